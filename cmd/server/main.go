@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/config"
 	"github.com/gilangrmdnii/invoice-backend/internal/database"
 	"github.com/gilangrmdnii/invoice-backend/internal/router"
+	"github.com/gilangrmdnii/invoice-backend/internal/scheduler"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
 	"github.com/gilangrmdnii/invoice-backend/migrations"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -48,15 +54,44 @@ func main() {
 		AllowHeaders:     "Origin,Content-Type,Authorization",
 		AllowCredentials: true,
 	}))
-	router.SetupRoutes(app, db, cfg)
+	sseHub := sse.NewHub()
+	router.SetupRoutes(app, db, cfg, sseHub)
+
+	// Background jobs
+	sched := scheduler.New(db)
+	if cfg.SchedulerEnabled {
+		if err := router.SetupJobs(sched, db, cfg, sseHub); err != nil {
+			log.Fatalf("failed to setup jobs: %v", err)
+		}
+		sched.Start()
+	}
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = cfg.AppPort
 	}
 
-	log.Printf("server starting on port %s", port)
-	if err := app.Listen(":" + port); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	go func() {
+		log.Printf("server starting on port %s", port)
+		if err := app.Listen(":" + port); err != nil {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+
+	// Graceful shutdown on SIGINT/SIGTERM
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+	log.Println("shutting down...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := app.ShutdownWithContext(ctx); err != nil {
+		log.Printf("server shutdown error: %v", err)
+	}
+	if err := sched.Stop(ctx); err != nil {
+		log.Printf("scheduler shutdown error: %v", err)
 	}
+	log.Println("server stopped")
 }
@@ -0,0 +1,289 @@
+package bankstatement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Header aliases of the CSV exports. Headers are compared lowercased with
+// surrounding spaces and dots removed.
+var (
+	dateHeaders        = []string{"tanggal transaksi", "tanggal", "tgl transaksi", "tgl_tran", "date", "post date", "transaction date", "tgl"}
+	descriptionHeaders = []string{"keterangan", "description", "desk_tran", "uraian", "remark", "remarks", "uraian transaksi", "transaction description"}
+	debitHeaders       = []string{"debit", "debet", "mutasi_debet", "mutasi debet", "debit amount"}
+	creditHeaders      = []string{"credit", "kredit", "mutasi_kredit", "mutasi kredit", "credit amount"}
+	amountHeaders      = []string{"jumlah", "amount", "mutasi", "nominal"}
+	flagHeaders        = []string{"db/cr", "d/k", "cr/db", "d/c", "dk"}
+	referenceHeaders   = []string{"reference no", "no referensi", "reference", "ref no", "journal no", "no ref"}
+	accountHeaders     = []string{"account no", "account number", "no rekening", "nomor rekening"}
+)
+
+var (
+	accountLine = regexp.MustCompile(`(?i)^(no\.?\s*rekening|nomor rekening|account no\.?|account number)\s*:?$`)
+	periodLine  = regexp.MustCompile(`(?i)^periode\s*:?$`)
+	periodRange = regexp.MustCompile(`(\d{2}/\d{2}/\d{4})\s*-\s*(\d{2}/\d{2}/\d{4})`)
+)
+
+type csvColumns struct {
+	date, reference, debit, credit, amount, flag, account int
+	description                                           []int
+}
+
+func parseCSV(data []byte) (*Statement, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = detectDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	st := &Statement{Format: FormatCSV}
+	var cols *csvColumns
+	line := 0
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		if cols == nil {
+			if c := detectColumns(record); c != nil {
+				cols = c
+				st.Format = detectCSVFormat(record)
+				continue
+			}
+			// Preamble lines before the header carry the account and period
+			readPreamble(st, record)
+			continue
+		}
+
+		t, ok, err := parseCSVRecord(record, cols, st.PeriodEnd)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if ok {
+			st.Transactions = append(st.Transactions, t)
+		}
+		// Mandiri repeats the account number on every row
+		if st.AccountNumber == "" && cols.account >= 0 && cols.account < len(record) {
+			st.AccountNumber = strings.TrimPrefix(strings.TrimSpace(record[cols.account]), "'")
+		}
+	}
+
+	if cols == nil {
+		return nil, fmt.Errorf("unrecognized statement format")
+	}
+	return st, nil
+}
+
+func detectDelimiter(data []byte) rune {
+	firstLines := data
+	if len(firstLines) > 4096 {
+		firstLines = firstLines[:4096]
+	}
+	if bytes.Count(firstLines, []byte(";")) > bytes.Count(firstLines, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlankRecord(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeHeader(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, ".", "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// readPreamble accepts "key,value", "key,:,value" and "key : value" lines.
+func readPreamble(st *Statement, record []string) {
+	var fields []string
+	for _, f := range record {
+		if f = strings.TrimSpace(f); f != "" && f != ":" {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 1 {
+		k, v, ok := strings.Cut(fields[0], ":")
+		if !ok {
+			return
+		}
+		fields = []string{k, v}
+	}
+	if len(fields) < 2 {
+		return
+	}
+	key := strings.TrimSpace(fields[0])
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(fields[1]), "'"))
+	switch {
+	case accountLine.MatchString(key):
+		st.AccountNumber = value
+	case periodLine.MatchString(key):
+		if m := periodRange.FindStringSubmatch(value); m != nil {
+			start, err1 := time.Parse("02/01/2006", m[1])
+			end, err2 := time.Parse("02/01/2006", m[2])
+			if err1 == nil && err2 == nil {
+				st.PeriodStart, st.PeriodEnd = &start, &end
+			}
+		}
+	}
+}
+
+// detectColumns returns the column layout when record is a header row with at
+// least a date, a description and an amount column.
+func detectColumns(record []string) *csvColumns {
+	c := &csvColumns{date: -1, reference: -1, debit: -1, credit: -1, amount: -1, flag: -1, account: -1}
+	for i, h := range record {
+		h = normalizeHeader(h)
+		switch {
+		case c.date < 0 && containsHeader(dateHeaders, h):
+			c.date = i
+		case containsHeader(descriptionHeaders, h):
+			c.description = append(c.description, i)
+		case c.debit < 0 && containsHeader(debitHeaders, h):
+			c.debit = i
+		case c.credit < 0 && containsHeader(creditHeaders, h):
+			c.credit = i
+		case c.amount < 0 && containsHeader(amountHeaders, h):
+			c.amount = i
+		case c.flag < 0 && containsHeader(flagHeaders, h):
+			c.flag = i
+		case c.reference < 0 && containsHeader(referenceHeaders, h):
+			c.reference = i
+		case c.account < 0 && containsHeader(accountHeaders, h):
+			c.account = i
+		}
+	}
+	if c.date < 0 || len(c.description) == 0 {
+		return nil
+	}
+	if c.amount < 0 && (c.debit < 0 || c.credit < 0) {
+		return nil
+	}
+	// BCA puts the CR/DB flag in an unnamed column right after the amount
+	if c.amount >= 0 && c.flag < 0 && c.amount+1 < len(record) && strings.TrimSpace(record[c.amount+1]) == "" {
+		c.flag = c.amount + 1
+	}
+	return c
+}
+
+func containsHeader(aliases []string, h string) bool {
+	for _, a := range aliases {
+		if h == a {
+			return true
+		}
+	}
+	return false
+}
+
+func detectCSVFormat(header []string) string {
+	has := func(name string) bool {
+		for _, h := range header {
+			if normalizeHeader(h) == name {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("tanggal transaksi") && has("cabang"):
+		return FormatBCA
+	case has("account no") && has("val date"):
+		return FormatMandiri
+	case has("post date") && has("journal no"):
+		return FormatBNI
+	case has("tgl_tran") || has("desk_tran"):
+		return FormatBRI
+	}
+	return FormatCSV
+}
+
+// parseCSVRecord converts a data row. ok is false for rows that are not
+// transactions, such as pending entries and balance or total lines.
+func parseCSVRecord(record []string, cols *csvColumns, periodEnd *time.Time) (Transaction, bool, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rawDate := field(cols.date)
+	if rawDate == "" || strings.EqualFold(strings.TrimPrefix(rawDate, "'"), "PEND") {
+		return Transaction{}, false, nil
+	}
+	date, err := parseDate(rawDate, periodEnd)
+	if err != nil {
+		// Trailing summary lines (saldo awal, mutasi, ...) have no date
+		return Transaction{}, false, nil
+	}
+
+	var parts []string
+	for _, i := range cols.description {
+		if v := field(i); v != "" {
+			parts = append(parts, v)
+		}
+	}
+	t := Transaction{
+		Date:        date,
+		Description: strings.Join(strings.Fields(strings.Join(parts, " ")), " "),
+		Reference:   field(cols.reference),
+	}
+
+	if cols.amount >= 0 {
+		amount, flag, err := parseAmount(field(cols.amount))
+		if err != nil {
+			return Transaction{}, false, err
+		}
+		if f := strings.ToUpper(field(cols.flag)); f != "" {
+			flag = f
+		}
+		switch flag {
+		case "CR", "C", "K":
+			t.Credit = true
+		case "DB", "D":
+			t.Credit = false
+		default:
+			t.Credit = amount > 0
+		}
+		t.Amount = amount.Abs()
+	} else {
+		debit, _, err := parseAmount(field(cols.debit))
+		if err != nil {
+			return Transaction{}, false, err
+		}
+		credit, _, err := parseAmount(field(cols.credit))
+		if err != nil {
+			return Transaction{}, false, err
+		}
+		if credit != 0 {
+			t.Credit = true
+			t.Amount = credit.Abs()
+		} else {
+			t.Amount = debit.Abs()
+		}
+	}
+
+	if t.Amount == 0 {
+		return Transaction{}, false, nil
+	}
+	return t, true, nil
+}
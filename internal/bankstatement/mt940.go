@@ -0,0 +1,136 @@
+package bankstatement
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+var (
+	mt940Tag = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	// :61: value date, optional entry date, (R)C/(R)D mark, optional funds
+	// code, amount, transaction type and customer reference
+	mt940Line    = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d{0,2})([NSF][A-Z0-9]{3})?([^/]*)(?://(.*))?$`)
+	mt940Balance = regexp.MustCompile(`^[CD](\d{6})[A-Z]{3}`)
+)
+
+func isMT940(data []byte) bool {
+	return bytes.Contains(data, []byte(":20:")) && bytes.Contains(data, []byte(":61:"))
+}
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+func parseMT940(data []byte) (*Statement, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if m := mt940Tag.FindStringSubmatch(line); m != nil {
+			fields = append(fields, mt940Field{tag: m[1], value: m[2]})
+			continue
+		}
+		// Continuation of the previous field; "-" ends a message
+		if len(fields) > 0 && strings.TrimSpace(line) != "-" && strings.TrimSpace(line) != "" {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	st := &Statement{Format: FormatMT940}
+	var current *Transaction
+	flush := func() {
+		if current != nil {
+			current.Description = strings.Join(strings.Fields(current.Description), " ")
+			st.Transactions = append(st.Transactions, *current)
+			current = nil
+		}
+	}
+
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			if st.AccountNumber == "" {
+				st.AccountNumber = strings.TrimSpace(f.value)
+			}
+		case "60F", "60M":
+			if d, ok := mt940BalanceDate(f.value); ok && st.PeriodStart == nil {
+				st.PeriodStart = &d
+			}
+		case "62F", "62M":
+			if d, ok := mt940BalanceDate(f.value); ok {
+				st.PeriodEnd = &d
+			}
+		case "61":
+			flush()
+			t, err := parseMT940Line(f.value)
+			if err != nil {
+				return nil, err
+			}
+			current = &t
+		case "86":
+			if current != nil {
+				current.Description = strings.TrimSpace(current.Description + " " + strings.ReplaceAll(f.value, "\n", " "))
+			}
+		}
+	}
+	flush()
+
+	if st.AccountNumber == "" && len(st.Transactions) == 0 {
+		return nil, fmt.Errorf("unrecognized statement format")
+	}
+	return st, nil
+}
+
+func parseMT940Line(value string) (Transaction, error) {
+	first, supplementary, _ := strings.Cut(value, "\n")
+	m := mt940Line.FindStringSubmatch(strings.TrimSpace(first))
+	if m == nil {
+		return Transaction{}, fmt.Errorf("invalid MT940 statement line %q", first)
+	}
+
+	date, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid MT940 date %q", m[1])
+	}
+	amount, err := money.Parse(strings.Replace(m[5], ",", ".", 1))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid MT940 amount %q", m[5])
+	}
+
+	t := Transaction{
+		Date:   date,
+		Amount: amount,
+		// A reversal of a debit (RD) brings money back into the account
+		Credit:      m[3] == "C" || m[3] == "RD",
+		Description: strings.TrimSpace(supplementary),
+	}
+	ref := strings.TrimSpace(m[7])
+	if ref == "" || ref == "NONREF" {
+		ref = strings.TrimSpace(m[8])
+	}
+	t.Reference = ref
+	return t, nil
+}
+
+func mt940BalanceDate(value string) (time.Time, bool) {
+	m := mt940Balance.FindStringSubmatch(strings.TrimSpace(value))
+	if m == nil {
+		return time.Time{}, false
+	}
+	d, err := time.Parse("060102", m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return d, true
+}
@@ -0,0 +1,77 @@
+// Package bankstatement parses bank account statements exported from internet
+// banking: CSV exports of the common Indonesian banks and SWIFT MT940 files.
+package bankstatement
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// Formats reported by Parse.
+const (
+	FormatBCA     = "BCA"
+	FormatMandiri = "MANDIRI"
+	FormatBNI     = "BNI"
+	FormatBRI     = "BRI"
+	FormatCSV     = "CSV"
+	FormatMT940   = "MT940"
+)
+
+// Transaction is a single statement line. Amount is always positive; Credit
+// tells whether money came into the account.
+type Transaction struct {
+	Date        time.Time
+	Description string
+	Reference   string
+	Amount      money.Money
+	Credit      bool
+}
+
+// Statement is a parsed statement file.
+type Statement struct {
+	Format        string
+	AccountNumber string
+	PeriodStart   *time.Time
+	PeriodEnd     *time.Time
+	Transactions  []Transaction
+}
+
+// Parse detects the format of a statement file and parses it.
+func Parse(data []byte) (*Statement, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("statement file is empty")
+	}
+
+	var st *Statement
+	var err error
+	if isMT940(data) {
+		st, err = parseMT940(data)
+	} else {
+		st, err = parseCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(st.Transactions) == 0 {
+		return nil, fmt.Errorf("statement has no transactions")
+	}
+
+	// Fall back to the transaction dates when the file has no period
+	if st.PeriodStart == nil || st.PeriodEnd == nil {
+		start, end := st.Transactions[0].Date, st.Transactions[0].Date
+		for _, t := range st.Transactions[1:] {
+			if t.Date.Before(start) {
+				start = t.Date
+			}
+			if t.Date.After(end) {
+				end = t.Date
+			}
+		}
+		st.PeriodStart, st.PeriodEnd = &start, &end
+	}
+	return st, nil
+}
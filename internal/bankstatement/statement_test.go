@@ -0,0 +1,229 @@
+package bankstatement
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseFixtures(t *testing.T) {
+	tests := []struct {
+		file        string
+		format      string
+		account     string
+		periodStart time.Time
+		periodEnd   time.Time
+		want        []Transaction
+	}{
+		{
+			// Short dates across the year boundary, the unnamed CR/DB column,
+			// a pending row and the trailing summary lines
+			file:        "bca.csv",
+			format:      FormatBCA,
+			account:     "0123456789",
+			periodStart: date(2025, 12, 15),
+			periodEnd:   date(2026, 1, 15),
+			want: []Transaction{
+				{Date: date(2025, 12, 20), Description: "TRSF E-BANKING CR 2012/FTSCY/WS95031 PT KLIEN INV-001", Amount: 1_500_000_00, Credit: true},
+				{Date: date(2026, 1, 2), Description: "BIAYA ADM", Amount: 15_000_00},
+			},
+		},
+		{
+			// Semicolons, Indonesian separators, two description columns and
+			// the account number on every row
+			file:        "mandiri.csv",
+			format:      FormatMandiri,
+			account:     "1230004567890",
+			periodStart: date(2026, 1, 5),
+			periodEnd:   date(2026, 1, 6),
+			want: []Transaction{
+				{Date: date(2026, 1, 5), Description: "TRANSFER DARI PT KLIEN INV-002", Reference: "REF123", Amount: 2_750_000_00, Credit: true},
+				{Date: date(2026, 1, 6), Description: "BIAYA ADM", Amount: 12_500_00},
+			},
+		},
+		{
+			file:        "bni.csv",
+			format:      FormatBNI,
+			account:     "0987654321",
+			periodStart: date(2026, 1, 1),
+			periodEnd:   date(2026, 1, 31),
+			want: []Transaction{
+				{Date: date(2026, 1, 10), Description: "TRANSFER PT KLIEN INV-003", Reference: "J00123", Amount: 3_000_000_00, Credit: true},
+				{Date: date(2026, 1, 11), Description: "PEMBELIAN MATERIAL", Reference: "J00124", Amount: 450_000_00},
+			},
+		},
+		{
+			file:        "bri.csv",
+			format:      FormatBRI,
+			periodStart: date(2026, 1, 8),
+			periodEnd:   date(2026, 1, 9),
+			want: []Transaction{
+				{Date: date(2026, 1, 8), Description: "NBMB PT KLIEN TO PT CONTOH INV-004", Amount: 3_500_000_00, Credit: true},
+				{Date: date(2026, 1, 9), Description: "BIAYA TRANSFER", Amount: 6_500_00},
+			},
+		},
+		{
+			// Single amount column with "K"/"D" suffixes
+			file:        "generic.csv",
+			format:      FormatCSV,
+			periodStart: date(2026, 1, 12),
+			periodEnd:   date(2026, 1, 13),
+			want: []Transaction{
+				{Date: date(2026, 1, 12), Description: "SETORAN TUNAI", Amount: 500_000_00, Credit: true},
+				{Date: date(2026, 1, 13), Description: "TARIK TUNAI", Amount: 200_000_00},
+			},
+		},
+		{
+			// Entry dates, supplementary details, NONREF and an RD reversal
+			file:        "statement.mt940",
+			format:      FormatMT940,
+			account:     "0123456789",
+			periodStart: date(2025, 12, 31),
+			periodEnd:   date(2026, 1, 31),
+			want: []Transaction{
+				{Date: date(2026, 1, 5), Description: "TRANSFER MASUK TRANSFER DARI PT KLIEN INV-001", Reference: "INV-001", Amount: 1_500_000_00, Credit: true},
+				{Date: date(2026, 1, 6), Description: "BIAYA ADMIN", Reference: "ADM0106", Amount: 25_000_00},
+				{Date: date(2026, 1, 7), Description: "KOREKSI BIAYA ADMIN", Amount: 25_000_00, Credit: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			st, err := Parse(data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if st.Format != tt.format {
+				t.Errorf("Format = %q, want %q", st.Format, tt.format)
+			}
+			if st.AccountNumber != tt.account {
+				t.Errorf("AccountNumber = %q, want %q", st.AccountNumber, tt.account)
+			}
+			if !st.PeriodStart.Equal(tt.periodStart) || !st.PeriodEnd.Equal(tt.periodEnd) {
+				t.Errorf("period = %s - %s, want %s - %s", st.PeriodStart.Format(time.DateOnly), st.PeriodEnd.Format(time.DateOnly),
+					tt.periodStart.Format(time.DateOnly), tt.periodEnd.Format(time.DateOnly))
+			}
+			if len(st.Transactions) != len(tt.want) {
+				t.Fatalf("got %d transactions, want %d: %+v", len(st.Transactions), len(tt.want), st.Transactions)
+			}
+			for i, got := range st.Transactions {
+				want := tt.want[i]
+				if !got.Date.Equal(want.Date) || got.Description != want.Description || got.Reference != want.Reference ||
+					got.Amount != want.Amount || got.Credit != want.Credit {
+					t.Errorf("transaction %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", "\xef\xbb\xbf \n"},
+		{"no header", "foo,bar\n1,2\n"},
+		{"no transactions", "Tanggal,Keterangan,Jumlah\n"},
+		{"bad amount", "Tanggal,Keterangan,Jumlah\n01/01/2026,X,1.2.3x\n"},
+		{"bad MT940 line", ":20:X\n:25:1\n:61:garbage\n"},
+	}
+	for _, tt := range tests {
+		if st, err := Parse([]byte(tt.data)); err == nil {
+			t.Errorf("%s: Parse = %+v, want error", tt.name, st)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    money.Money
+		flag    string
+		wantErr bool
+	}{
+		{in: "1.500.000,00", want: 1_500_000_00},
+		{in: "1,500,000.00", want: 1_500_000_00},
+		{in: "Rp 1.500.000", want: 1_500_000_00},
+		{in: "IDR 12,34", want: 12_34},
+		{in: "2.750.000,00 K", want: 2_750_000_00, flag: "CR"},
+		{in: "25.000,00 D", want: 25_000_00, flag: "DB"},
+		{in: "100.00 CR", want: 100_00, flag: "CR"},
+		{in: "100.00DB", want: 100_00, flag: "DB"},
+		{in: "(1,000.00)", want: -1_000_00},
+		{in: "-5,5", want: -5_50},
+		{in: "", want: 0},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, flag, err := parseAmount(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAmount(%q) = %d, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseAmount(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want || flag != tt.flag {
+			t.Errorf("parseAmount(%q) = %d, %q, want %d, %q", tt.in, got, flag, tt.want, tt.flag)
+		}
+	}
+}
+
+func TestNormalizeDecimal(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"1.234.567,89", "1234567.89"},
+		{"1,234,567.89", "1234567.89"},
+		{"1.234", "1234"},
+		{"1,234", "1234"},
+		{"1,5", "1.5"},
+		{"0,05", "0.05"},
+		{"1234", "1234"},
+	}
+	for _, tt := range tests {
+		if got := normalizeDecimal(tt.in); got != tt.want {
+			t.Errorf("normalizeDecimal(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateShortAcrossYear(t *testing.T) {
+	periodEnd := date(2026, 1, 15)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"'28/12", date(2025, 12, 28)},
+		{"03/01", date(2026, 1, 3)},
+		{"15/01/2026", date(2026, 1, 15)},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.in, &periodEnd)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %s, want %s", tt.in, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+	if _, err := parseDate("03/01", nil); err == nil {
+		t.Errorf("parseDate without period: want error")
+	}
+}
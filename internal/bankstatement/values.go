@@ -0,0 +1,116 @@
+package bankstatement
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+var dateLayouts = []string{
+	"02/01/2006",
+	"02/01/06",
+	"2006-01-02",
+	"02-01-2006",
+	"02-01-06",
+	"2006/01/02",
+	"02 Jan 2006",
+	"02-Jan-2006",
+	"02-Jan-06",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/06 15.04.05",
+	"2006-01-02 15:04:05",
+}
+
+// parseDate parses a transaction date. Short "dd/mm" dates, as used by BCA,
+// take their year from the statement period.
+func parseDate(s string, periodEnd *time.Time) (time.Time, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "'"))
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return truncateDay(t), nil
+		}
+	}
+
+	if t, err := time.Parse("02/01", s); err == nil && periodEnd != nil {
+		year := periodEnd.Year()
+		if t.Month() > periodEnd.Month() {
+			// Statement spans the turn of the year
+			year--
+		}
+		return time.Date(year, t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// parseAmount parses an amount written with either Indonesian (1.500.000,00) or
+// English (1,500,000.00) separators, optionally with a currency prefix. The
+// returned flag is "CR", "DB" or "" when the amount carries a credit/debit
+// suffix. Negative amounts are returned as negative.
+func parseAmount(s string) (money.Money, string, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "IDR")
+	s = strings.TrimPrefix(s, "RP")
+	s = strings.TrimSpace(s)
+
+	flag := ""
+	for _, suffix := range []string{"CR", "DB", "D", "K", "C"} {
+		if strings.HasSuffix(s, suffix) {
+			flag = suffix
+			s = strings.TrimSpace(strings.TrimSuffix(s, suffix))
+			break
+		}
+	}
+	switch flag {
+	case "K", "C":
+		flag = "CR"
+	case "D":
+		flag = "DB"
+	}
+
+	neg := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		neg = true
+		s = s[1 : len(s)-1]
+	}
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = s[1:]
+	}
+	s = strings.ReplaceAll(s, " ", "")
+	if s == "" {
+		return 0, flag, nil
+	}
+
+	s = normalizeDecimal(s)
+	m, err := money.Parse(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid amount %q", s)
+	}
+	if neg {
+		m = -m
+	}
+	return m, flag, nil
+}
+
+// normalizeDecimal rewrites a number with thousand separators to a plain
+// decimal with a dot. The last separator is the decimal one when it is
+// followed by one or two digits.
+func normalizeDecimal(s string) string {
+	last := strings.LastIndexAny(s, ".,")
+	if last < 0 {
+		return s
+	}
+	decimals := len(s) - last - 1
+	if decimals >= 1 && decimals <= 2 {
+		whole := strings.NewReplacer(".", "", ",", "").Replace(s[:last])
+		return whole + "." + s[last+1:]
+	}
+	return strings.NewReplacer(".", "", ",", "").Replace(s)
+}
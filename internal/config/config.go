@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +19,22 @@ type Config struct {
 	DBURL        string
 	JWTSecret    string
 	JWTExpiryHrs int
+
+	SchedulerEnabled    bool
+	OverdueReminderCron string
+	OverdueReminderDays []int
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPFromName string
+	SMTPTLS      bool // implicit TLS (SMTPS), as on port 465
+
+	PaymentProvider      string
+	PaymentWebhookSecret string
+	PaymentChargeHours   int
 }
 
 func Load() (*Config, error) {
@@ -25,8 +43,10 @@ func Load() (*Config, error) {
 	}
 
 	expiryHrs, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
+	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	chargeHours, _ := strconv.Atoi(getEnv("PAYMENT_CHARGE_EXPIRY_HOURS", "24"))
 
-	return &Config{
+	cfg := &Config{
 		AppPort:      getEnv("APP_PORT", "3000"),
 		DBHost:       getEnvMulti([]string{"DB_HOST", "MYSQLHOST"}, "localhost"),
 		DBPort:       getEnvMulti([]string{"DB_PORT", "MYSQLPORT"}, "3306"),
@@ -36,7 +56,42 @@ func Load() (*Config, error) {
 		DBURL:        getEnv("MYSQL_URL", ""),
 		JWTSecret:    getEnv("JWT_SECRET", ""),
 		JWTExpiryHrs: expiryHrs,
-	}, nil
+
+		SchedulerEnabled:    getEnv("SCHEDULER_ENABLED", "true") == "true",
+		OverdueReminderCron: getEnv("OVERDUE_REMINDER_CRON", "0 8 * * *"),
+		OverdueReminderDays: parseIntList(getEnv("OVERDUE_REMINDER_DAYS", "1,7,14,30")),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     smtpPort,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", ""),
+		SMTPFromName: getEnv("SMTP_FROM_NAME", ""),
+		SMTPTLS:      getEnv("SMTP_TLS", "false") == "true",
+
+		PaymentProvider:      getEnv("PAYMENT_PROVIDER", ""),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		PaymentChargeHours:   chargeHours,
+	}
+
+	// Webhooks are public and only trusted through their signature, which is
+	// worthless without a secret
+	if cfg.PaymentProvider != "" && cfg.PaymentWebhookSecret == "" {
+		return nil, fmt.Errorf("PAYMENT_WEBHOOK_SECRET is required when PAYMENT_PROVIDER is set")
+	}
+	return cfg, nil
+}
+
+// parseIntList parses a comma-separated list of positive integers, skipping invalid entries.
+func parseIntList(s string) []int {
+	var out []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && n > 0 {
+			out = append(out, n)
+		}
+	}
+	return out
 }
 
 func getEnv(key, fallback string) string {
@@ -0,0 +1,22 @@
+package request
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+type ApprovalPolicyStepRequest struct {
+	ApproverRoles []string `json:"approver_roles" validate:"required,min=1,dive,oneof=FINANCE OWNER"`
+}
+
+type CreateApprovalPolicyRequest struct {
+	Name        string                      `json:"name" validate:"required,min=2,max=255"`
+	InvoiceType string                      `json:"invoice_type" validate:"omitempty,oneof=DP FINAL_PAYMENT TOP_1 TOP_2 TOP_3 MEALS ADDITIONAL"`
+	MinAmount   money.Money                 `json:"min_amount" validate:"gte=0"`
+	Steps       []ApprovalPolicyStepRequest `json:"steps" validate:"required,min=1,max=5,dive"`
+}
+
+type UpdateApprovalPolicyRequest struct {
+	Name        string                      `json:"name" validate:"omitempty,min=2,max=255"`
+	InvoiceType *string                     `json:"invoice_type" validate:"omitempty,oneof='' DP FINAL_PAYMENT TOP_1 TOP_2 TOP_3 MEALS ADDITIONAL"` // "" applies to all types
+	MinAmount   *money.Money                `json:"min_amount" validate:"omitempty,gte=0"`
+	IsActive    *bool                       `json:"is_active"`
+	Steps       []ApprovalPolicyStepRequest `json:"steps" validate:"omitempty,min=1,max=5,dive"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type BankMatchRequest struct {
+	TransactionID uint64 `json:"transaction_id" validate:"required"`
+	InvoiceID     uint64 `json:"invoice_id" validate:"required"`
+	Notes         string `json:"notes" validate:"max=2000"`
+}
+
+type ConfirmBankMatchesRequest struct {
+	Matches []BankMatchRequest `json:"matches" validate:"required,min=1,max=200,dive"`
+}
@@ -0,0 +1,31 @@
+package request
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+type BillingTermRequest struct {
+	ID           uint64  `json:"id"`
+	InvoiceType  string  `json:"invoice_type" validate:"required,oneof=DP TOP_1 TOP_2 TOP_3 FINAL_PAYMENT"`
+	Description  string  `json:"description" validate:"max=500"`
+	Percentage   float64 `json:"percentage" validate:"required,gt=0,lte=100"`
+	ExpectedDate string  `json:"expected_date"`
+}
+
+type SaveBillingScheduleRequest struct {
+	ContractValue money.Money          `json:"contract_value" validate:"required,gt=0"`
+	PPNPercentage float64              `json:"ppn_percentage" validate:"gte=0,lte=100"`
+	PPHPercentage float64              `json:"pph_percentage" validate:"gte=0,lte=100"`
+	Notes         string               `json:"notes" validate:"max=2000"`
+	Terms         []BillingTermRequest `json:"terms" validate:"omitempty,dive"`
+}
+
+type GenerateTermInvoiceRequest struct {
+	ClientID         *uint64 `json:"client_id"`
+	RecipientName    string  `json:"recipient_name" validate:"max=255"`
+	RecipientAddress string  `json:"recipient_address" validate:"max=1000"`
+	Attention        string  `json:"attention" validate:"max=255"`
+	PONumber         string  `json:"po_number" validate:"max=100"`
+	InvoiceDate      string  `json:"invoice_date"`
+	DueDate          string  `json:"due_date"`
+	Notes            string  `json:"notes" validate:"max=2000"`
+	Language         string  `json:"language" validate:"omitempty,oneof=ID EN"`
+}
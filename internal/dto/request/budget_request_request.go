@@ -1,10 +1,12 @@
 package request
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
 type CreateBudgetRequestRequest struct {
-	ProjectID uint64  `json:"project_id" validate:"required"`
-	Amount    float64 `json:"amount" validate:"required,gt=0"`
-	Reason    string  `json:"reason" validate:"required,min=2,max=1000"`
-	ProofURL  string  `json:"proof_url" validate:"required"`
+	ProjectID uint64      `json:"project_id" validate:"required"`
+	Amount    money.Money `json:"amount" validate:"required,gt=0"`
+	Reason    string      `json:"reason" validate:"required,min=2,max=1000"`
+	ProofURL  string      `json:"proof_url" validate:"required"`
 }
 
 type ApproveBudgetRequestRequest struct {
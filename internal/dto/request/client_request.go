@@ -0,0 +1,32 @@
+package request
+
+type ClientContactRequest struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	Position  string `json:"position" validate:"max=100"`
+	Email     string `json:"email" validate:"omitempty,email,max=255"`
+	Phone     string `json:"phone" validate:"max=50"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type CreateClientRequest struct {
+	Name             string                 `json:"name" validate:"required,min=2,max=255"`
+	NPWP             string                 `json:"npwp" validate:"max=30"`
+	BillingAddress   string                 `json:"billing_address" validate:"max=1000"`
+	Email            string                 `json:"email" validate:"omitempty,email,max=255"`
+	Phone            string                 `json:"phone" validate:"max=50"`
+	PaymentTermsDays *int                   `json:"payment_terms_days" validate:"omitempty,gte=0,lte=365"`
+	Notes            string                 `json:"notes" validate:"max=2000"`
+	Contacts         []ClientContactRequest `json:"contacts" validate:"omitempty,dive"`
+}
+
+type UpdateClientRequest struct {
+	Name             string                 `json:"name" validate:"omitempty,min=2,max=255"`
+	NPWP             *string                `json:"npwp" validate:"omitempty,max=30"`
+	BillingAddress   *string                `json:"billing_address" validate:"omitempty,max=1000"`
+	Email            *string                `json:"email" validate:"omitempty,email,max=255"`
+	Phone            *string                `json:"phone" validate:"omitempty,max=50"`
+	PaymentTermsDays *int                   `json:"payment_terms_days" validate:"omitempty,gte=0,lte=365"`
+	Notes            *string                `json:"notes" validate:"omitempty,max=2000"`
+	IsActive         *bool                  `json:"is_active"`
+	Contacts         []ClientContactRequest `json:"contacts" validate:"omitempty,dive"`
+}
@@ -0,0 +1,20 @@
+package request
+
+// CreateCommentRequest adds a comment to an invoice, budget request, expense
+// or QC report. A reply gives the id of a comment in the thread as parent_id.
+// Mentions appear in the body as @Full Name and mention_ids lists the users
+// they refer to. Attachments are file URLs returned by the upload endpoint.
+type CreateCommentRequest struct {
+	EntityType  string   `json:"entity_type" validate:"required,oneof=INVOICE BUDGET_REQUEST EXPENSE QC_REPORT"`
+	EntityID    uint64   `json:"entity_id" validate:"required"`
+	ParentID    *uint64  `json:"parent_id"`
+	Body        string   `json:"body" validate:"required,min=1,max=5000"`
+	Attachments []string `json:"attachments" validate:"omitempty,max=10,dive,startswith=/uploads/,max=500"`
+	MentionIDs  []uint64 `json:"mention_ids" validate:"omitempty,max=20"`
+}
+
+// ListCommentsRequest holds the query parameters of GET /api/comments.
+type ListCommentsRequest struct {
+	EntityType string `query:"entity_type" validate:"required,oneof=INVOICE BUDGET_REQUEST EXPENSE QC_REPORT"`
+	EntityID   uint64 `query:"entity_id" validate:"required"`
+}
@@ -1,17 +1,27 @@
 package request
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
 type UpsertCompanySettingsRequest struct {
-	CompanyName       string `json:"company_name" validate:"required,max=255"`
-	CompanyCode       string `json:"company_code" validate:"required,max=10"`
-	Address           string `json:"address" validate:"max=1000"`
-	Phone             string `json:"phone" validate:"max=50"`
-	Email             string `json:"email" validate:"omitempty,email,max=255"`
-	NPWP              string `json:"npwp" validate:"max=50"`
-	BankName          string `json:"bank_name" validate:"max=100"`
-	BankAccountNumber string `json:"bank_account_number" validate:"max=50"`
-	BankAccountName   string `json:"bank_account_name" validate:"max=255"`
-	BankBranch        string `json:"bank_branch" validate:"max=255"`
-	LogoURL           string `json:"logo_url" validate:"omitempty,max=500"`
-	SignatoryName     string `json:"signatory_name" validate:"max=255"`
-	SignatoryTitle    string `json:"signatory_title" validate:"max=255"`
+	CompanyName             string `json:"company_name" validate:"required,max=255"`
+	CompanyCode             string `json:"company_code" validate:"required,max=10"`
+	Address                 string `json:"address" validate:"max=1000"`
+	Phone                   string `json:"phone" validate:"max=50"`
+	Email                   string `json:"email" validate:"omitempty,email,max=255"`
+	NPWP                    string `json:"npwp" validate:"max=50"`
+	BankName                string `json:"bank_name" validate:"max=100"`
+	BankAccountNumber       string `json:"bank_account_number" validate:"max=50"`
+	BankAccountName         string `json:"bank_account_name" validate:"max=255"`
+	BankBranch              string `json:"bank_branch" validate:"max=255"`
+	LogoURL                 string `json:"logo_url" validate:"omitempty,max=500"`
+	SignatoryName           string `json:"signatory_name" validate:"max=255"`
+	SignatoryTitle          string `json:"signatory_title" validate:"max=255"`
+	InvoiceNumberPattern    string `json:"invoice_number_pattern" validate:"omitempty,max=100"`
+	CreditNoteNumberPattern string `json:"credit_note_number_pattern" validate:"omitempty,max=100"`
+	ReceiptNumberPattern    string `json:"receipt_number_pattern" validate:"omitempty,max=100"`
+	// StampDutyThreshold defaults to Rp5.000.000 when omitted
+	StampDutyThreshold *money.Money `json:"stamp_duty_threshold" validate:"omitempty,gte=0"`
+	// Defaults for new invoices; the mode defaults to STANDARD
+	DefaultPPNMode       string  `json:"default_ppn_mode" validate:"omitempty,oneof=STANDARD DPP_NILAI_LAIN"`
+	DefaultPPNPercentage float64 `json:"default_ppn_percentage" validate:"gte=0,lte=100"`
 }
@@ -0,0 +1,7 @@
+package request
+
+type CreateCreditNoteRequest struct {
+	CreditDate string               `json:"credit_date" validate:"required"`
+	Reason     string               `json:"reason" validate:"required,min=5,max=2000"`
+	Items      []InvoiceItemRequest `json:"items" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,16 @@
+package request
+
+type CreateExpenseCategoryRequest struct {
+	Code           string `json:"code" validate:"required,max=100"`
+	LabelID        string `json:"label_id" validate:"required,max=255"`
+	LabelEN        string `json:"label_en" validate:"required,max=255"`
+	ReportCategory string `json:"report_category" validate:"required,oneof=SPV UANG_MAKAN PULSA RECORDING INPUT_PERPI BENSIN BRIEFING TRANSPORT LAIN_LAIN"`
+	IsActive       *bool  `json:"is_active"`
+}
+
+type UpdateExpenseCategoryRequest struct {
+	LabelID        string `json:"label_id" validate:"omitempty,max=255"`
+	LabelEN        string `json:"label_en" validate:"omitempty,max=255"`
+	ReportCategory string `json:"report_category" validate:"omitempty,oneof=SPV UANG_MAKAN PULSA RECORDING INPUT_PERPI BENSIN BRIEFING TRANSPORT LAIN_LAIN"`
+	IsActive       *bool  `json:"is_active"`
+}
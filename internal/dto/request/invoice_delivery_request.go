@@ -0,0 +1,8 @@
+package request
+
+type SendInvoiceRequest struct {
+	To      []string `json:"to" validate:"required,min=1,dive,email"`
+	Cc      []string `json:"cc" validate:"omitempty,dive,email"`
+	Subject string   `json:"subject" validate:"max=255"`
+	Message string   `json:"message" validate:"max=2000"`
+}
@@ -0,0 +1,15 @@
+package request
+
+// EFakturExportRequest holds the query parameters of GET
+// /api/invoices/efaktur. Dates use YYYY-MM-DD and the range is inclusive.
+type EFakturExportRequest struct {
+	DateFrom string `query:"date_from" validate:"required,datetime=2006-01-02"`
+	DateTo   string `query:"date_to" validate:"required,datetime=2006-01-02"`
+	Format   string `query:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// SetTaxInvoiceNumberRequest sets the tax invoice number (NSFP) of an invoice.
+// Separators are ignored; an empty number clears it.
+type SetTaxInvoiceNumberRequest struct {
+	TaxInvoiceNumber string `json:"tax_invoice_number" validate:"max=30"`
+}
@@ -0,0 +1,20 @@
+package request
+
+// ListInvoicesRequest holds the query parameters of GET /api/invoices.
+// Dates use YYYY-MM-DD and ranges are inclusive.
+type ListInvoicesRequest struct {
+	Status        string `query:"status" validate:"omitempty,oneof=PENDING APPROVED REJECTED VOID"`
+	PaymentStatus string `query:"payment_status" validate:"omitempty,oneof=UNPAID PARTIAL_PAID PAID"`
+	InvoiceType   string `query:"invoice_type" validate:"omitempty,oneof=DP FINAL_PAYMENT TOP_1 TOP_2 TOP_3 MEALS ADDITIONAL"`
+	ProjectID     uint64 `query:"project_id"`
+	DateFrom      string `query:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo        string `query:"date_to" validate:"omitempty,datetime=2006-01-02"`
+	DueFrom       string `query:"due_from" validate:"omitempty,datetime=2006-01-02"`
+	DueTo         string `query:"due_to" validate:"omitempty,datetime=2006-01-02"`
+	Recipient     string `query:"recipient" validate:"max=255"`
+	Search        string `query:"search" validate:"max=100"` // invoice number or PO number
+	Sort          string `query:"sort" validate:"omitempty,oneof=created_at invoice_date due_date amount invoice_number"`
+	Order         string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit"`
+}
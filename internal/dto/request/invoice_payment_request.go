@@ -1,10 +1,45 @@
 package request
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
 type CreateInvoicePaymentRequest struct {
-	InvoiceID     uint64  `json:"invoice_id" validate:"required"`
-	Amount        float64 `json:"amount" validate:"required,gt=0"`
-	PaymentDate   string  `json:"payment_date" validate:"required"`
-	PaymentMethod string  `json:"payment_method" validate:"required,oneof=TRANSFER CASH GIRO OTHER"`
-	ProofURL      string  `json:"proof_url" validate:"omitempty,max=500"`
-	Notes         string  `json:"notes" validate:"max=2000"`
+	InvoiceID     uint64      `json:"invoice_id" validate:"required"`
+	Amount        money.Money `json:"amount" validate:"required,gt=0"`
+	PaymentDate   string      `json:"payment_date" validate:"required"`
+	PaymentMethod string      `json:"payment_method" validate:"required,oneof=TRANSFER CASH GIRO OTHER"`
+	ProofURL      string      `json:"proof_url" validate:"omitempty,max=500"`
+	Notes         string      `json:"notes" validate:"max=2000"`
+}
+
+type ReverseInvoicePaymentRequest struct {
+	ReversalDate string `json:"reversal_date" validate:"required"`
+	Reason       string `json:"reason" validate:"required,min=5,max=2000"`
+	ProofURL     string `json:"proof_url" validate:"omitempty,max=500"`
+	Notes        string `json:"notes" validate:"max=2000"`
+}
+
+type CreateInvoiceRefundRequest struct {
+	Amount        money.Money `json:"amount" validate:"required,gt=0"`
+	RefundDate    string      `json:"refund_date" validate:"required"`
+	PaymentMethod string      `json:"payment_method" validate:"required,oneof=TRANSFER CASH GIRO OTHER"`
+	Reason        string      `json:"reason" validate:"required,min=5,max=2000"`
+	ProofURL      string      `json:"proof_url" validate:"omitempty,max=500"`
+	Notes         string      `json:"notes" validate:"max=2000"`
+}
+
+type CreatePaymentChargeRequest struct {
+	Method string      `json:"method" validate:"required,oneof=VIRTUAL_ACCOUNT QRIS"`
+	Bank   string      `json:"bank" validate:"omitempty,oneof=BCA BNI BRI MANDIRI PERMATA"`
+	Amount money.Money `json:"amount" validate:"omitempty,gt=0"` // defaults to the outstanding balance
+}
+
+type SimulatePaymentChargeRequest struct {
+	Status string      `json:"status" validate:"omitempty,oneof=PAID EXPIRED FAILED"`
+	Amount money.Money `json:"amount" validate:"omitempty,gt=0"` // defaults to the charge amount
+}
+
+// ListPaymentWebhookEventsRequest holds the query parameters of GET
+// /api/payment-webhook-events.
+type ListPaymentWebhookEventsRequest struct {
+	Result string `query:"result" validate:"omitempty,oneof=RECEIVED PROCESSED IGNORED FAILED"`
 }
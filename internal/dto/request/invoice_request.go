@@ -1,10 +1,19 @@
 package request
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+// InvoiceItemRequest is an invoice line. A line is discounted by either
+// discount_percent or a fixed discount_amount. ppn_code defaults to STANDARD
+// and pph_code to PPH23.
 type InvoiceItemRequest struct {
-	Description string  `json:"description" validate:"required,min=1,max=500"`
-	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
-	Unit        string  `json:"unit" validate:"required,max=50"`
-	UnitPrice   float64 `json:"unit_price" validate:"required,gt=0"`
+	Description     string      `json:"description" validate:"required,min=1,max=500"`
+	Quantity        float64     `json:"quantity" validate:"required,gt=0"`
+	Unit            string      `json:"unit" validate:"required,max=50"`
+	UnitPrice       money.Money `json:"unit_price" validate:"required,gt=0"`
+	DiscountPercent float64     `json:"discount_percent" validate:"gte=0,lte=100"`
+	DiscountAmount  money.Money `json:"discount_amount" validate:"gte=0"`
+	PPNCode         string      `json:"ppn_code" validate:"omitempty,oneof=STANDARD EXEMPT"`
+	PPHCode         string      `json:"pph_code" validate:"omitempty,oneof=PPH23 PPH21 NONE"`
 }
 
 type InvoiceLabelRequest struct {
@@ -15,15 +24,22 @@ type InvoiceLabelRequest struct {
 type CreateInvoiceRequest struct {
 	ProjectID        uint64               `json:"project_id" validate:"required"`
 	InvoiceType      string               `json:"invoice_type" validate:"required,oneof=DP FINAL_PAYMENT TOP_1 TOP_2 TOP_3 MEALS ADDITIONAL"`
-	RecipientName    string               `json:"recipient_name" validate:"required,max=255"`
+	ClientID         *uint64              `json:"client_id"`
+	RecipientName    string               `json:"recipient_name" validate:"max=255"`
 	RecipientAddress string               `json:"recipient_address" validate:"max=1000"`
+	RecipientNPWP    string               `json:"recipient_npwp" validate:"max=30"`
 	Attention        string               `json:"attention" validate:"max=255"`
 	PONumber         string               `json:"po_number" validate:"max=100"`
 	InvoiceDate      string               `json:"invoice_date" validate:"required"`
 	DueDate          string               `json:"due_date" validate:"omitempty"`
 	DPPercentage  *float64 `json:"dp_percentage" validate:"omitempty,gte=0,lte=100"`
-	PPNPercentage float64 `json:"ppn_percentage" validate:"gte=0,lte=100"`
-	PPHPercentage float64 `json:"pph_percentage" validate:"gte=0,lte=100"`
+	DiscountPercent float64 `json:"discount_percent" validate:"gte=0,lte=100"`
+	DiscountAmount money.Money `json:"discount_amount" validate:"gte=0"`
+	// PPN mode and rate default to the company settings when omitted
+	PPNMode       string   `json:"ppn_mode" validate:"omitempty,oneof=STANDARD DPP_NILAI_LAIN"`
+	PPNPercentage *float64 `json:"ppn_percentage" validate:"omitempty,gte=0,lte=100"`
+	PPHPercentage float64 `json:"pph_percentage" validate:"gte=0,lte=100"` // PPh 23
+	PPH21Percentage float64 `json:"pph21_percentage" validate:"gte=0,lte=100"`
 	Notes            string               `json:"notes" validate:"max=2000"`
 	Language         string               `json:"language" validate:"required,oneof=ID EN"`
 	FileURL          string               `json:"file_url" validate:"omitempty,max=500"`
@@ -32,14 +48,21 @@ type CreateInvoiceRequest struct {
 }
 
 type UpdateInvoiceRequest struct {
+	ClientID         *uint64              `json:"client_id"`
 	RecipientName    string               `json:"recipient_name" validate:"omitempty,max=255"`
 	RecipientAddress string               `json:"recipient_address" validate:"max=1000"`
+	RecipientNPWP    string               `json:"recipient_npwp" validate:"max=30"`
 	Attention        string               `json:"attention" validate:"max=255"`
 	PONumber         string               `json:"po_number" validate:"max=100"`
 	InvoiceDate      string               `json:"invoice_date"`
+	DueDate          string               `json:"due_date"`
 	DPPercentage  *float64 `json:"dp_percentage" validate:"omitempty,gte=0,lte=100"`
+	DiscountPercent *float64 `json:"discount_percent" validate:"omitempty,gte=0,lte=100"`
+	DiscountAmount *money.Money `json:"discount_amount" validate:"omitempty,gte=0"`
+	PPNMode       string   `json:"ppn_mode" validate:"omitempty,oneof=STANDARD DPP_NILAI_LAIN"`
 	PPNPercentage *float64 `json:"ppn_percentage" validate:"omitempty,gte=0,lte=100"`
-	PPHPercentage *float64 `json:"pph_percentage" validate:"omitempty,gte=0,lte=100"`
+	PPHPercentage *float64 `json:"pph_percentage" validate:"omitempty,gte=0,lte=100"` // PPh 23
+	PPH21Percentage *float64 `json:"pph21_percentage" validate:"omitempty,gte=0,lte=100"`
 	Notes            string               `json:"notes" validate:"max=2000"`
 	Language         string               `json:"language" validate:"omitempty,oneof=ID EN"`
 	FileURL          string               `json:"file_url" validate:"omitempty,max=500"`
@@ -54,3 +77,7 @@ type ApproveInvoiceRequest struct {
 type RejectInvoiceRequest struct {
 	Notes string `json:"notes" validate:"required,min=5,max=1000"`
 }
+
+type VoidInvoiceRequest struct {
+	Reason string `json:"reason" validate:"required,min=5,max=1000"`
+}
@@ -0,0 +1,42 @@
+package request
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+// SaveInvoiceTemplateRequest creates a template or replaces all of its fields.
+type SaveInvoiceTemplateRequest struct {
+	Name             string                `json:"name" validate:"required,min=2,max=255"`
+	InvoiceType      string                `json:"invoice_type" validate:"required,oneof=DP FINAL_PAYMENT TOP_1 TOP_2 TOP_3 MEALS ADDITIONAL"`
+	ProjectID        *uint64               `json:"project_id"`
+	ClientID         *uint64               `json:"client_id"`
+	RecipientName    string                `json:"recipient_name" validate:"max=255"`
+	RecipientAddress string                `json:"recipient_address" validate:"max=1000"`
+	RecipientNPWP    string                `json:"recipient_npwp" validate:"max=30"`
+	Attention        string                `json:"attention" validate:"max=255"`
+	DPPercentage     *float64              `json:"dp_percentage" validate:"omitempty,gte=0,lte=100"`
+	DiscountPercent  float64               `json:"discount_percent" validate:"gte=0,lte=100"`
+	DiscountAmount   money.Money           `json:"discount_amount" validate:"gte=0"`
+	PPNMode          string                `json:"ppn_mode" validate:"omitempty,oneof=STANDARD DPP_NILAI_LAIN"`
+	PPNPercentage    float64               `json:"ppn_percentage" validate:"gte=0,lte=100"`
+	PPHPercentage    float64               `json:"pph_percentage" validate:"gte=0,lte=100"`
+	PPH21Percentage  float64               `json:"pph21_percentage" validate:"gte=0,lte=100"`
+	Language         string                `json:"language" validate:"required,oneof=ID EN"`
+	Notes            string                `json:"notes" validate:"max=2000"`
+	Items            []InvoiceItemRequest  `json:"items" validate:"omitempty,dive"`
+	Labels           []InvoiceLabelRequest `json:"labels" validate:"omitempty,dive"`
+}
+
+// CreateInvoiceFromTemplateRequest holds the values that differ per invoice.
+// project_id may be left out when the template has a default project.
+type CreateInvoiceFromTemplateRequest struct {
+	ProjectID   uint64 `json:"project_id"`
+	InvoiceDate string `json:"invoice_date" validate:"required"`
+	DueDate     string `json:"due_date"`
+	PONumber    string `json:"po_number" validate:"max=100"`
+}
+
+// CloneInvoiceRequest dates the copy of an invoice. Without invoice_date the
+// copy is dated today; without due_date it keeps the original payment term.
+type CloneInvoiceRequest struct {
+	InvoiceDate string `json:"invoice_date"`
+	DueDate     string `json:"due_date"`
+}
@@ -1,17 +1,25 @@
 package request
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
 type CreateProjectRequest struct {
 	Name        string             `json:"name" validate:"required,min=2,max=255"`
 	Description string             `json:"description" validate:"max=1000"`
-	TotalBudget float64            `json:"total_budget" validate:"required,gt=0"`
+	ClientID    *uint64            `json:"client_id"`
+	TotalBudget money.Money        `json:"total_budget" validate:"required,gt=0"`
 	PlanItems   []PlanItemRequest  `json:"plan_items" validate:"omitempty,dive"`
 	PlanLabels  []PlanLabelRequest `json:"plan_labels" validate:"omitempty,dive"`
+	// WARN (default) or BLOCK expenses that take spending past total_budget
+	OverspendPolicy string `json:"overspend_policy" validate:"omitempty,oneof=WARN BLOCK"`
 }
 
 type UpdateProjectRequest struct {
-	Name        string `json:"name" validate:"omitempty,min=2,max=255"`
-	Description string `json:"description" validate:"max=1000"`
-	Status      string `json:"status" validate:"omitempty,oneof=ACTIVE COMPLETED ARCHIVED"`
+	Name        string  `json:"name" validate:"omitempty,min=2,max=255"`
+	Description string  `json:"description" validate:"max=1000"`
+	ClientID    *uint64 `json:"client_id"`
+	Status      string  `json:"status" validate:"omitempty,oneof=ACTIVE COMPLETED ARCHIVED"`
+	// WARN or BLOCK expenses that take spending past total_budget
+	OverspendPolicy string `json:"overspend_policy" validate:"omitempty,oneof=WARN BLOCK"`
 }
 
 type AddMemberRequest struct {
@@ -0,0 +1,29 @@
+package request
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+// CreateWithholdingCertificateRequest records a certificate for an invoice.
+// Without a certificate number it is recorded as still expected.
+type CreateWithholdingCertificateRequest struct {
+	TaxType           string      `json:"tax_type" validate:"omitempty,oneof=PPH23 PPH21"`
+	CertificateNumber string      `json:"certificate_number" validate:"max=100"`
+	CertificateDate   string      `json:"certificate_date" validate:"omitempty,datetime=2006-01-02"`
+	WithheldAmount    money.Money `json:"withheld_amount" validate:"gte=0"`
+	FileURL           string      `json:"file_url" validate:"omitempty,max=500"`
+	Notes             string      `json:"notes" validate:"max=1000"`
+}
+
+// UpdateWithholdingCertificateRequest replaces the details of a certificate.
+type UpdateWithholdingCertificateRequest struct {
+	CertificateNumber string      `json:"certificate_number" validate:"max=100"`
+	CertificateDate   string      `json:"certificate_date" validate:"omitempty,datetime=2006-01-02"`
+	WithheldAmount    money.Money `json:"withheld_amount" validate:"gte=0"`
+	FileURL           string      `json:"file_url" validate:"omitempty,max=500"`
+	Notes             string      `json:"notes" validate:"max=1000"`
+}
+
+// WithholdingReportRequest holds the query parameters of GET
+// /api/withholding-certificates/report. The tax period is a month.
+type WithholdingReportRequest struct {
+	Period string `query:"period" validate:"required,datetime=2006-01"`
+}
@@ -0,0 +1,37 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type ApprovalPolicyStepResponse struct {
+	StepOrder     int      `json:"step_order"`
+	ApproverRoles []string `json:"approver_roles"`
+}
+
+type ApprovalPolicyResponse struct {
+	ID          uint64                       `json:"id"`
+	Name        string                       `json:"name"`
+	InvoiceType string                       `json:"invoice_type,omitempty"` // empty applies to all types
+	MinAmount   money.Money                  `json:"min_amount"`
+	IsActive    bool                         `json:"is_active"`
+	Steps       []ApprovalPolicyStepResponse `json:"steps"`
+	CreatedBy   uint64                       `json:"created_by"`
+	CreatedAt   time.Time                    `json:"created_at"`
+	UpdatedAt   time.Time                    `json:"updated_at"`
+}
+
+type InvoiceApprovalStepResponse struct {
+	ID            uint64     `json:"id"`
+	Round         int        `json:"round"`
+	StepOrder     int        `json:"step_order"`
+	PolicyID      *uint64    `json:"policy_id,omitempty"`
+	ApproverRoles []string   `json:"approver_roles"`
+	Status        string     `json:"status"`
+	ActedBy       *uint64    `json:"acted_by,omitempty"`
+	ActorName     string     `json:"actor_name,omitempty"`
+	ActedAt       *time.Time `json:"acted_at,omitempty"`
+	Notes         string     `json:"notes,omitempty"`
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type BankStatementResponse struct {
+	ID               uint64                    `json:"id"`
+	Format           string                    `json:"format"`
+	AccountNumber    string                    `json:"account_number"`
+	FileName         string                    `json:"file_name"`
+	PeriodStart      string                    `json:"period_start,omitempty"`
+	PeriodEnd        string                    `json:"period_end,omitempty"`
+	TransactionCount int                       `json:"transaction_count"`
+	DuplicateCount   int                       `json:"duplicate_count"`
+	MatchedCount     int                       `json:"matched_count"`
+	UnmatchedCount   int                       `json:"unmatched_count"`
+	UploadedBy       uint64                    `json:"uploaded_by"`
+	UploaderName     string                    `json:"uploader_name,omitempty"`
+	CreatedAt        time.Time                 `json:"created_at"`
+	Transactions     []BankTransactionResponse `json:"transactions,omitempty"`
+}
+
+type BankTransactionResponse struct {
+	ID              uint64                `json:"id"`
+	TransactionDate string                `json:"transaction_date"`
+	Description     string                `json:"description"`
+	Reference       string                `json:"reference,omitempty"`
+	Amount          money.Money           `json:"amount"`
+	Direction       string                `json:"direction"`
+	Status          string                `json:"status"`
+	InvoiceID       *uint64               `json:"invoice_id,omitempty"`
+	InvoiceNumber   string                `json:"invoice_number,omitempty"`
+	PaymentID       *uint64               `json:"payment_id,omitempty"`
+	MatchedBy       *uint64               `json:"matched_by,omitempty"`
+	MatchedAt       *time.Time            `json:"matched_at,omitempty"`
+	Suggestions     []BankMatchSuggestion `json:"suggestions,omitempty"`
+}
+
+// BankMatchSuggestion is an outstanding invoice that an incoming transaction
+// probably pays. Reasons lists the matching criteria: invoice_number,
+// exact_amount, partial_amount and date_window.
+type BankMatchSuggestion struct {
+	InvoiceID     uint64      `json:"invoice_id"`
+	InvoiceNumber string      `json:"invoice_number"`
+	RecipientName string      `json:"recipient_name"`
+	Outstanding   money.Money `json:"outstanding"`
+	InvoiceDate   string      `json:"invoice_date"`
+	DueDate       string      `json:"due_date,omitempty"`
+	Score         int         `json:"score"`
+	Confidence    string      `json:"confidence"`
+	Reasons       []string    `json:"reasons"`
+}
+
+type BankMatchResultResponse struct {
+	TransactionID uint64  `json:"transaction_id"`
+	InvoiceID     uint64  `json:"invoice_id"`
+	PaymentID     *uint64 `json:"payment_id,omitempty"`
+	Status        string  `json:"status"`
+	Error         string  `json:"error,omitempty"`
+}
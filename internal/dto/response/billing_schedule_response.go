@@ -0,0 +1,37 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type BillingTermResponse struct {
+	ID            uint64      `json:"id"`
+	InvoiceType   string      `json:"invoice_type"`
+	Description   string      `json:"description"`
+	Percentage    float64     `json:"percentage"`
+	Amount        money.Money `json:"amount"`
+	ExpectedDate  string      `json:"expected_date,omitempty"`
+	Status        string      `json:"status"`
+	InvoiceID     *uint64     `json:"invoice_id,omitempty"`
+	InvoiceNumber string      `json:"invoice_number,omitempty"`
+	InvoiceStatus string      `json:"invoice_status,omitempty"`
+}
+
+type BillingScheduleResponse struct {
+	ProjectID           uint64                `json:"project_id"`
+	ProjectName         string                `json:"project_name,omitempty"`
+	ContractValue       money.Money           `json:"contract_value"`
+	PPNPercentage       float64               `json:"ppn_percentage"`
+	PPHPercentage       float64               `json:"pph_percentage"`
+	Notes               string                `json:"notes,omitempty"`
+	ScheduledPercentage float64               `json:"scheduled_percentage"`
+	ScheduledAmount     money.Money           `json:"scheduled_amount"`
+	UnscheduledAmount   money.Money           `json:"unscheduled_amount"`
+	InvoicedAmount      money.Money           `json:"invoiced_amount"`
+	UnbilledAmount      money.Money           `json:"unbilled_amount"`
+	Terms               []BillingTermResponse `json:"terms"`
+	UpdatedBy           uint64                `json:"updated_by"`
+	UpdatedAt           time.Time             `json:"updated_at"`
+}
@@ -1,18 +1,22 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type BudgetRequestResponse struct {
-	ID               uint64    `json:"id"`
-	ProjectID        uint64    `json:"project_id"`
-	RequestedBy      uint64    `json:"requested_by"`
-	Amount           float64   `json:"amount"`
-	Reason           string    `json:"reason"`
-	ProofURL         *string   `json:"proof_url,omitempty"`
-	Status           string    `json:"status"`
-	ApprovedBy       *uint64   `json:"approved_by,omitempty"`
-	ApprovalNotes    *string   `json:"approval_notes,omitempty"`
-	ApprovalProofURL *string   `json:"approval_proof_url,omitempty"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID               uint64      `json:"id"`
+	ProjectID        uint64      `json:"project_id"`
+	RequestedBy      uint64      `json:"requested_by"`
+	Amount           money.Money `json:"amount"`
+	Reason           string      `json:"reason"`
+	ProofURL         *string     `json:"proof_url,omitempty"`
+	Status           string      `json:"status"`
+	ApprovedBy       *uint64     `json:"approved_by,omitempty"`
+	ApprovalNotes    *string     `json:"approval_notes,omitempty"`
+	ApprovalProofURL *string     `json:"approval_proof_url,omitempty"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+type ClientContactResponse struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Position  string `json:"position,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+type ClientResponse struct {
+	ID               uint64                  `json:"id"`
+	Name             string                  `json:"name"`
+	NPWP             string                  `json:"npwp,omitempty"`
+	BillingAddress   string                  `json:"billing_address,omitempty"`
+	Email            string                  `json:"email,omitempty"`
+	Phone            string                  `json:"phone,omitempty"`
+	PaymentTermsDays int                     `json:"payment_terms_days"`
+	Notes            string                  `json:"notes,omitempty"`
+	IsActive         bool                    `json:"is_active"`
+	CreatedBy        uint64                  `json:"created_by"`
+	Contacts         []ClientContactResponse `json:"contacts,omitempty"`
+	CreatedAt        time.Time               `json:"created_at"`
+	UpdatedAt        time.Time               `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type CommentMentionResponse struct {
+	UserID   uint64 `json:"user_id"`
+	FullName string `json:"full_name"`
+}
+
+// CommentResponse is a comment. In a listing, top-level comments carry their
+// replies oldest first.
+type CommentResponse struct {
+	ID          uint64                   `json:"id"`
+	EntityType  string                   `json:"entity_type"`
+	EntityID    uint64                   `json:"entity_id"`
+	ParentID    *uint64                  `json:"parent_id,omitempty"`
+	Body        string                   `json:"body"`
+	CreatedBy   uint64                   `json:"created_by"`
+	AuthorName  string                   `json:"author_name"`
+	Attachments []string                 `json:"attachments"`
+	Mentions    []CommentMentionResponse `json:"mentions"`
+	Replies     []CommentResponse        `json:"replies,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
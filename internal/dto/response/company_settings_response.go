@@ -1,22 +1,32 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type CompanySettingsResponse struct {
-	ID                uint64    `json:"id"`
-	CompanyName       string    `json:"company_name"`
-	CompanyCode       string    `json:"company_code"`
-	Address           string    `json:"address,omitempty"`
-	Phone             string    `json:"phone,omitempty"`
-	Email             string    `json:"email,omitempty"`
-	NPWP              string    `json:"npwp,omitempty"`
-	BankName          string    `json:"bank_name,omitempty"`
-	BankAccountNumber string    `json:"bank_account_number,omitempty"`
-	BankAccountName   string    `json:"bank_account_name,omitempty"`
-	BankBranch        string    `json:"bank_branch,omitempty"`
-	LogoURL           string    `json:"logo_url,omitempty"`
-	SignatoryName     string    `json:"signatory_name,omitempty"`
-	SignatoryTitle    string    `json:"signatory_title,omitempty"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                      uint64      `json:"id"`
+	CompanyName             string      `json:"company_name"`
+	CompanyCode             string      `json:"company_code"`
+	Address                 string      `json:"address,omitempty"`
+	Phone                   string      `json:"phone,omitempty"`
+	Email                   string      `json:"email,omitempty"`
+	NPWP                    string      `json:"npwp,omitempty"`
+	BankName                string      `json:"bank_name,omitempty"`
+	BankAccountNumber       string      `json:"bank_account_number,omitempty"`
+	BankAccountName         string      `json:"bank_account_name,omitempty"`
+	BankBranch              string      `json:"bank_branch,omitempty"`
+	LogoURL                 string      `json:"logo_url,omitempty"`
+	SignatoryName           string      `json:"signatory_name,omitempty"`
+	SignatoryTitle          string      `json:"signatory_title,omitempty"`
+	InvoiceNumberPattern    string      `json:"invoice_number_pattern"`
+	CreditNoteNumberPattern string      `json:"credit_note_number_pattern"`
+	ReceiptNumberPattern    string      `json:"receipt_number_pattern"`
+	StampDutyThreshold      money.Money `json:"stamp_duty_threshold"`
+	DefaultPPNMode          string      `json:"default_ppn_mode"`
+	DefaultPPNPercentage    float64     `json:"default_ppn_percentage"`
+	CreatedAt               time.Time   `json:"created_at"`
+	UpdatedAt               time.Time   `json:"updated_at"`
 }
@@ -0,0 +1,46 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type CreditNoteItemResponse struct {
+	ID              uint64      `json:"id"`
+	Description     string      `json:"description"`
+	Quantity        float64     `json:"quantity"`
+	Unit            string      `json:"unit"`
+	UnitPrice       money.Money `json:"unit_price"`
+	DiscountPercent float64     `json:"discount_percent"`
+	DiscountAmount  money.Money `json:"discount_amount"`
+	Subtotal        money.Money `json:"subtotal"`
+	PPNCode         string      `json:"ppn_code"`
+	PPHCode         string      `json:"pph_code"`
+	SortOrder       int         `json:"sort_order"`
+}
+
+type CreditNoteResponse struct {
+	ID               uint64                   `json:"id"`
+	CreditNoteNumber string                   `json:"credit_note_number"`
+	InvoiceID        uint64                   `json:"invoice_id"`
+	InvoiceNumber    string                   `json:"invoice_number,omitempty"`
+	CreditDate       string                   `json:"credit_date"`
+	Reason           string                   `json:"reason"`
+	Subtotal         money.Money              `json:"subtotal"`
+	DiscountAmount   money.Money              `json:"discount_amount"`
+	DPPAmount        money.Money              `json:"dpp_amount"`
+	PPNMode          string                   `json:"ppn_mode"`
+	PPNBase          money.Money              `json:"ppn_base"`
+	PPNPercentage    float64                  `json:"ppn_percentage"`
+	PPNAmount        money.Money              `json:"ppn_amount"`
+	PPHPercentage    float64                  `json:"pph_percentage"`
+	PPHAmount        money.Money              `json:"pph_amount"`
+	PPH21Percentage  float64                  `json:"pph21_percentage"`
+	PPH21Amount      money.Money              `json:"pph21_amount"`
+	Amount           money.Money              `json:"amount"`
+	CreatedBy        uint64                   `json:"created_by"`
+	CreatorName      string                   `json:"creator_name,omitempty"`
+	Items            []CreditNoteItemResponse `json:"items,omitempty"`
+	CreatedAt        time.Time                `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package response
 
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
 type DashboardResponse struct {
 	Projects       ProjectSummary       `json:"projects"`
 	Budget         BudgetSummary        `json:"budget"`
@@ -14,26 +16,27 @@ type ProjectSummary struct {
 }
 
 type BudgetSummary struct {
-	TotalBudget     float64 `json:"total_budget"`
-	TotalPlanBudget float64 `json:"total_plan_budget"`
-	TotalSpent      float64 `json:"total_spent"`
-	Remaining       float64 `json:"remaining"`
+	TotalBudget     money.Money `json:"total_budget"`
+	TotalPlanBudget float64     `json:"total_plan_budget"`
+	TotalSpent      money.Money `json:"total_spent"`
+	Remaining       money.Money `json:"remaining"`
 }
 
 type ExpenseSummary struct {
-	TotalExpenses int64   `json:"total_expenses"`
-	TotalAmount   float64 `json:"total_amount"`
+	TotalExpenses   int64       `json:"total_expenses"`
+	TotalAmount     money.Money `json:"total_amount"`
+	PendingExpenses int64       `json:"pending_expenses"`
 }
 
 type BudgetRequestSummary struct {
-	TotalRequests    int64   `json:"total_requests"`
-	PendingRequests  int64   `json:"pending_requests"`
-	ApprovedRequests int64   `json:"approved_requests"`
-	RejectedRequests int64   `json:"rejected_requests"`
-	TotalAmount      float64 `json:"total_amount"`
+	TotalRequests    int64       `json:"total_requests"`
+	PendingRequests  int64       `json:"pending_requests"`
+	ApprovedRequests int64       `json:"approved_requests"`
+	RejectedRequests int64       `json:"rejected_requests"`
+	TotalAmount      money.Money `json:"total_amount"`
 }
 
 type InvoiceSummary struct {
-	TotalInvoices int64   `json:"total_invoices"`
-	TotalAmount   float64 `json:"total_amount"`
+	TotalInvoices int64       `json:"total_invoices"`
+	TotalAmount   money.Money `json:"total_amount"`
 }
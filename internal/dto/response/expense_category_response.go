@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type ExpenseCategoryResponse struct {
+	ID             uint64    `json:"id"`
+	Code           string    `json:"code"`
+	LabelID        string    `json:"label_id"`
+	LabelEN        string    `json:"label_en"`
+	ReportCategory string    `json:"report_category"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
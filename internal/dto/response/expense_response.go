@@ -1,15 +1,34 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type ExpenseResponse struct {
-	ID          uint64    `json:"id"`
-	ProjectID   uint64    `json:"project_id"`
-	Description string    `json:"description"`
-	Amount      float64   `json:"amount"`
-	Category    string    `json:"category"`
-	ReceiptURL  string    `json:"receipt_url,omitempty"`
-	CreatedBy   uint64    `json:"created_by"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint64                    `json:"id"`
+	ProjectID   uint64                    `json:"project_id"`
+	Description string                    `json:"description"`
+	Amount      money.Money               `json:"amount"`
+	Category    string                    `json:"category"`
+	ReceiptURL  string                    `json:"receipt_url,omitempty"`
+	Status      string                    `json:"status"`
+	CreatedBy   uint64                    `json:"created_by"`
+	Approvals   []ExpenseApprovalResponse `json:"approvals,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+	// Set when the project spending is, or would be once the expense is
+	// approved, past the total budget
+	BudgetWarning string `json:"budget_warning,omitempty"`
+}
+
+type ExpenseApprovalResponse struct {
+	ID           uint64    `json:"id"`
+	ApprovedBy   uint64    `json:"approved_by"`
+	ApproverName string    `json:"approver_name"`
+	Status       string    `json:"status"`
+	Notes        string    `json:"notes,omitempty"`
+	ProofURL     string    `json:"proof_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
 }
@@ -0,0 +1,41 @@
+package response
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+type AgingBuckets struct {
+	Current    money.Money `json:"current"`
+	Days1To30  money.Money `json:"days_1_30"`
+	Days31To60 money.Money `json:"days_31_60"`
+	Days61To90 money.Money `json:"days_61_90"`
+	Over90     money.Money `json:"over_90"`
+	Total      money.Money `json:"total"`
+}
+
+type AgingGroupResponse struct {
+	ProjectID    uint64       `json:"project_id,omitempty"`
+	Name         string       `json:"name"`
+	InvoiceCount int          `json:"invoice_count"`
+	Buckets      AgingBuckets `json:"buckets"`
+}
+
+type AgingInvoiceResponse struct {
+	ID            uint64      `json:"id"`
+	InvoiceNumber string      `json:"invoice_number"`
+	RecipientName string      `json:"recipient_name"`
+	ProjectID     uint64      `json:"project_id"`
+	ProjectName   string      `json:"project_name,omitempty"`
+	InvoiceDate   string      `json:"invoice_date"`
+	DueDate       string      `json:"due_date"`
+	DaysPastDue   int         `json:"days_past_due"`
+	Bucket        string      `json:"bucket"`
+	Amount        money.Money `json:"amount"`
+	Outstanding   money.Money `json:"outstanding"`
+}
+
+type AgingReportResponse struct {
+	AsOf        string                 `json:"as_of"`
+	Totals      AgingBuckets           `json:"totals"`
+	ByRecipient []AgingGroupResponse   `json:"by_recipient"`
+	ByProject   []AgingGroupResponse   `json:"by_project"`
+	Invoices    []AgingInvoiceResponse `json:"invoices"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type InvoiceDeliveryResponse struct {
+	ID           uint64     `json:"id"`
+	InvoiceID    uint64     `json:"invoice_id"`
+	Channel      string     `json:"channel"`
+	Recipients   []string   `json:"recipients"`
+	Cc           []string   `json:"cc,omitempty"`
+	Subject      string     `json:"subject"`
+	Status       string     `json:"status"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	SentBy       uint64     `json:"sent_by"`
+	SenderName   string     `json:"sender_name,omitempty"`
+	SentAt       *time.Time `json:"sent_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,29 @@
+package response
+
+import "github.com/gilangrmdnii/invoice-backend/pkg/money"
+
+type EFakturInvoiceResponse struct {
+	ID              uint64      `json:"id"`
+	InvoiceNumber   string      `json:"invoice_number"`
+	InvoiceDate     string      `json:"invoice_date"`
+	RecipientName   string      `json:"recipient_name"`
+	RecipientNPWP   string      `json:"recipient_npwp"`
+	TransactionCode string      `json:"transaction_code"`
+	DPPAmount       money.Money `json:"dpp_amount"`
+	PPNAmount       money.Money `json:"ppn_amount"`
+	LineCount       int         `json:"line_count"`
+}
+
+type EFakturSkippedResponse struct {
+	ID            uint64   `json:"id"`
+	InvoiceNumber string   `json:"invoice_number"`
+	Reasons       []string `json:"reasons"`
+}
+
+type EFakturExportResponse struct {
+	DateFrom   string                   `json:"date_from"`
+	DateTo     string                   `json:"date_to"`
+	SellerNPWP string                   `json:"seller_npwp"`
+	Exported   []EFakturInvoiceResponse `json:"exported"`
+	Skipped    []EFakturSkippedResponse `json:"skipped"`
+}
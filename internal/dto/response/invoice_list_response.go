@@ -0,0 +1,8 @@
+package response
+
+type InvoiceListResponse struct {
+	Items      []InvoiceResponse `json:"items"`
+	Total      int               `json:"total"`                 // invoices matching the filters, across all pages
+	NextCursor string            `json:"next_cursor,omitempty"` // empty on the last page
+	Limit      int               `json:"limit"`
+}
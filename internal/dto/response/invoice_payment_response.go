@@ -1,16 +1,78 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type InvoicePaymentResponse struct {
-	ID            uint64    `json:"id"`
-	InvoiceID     uint64    `json:"invoice_id"`
-	Amount        float64   `json:"amount"`
-	PaymentDate   string    `json:"payment_date"`
-	PaymentMethod string    `json:"payment_method"`
-	ProofURL      string    `json:"proof_url,omitempty"`
-	Notes         string    `json:"notes,omitempty"`
-	CreatedBy     uint64    `json:"created_by"`
-	CreatorName   string    `json:"creator_name,omitempty"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID                uint64                  `json:"id"`
+	InvoiceID         uint64                  `json:"invoice_id"`
+	EntryType         string                  `json:"entry_type"`
+	ReceiptNumber     string                  `json:"receipt_number,omitempty"`
+	ReversedPaymentID *uint64                 `json:"reversed_payment_id,omitempty"`
+	ReversedByID      *uint64                 `json:"reversed_by_id,omitempty"`
+	Amount            money.Money             `json:"amount"`
+	PaymentDate       string                  `json:"payment_date"`
+	PaymentMethod     string                  `json:"payment_method"`
+	ProofURL          string                  `json:"proof_url,omitempty"`
+	Reason            string                  `json:"reason,omitempty"`
+	Notes             string                  `json:"notes,omitempty"`
+	CreatedBy         uint64                  `json:"created_by"`
+	CreatorName       string                  `json:"creator_name,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	Balance           *InvoiceBalanceResponse `json:"balance,omitempty"`
+}
+
+// InvoiceBalanceResponse is the invoice balance after a payment entry was
+// recorded.
+type InvoiceBalanceResponse struct {
+	PaidAmount     money.Money `json:"paid_amount"`
+	CreditedAmount money.Money `json:"credited_amount"`
+	Outstanding    money.Money `json:"outstanding"`
+	PaymentStatus  string      `json:"payment_status"`
+}
+
+type PaymentChargeResponse struct {
+	ID            uint64      `json:"id"`
+	InvoiceID     uint64      `json:"invoice_id"`
+	InvoiceNumber string      `json:"invoice_number"`
+	Provider      string      `json:"provider"`
+	Method        string      `json:"method"`
+	Bank          string      `json:"bank,omitempty"`
+	Reference     string      `json:"reference"`
+	VANumber      string      `json:"va_number,omitempty"`
+	QRString      string      `json:"qr_string,omitempty"`
+	Amount        money.Money `json:"amount"`
+	Status        string      `json:"status"`
+	ExpiresAt     time.Time   `json:"expires_at"`
+	PaidAt        *time.Time  `json:"paid_at,omitempty"`
+	PaymentID     *uint64     `json:"payment_id,omitempty"`
+	CreatedBy     uint64      `json:"created_by"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+// PaymentWebhookResponse acknowledges a gateway callback.
+type PaymentWebhookResponse struct {
+	EventID   string  `json:"event_id"`
+	Result    string  `json:"result"` // PROCESSED, IGNORED, FAILED or DUPLICATE
+	Error     string  `json:"error,omitempty"`
+	PaymentID *uint64 `json:"payment_id,omitempty"`
+}
+
+// PaymentWebhookEventResponse is a received gateway callback and what became
+// of it.
+type PaymentWebhookEventResponse struct {
+	ID        uint64      `json:"id"`
+	Provider  string      `json:"provider"`
+	EventID   string      `json:"event_id"`
+	Reference string      `json:"reference"`
+	Status    string      `json:"status"`
+	Amount    money.Money `json:"amount"`
+	Result    string      `json:"result"`
+	Error     string      `json:"error,omitempty"`
+	ChargeID  *uint64     `json:"charge_id,omitempty"`
+	InvoiceID *uint64     `json:"invoice_id,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
 }
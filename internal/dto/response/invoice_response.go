@@ -1,18 +1,65 @@
 package response
 
-import "time"
+import (
+	"time"
 
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// InvoiceItemResponse is an invoice line or label. The computed amounts of a
+// label are the sums of its lines.
 type InvoiceItemResponse struct {
-	ID          uint64  `json:"id"`
-	InvoiceID   uint64  `json:"invoice_id"`
-	ParentID    *uint64 `json:"parent_id,omitempty"`
-	IsLabel     bool    `json:"is_label"`
-	Description string  `json:"description"`
-	Quantity    float64 `json:"quantity"`
-	Unit        string  `json:"unit"`
-	UnitPrice   float64 `json:"unit_price"`
-	Subtotal    float64 `json:"subtotal"`
-	SortOrder   int     `json:"sort_order"`
+	ID              uint64      `json:"id"`
+	InvoiceID       uint64      `json:"invoice_id"`
+	ParentID        *uint64     `json:"parent_id,omitempty"`
+	IsLabel         bool        `json:"is_label"`
+	Description     string      `json:"description"`
+	Quantity        float64     `json:"quantity"`
+	Unit            string      `json:"unit"`
+	UnitPrice       money.Money `json:"unit_price"`
+	DiscountPercent float64     `json:"discount_percent"`
+	DiscountAmount  money.Money `json:"discount_amount"`
+	Subtotal        money.Money `json:"subtotal"` // before the line discount
+	PPNCode         string      `json:"ppn_code,omitempty"`
+	PPHCode         string      `json:"pph_code,omitempty"`
+	InvoiceDiscount money.Money `json:"invoice_discount"` // share of the invoice discount
+	DPPAmount       money.Money `json:"dpp_amount"`
+	PPNAmount       money.Money `json:"ppn_amount"`
+	PPHAmount       money.Money `json:"pph_amount"`
+	NetAmount       money.Money `json:"net_amount"` // DPP + PPN - PPh
+	SortOrder       int         `json:"sort_order"`
+}
+
+// InvoiceBreakdownResponse sums the computed line amounts of an invoice per
+// label and per tax.
+type InvoiceBreakdownResponse struct {
+	Groups []InvoiceGroupTotalsResponse `json:"groups"`
+	Taxes  []InvoiceTaxTotalsResponse   `json:"taxes"`
+}
+
+// InvoiceGroupTotalsResponse holds the totals of the lines under one label,
+// or of the lines outside any label when LabelID is nil.
+type InvoiceGroupTotalsResponse struct {
+	LabelID         *uint64     `json:"label_id,omitempty"`
+	Description     string      `json:"description,omitempty"`
+	Subtotal        money.Money `json:"subtotal"`
+	LineDiscount    money.Money `json:"line_discount"`
+	InvoiceDiscount money.Money `json:"invoice_discount"`
+	DPPAmount       money.Money `json:"dpp_amount"`
+	PPNAmount       money.Money `json:"ppn_amount"`
+	PPHAmount       money.Money `json:"pph_amount"`
+	NetAmount       money.Money `json:"net_amount"`
+}
+
+// InvoiceTaxTotalsResponse is one tax of an invoice with the base it was
+// computed on. For PPN in the DPP_NILAI_LAIN mode the base is the DPP nilai
+// lain.
+type InvoiceTaxTotalsResponse struct {
+	Code       string      `json:"code"`           // PPN, PPH23 or PPH21
+	Mode       string      `json:"mode,omitempty"` // PPN mode
+	Base       money.Money `json:"base"`
+	Percentage float64     `json:"percentage"`
+	Amount     money.Money `json:"amount"`
 }
 
 type InvoiceResponse struct {
@@ -21,30 +68,48 @@ type InvoiceResponse struct {
 	InvoiceType      string                `json:"invoice_type"`
 	ProjectID        uint64                `json:"project_id"`
 	ProjectName      string                `json:"project_name,omitempty"`
-	Amount           float64               `json:"amount"`
-	PaidAmount       float64               `json:"paid_amount"`
+	ClientID         *uint64               `json:"client_id,omitempty"`
+	BillingTermID    *uint64               `json:"billing_term_id,omitempty"`
+	Amount           money.Money           `json:"amount"`
+	PaidAmount       money.Money           `json:"paid_amount"`
+	CreditedAmount   money.Money           `json:"credited_amount"`
 	Status           string                `json:"status"`
 	PaymentStatus    string                `json:"payment_status"`
 	FileURL          string                `json:"file_url,omitempty"`
 	RecipientName    string                `json:"recipient_name"`
 	RecipientAddress string                `json:"recipient_address,omitempty"`
+	RecipientNPWP    string                `json:"recipient_npwp,omitempty"`
+	TaxInvoiceNumber string                `json:"tax_invoice_number,omitempty"`
 	Attention        string                `json:"attention,omitempty"`
 	PONumber         string                `json:"po_number,omitempty"`
 	InvoiceDate      string                `json:"invoice_date"`
 	DueDate          string                `json:"due_date,omitempty"`
 	DPPercentage     *float64              `json:"dp_percentage,omitempty"`
-	Subtotal      float64 `json:"subtotal"`
+	Subtotal      money.Money `json:"subtotal"` // after line discounts
+	DiscountPercent float64   `json:"discount_percent"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	DPPAmount     money.Money `json:"dpp_amount"`
+	PPNMode       string      `json:"ppn_mode"`
+	PPNBase       money.Money `json:"ppn_base"`
 	PPNPercentage float64 `json:"ppn_percentage"`
-	PPNAmount     float64 `json:"ppn_amount"`
-	PPHPercentage float64 `json:"pph_percentage"`
-	PPHAmount     float64 `json:"pph_amount"`
+	PPNAmount     money.Money `json:"ppn_amount"`
+	PPHPercentage float64 `json:"pph_percentage"` // PPh 23
+	PPHAmount     money.Money `json:"pph_amount"`
+	PPH21Percentage float64   `json:"pph21_percentage"`
+	PPH21Amount   money.Money `json:"pph21_amount"`
 	Notes            string                `json:"notes,omitempty"`
 	Language         string                `json:"language"`
 	CreatedBy        uint64                `json:"created_by"`
 	ApprovedBy       *uint64               `json:"approved_by,omitempty"`
 	RejectNotes      string                `json:"reject_notes,omitempty"`
+	VoidReason       string                `json:"void_reason,omitempty"`
+	VoidedBy         *uint64               `json:"voided_by,omitempty"`
+	VoidedAt         *time.Time            `json:"voided_at,omitempty"`
 	Items            []InvoiceItemResponse    `json:"items"`
+	Breakdown        *InvoiceBreakdownResponse `json:"breakdown,omitempty"`
 	Payments         []InvoicePaymentResponse `json:"payments,omitempty"`
+	CreditNotes      []CreditNoteResponse     `json:"credit_notes,omitempty"`
+	ApprovalSteps    []InvoiceApprovalStepResponse `json:"approval_steps,omitempty"` // current approval round
 	CreatedAt        time.Time                `json:"created_at"`
 	UpdatedAt        time.Time                `json:"updated_at"`
 }
@@ -0,0 +1,35 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type InvoiceRevisionResponse struct {
+	ID             uint64          `json:"id"`
+	InvoiceID      uint64          `json:"invoice_id"`
+	RevisionNumber int             `json:"revision_number"`
+	Action         string          `json:"action"`
+	Status         string          `json:"status"`
+	Amount         money.Money     `json:"amount"`
+	Snapshot       json.RawMessage `json:"snapshot,omitempty"` // only when a single revision is requested
+	CreatedBy      uint64          `json:"created_by"`
+	CreatorName    string          `json:"creator_name"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type InvoiceFieldChangeResponse struct {
+	Field string      `json:"field"` // e.g. "recipient_name" or "items[1].children[0].unit_price"
+	Type  string      `json:"type"`  // CHANGED, ADDED or REMOVED
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type InvoiceRevisionDiffResponse struct {
+	InvoiceID    uint64                       `json:"invoice_id"`
+	FromRevision int                          `json:"from_revision"`
+	ToRevision   int                          `json:"to_revision"`
+	Changes      []InvoiceFieldChangeResponse `json:"changes"`
+}
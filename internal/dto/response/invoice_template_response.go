@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type InvoiceTemplateLineResponse struct {
+	Description     string      `json:"description"`
+	Quantity        float64     `json:"quantity"`
+	Unit            string      `json:"unit"`
+	UnitPrice       money.Money `json:"unit_price"`
+	DiscountPercent float64     `json:"discount_percent"`
+	DiscountAmount  money.Money `json:"discount_amount"`
+	PPNCode         string      `json:"ppn_code"`
+	PPHCode         string      `json:"pph_code"`
+}
+
+type InvoiceTemplateLabelResponse struct {
+	Description string                        `json:"description"`
+	Items       []InvoiceTemplateLineResponse `json:"items"`
+}
+
+type InvoiceTemplateResponse struct {
+	ID               uint64                         `json:"id"`
+	Name             string                         `json:"name"`
+	InvoiceType      string                         `json:"invoice_type"`
+	ProjectID        *uint64                        `json:"project_id,omitempty"`
+	ClientID         *uint64                        `json:"client_id,omitempty"`
+	RecipientName    string                         `json:"recipient_name"`
+	RecipientAddress string                         `json:"recipient_address"`
+	RecipientNPWP    string                         `json:"recipient_npwp"`
+	Attention        string                         `json:"attention"`
+	DPPercentage     *float64                       `json:"dp_percentage,omitempty"`
+	DiscountPercent  float64                        `json:"discount_percent"`
+	DiscountAmount   money.Money                    `json:"discount_amount"`
+	PPNMode          string                         `json:"ppn_mode,omitempty"`
+	PPNPercentage    float64                        `json:"ppn_percentage"`
+	PPHPercentage    float64                        `json:"pph_percentage"`
+	PPH21Percentage  float64                        `json:"pph21_percentage"`
+	Language         string                         `json:"language"`
+	Notes            string                         `json:"notes"`
+	Items            []InvoiceTemplateLineResponse  `json:"items"`
+	Labels           []InvoiceTemplateLabelResponse `json:"labels"`
+	Subtotal         money.Money                    `json:"subtotal"` // of the template lines after line discounts, before tax
+	CreatedBy        uint64                         `json:"created_by"`
+	CreatorName      string                         `json:"creator_name"`
+	CreatedAt        time.Time                      `json:"created_at"`
+	UpdatedAt        time.Time                      `json:"updated_at"`
+}
@@ -1,14 +1,21 @@
 package response
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type ProjectResponse struct {
 	ID          uint64          `json:"id"`
 	Name        string          `json:"name"`
 	Description string          `json:"description"`
+	ClientID    *uint64         `json:"client_id,omitempty"`
+	ClientName  string          `json:"client_name,omitempty"`
 	Status      string          `json:"status"`
-	TotalBudget float64         `json:"total_budget"`
-	SpentAmount float64         `json:"spent_amount"`
+	TotalBudget money.Money     `json:"total_budget"`
+	SpentAmount money.Money     `json:"spent_amount"`
+	OverspendPolicy string      `json:"overspend_policy,omitempty"`
 	CreatedBy   uint64          `json:"created_by"`
 	CreatedAt   time.Time       `json:"created_at"`
 	UpdatedAt   time.Time       `json:"updated_at"`
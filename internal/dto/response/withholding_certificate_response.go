@@ -0,0 +1,51 @@
+package response
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type WithholdingCertificateResponse struct {
+	ID                uint64      `json:"id"`
+	InvoiceID         uint64      `json:"invoice_id"`
+	InvoiceNumber     string      `json:"invoice_number,omitempty"`
+	TaxType           string      `json:"tax_type"`
+	CertificateNumber string      `json:"certificate_number"`
+	CertificateDate   string      `json:"certificate_date,omitempty"`
+	WithheldAmount    money.Money `json:"withheld_amount"`
+	ExpectedAmount    money.Money `json:"expected_amount"`
+	FileURL           string      `json:"file_url"`
+	Status            string      `json:"status"`
+	Notes             string      `json:"notes"`
+	CreatedBy         uint64      `json:"created_by"`
+	CreatedAt         time.Time   `json:"created_at"`
+	UpdatedAt         time.Time   `json:"updated_at"`
+}
+
+// WithholdingReportLineResponse is an invoice whose certificate is missing
+// (Status MISSING) or does not match the PPh withheld (Status MISMATCH).
+type WithholdingReportLineResponse struct {
+	InvoiceID         uint64      `json:"invoice_id"`
+	InvoiceNumber     string      `json:"invoice_number"`
+	InvoiceDate       string      `json:"invoice_date"`
+	RecipientName     string      `json:"recipient_name"`
+	RecipientNPWP     string      `json:"recipient_npwp"`
+	TaxType           string      `json:"tax_type"`
+	ExpectedAmount    money.Money `json:"expected_amount"`
+	WithheldAmount    money.Money `json:"withheld_amount"`
+	CertificateID     *uint64     `json:"certificate_id,omitempty"`
+	CertificateNumber string      `json:"certificate_number,omitempty"`
+	Status            string      `json:"status"`
+}
+
+type WithholdingReportResponse struct {
+	Period         string                          `json:"period"`
+	ExpectedCount  int                             `json:"expected_count"`
+	ReceivedCount  int                             `json:"received_count"`
+	MissingCount   int                             `json:"missing_count"`
+	MismatchCount  int                             `json:"mismatch_count"`
+	ExpectedAmount money.Money                     `json:"expected_amount"`
+	MissingAmount  money.Money                     `json:"missing_amount"`
+	Invoices       []WithholdingReportLineResponse `json:"invoices"`
+}
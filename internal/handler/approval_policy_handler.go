@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type ApprovalPolicyHandler struct {
+	policyService *service.ApprovalPolicyService
+}
+
+func NewApprovalPolicyHandler(policyService *service.ApprovalPolicyService) *ApprovalPolicyHandler {
+	return &ApprovalPolicyHandler{policyService: policyService}
+}
+
+func (h *ApprovalPolicyHandler) Create(c *fiber.Ctx) error {
+	var req request.CreateApprovalPolicyRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	result, err := h.policyService.Create(c.Context(), &req, userID)
+	if err != nil {
+		if err.Error() == "an active policy with the same invoice type and minimum amount already exists" {
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create approval policy")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "approval policy created successfully", result)
+}
+
+func (h *ApprovalPolicyHandler) List(c *fiber.Ctx) error {
+	policies, err := h.policyService.List(c.Context())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list approval policies")
+	}
+
+	return response.Success(c, fiber.StatusOK, "approval policies retrieved successfully", policies)
+}
+
+func (h *ApprovalPolicyHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid approval policy id")
+	}
+
+	policy, err := h.policyService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "approval policy not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get approval policy")
+	}
+
+	return response.Success(c, fiber.StatusOK, "approval policy retrieved successfully", policy)
+}
+
+func (h *ApprovalPolicyHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid approval policy id")
+	}
+
+	var req request.UpdateApprovalPolicyRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	policy, err := h.policyService.Update(c.Context(), id, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "approval policy not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "an active policy with the same invoice type and minimum amount already exists":
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update approval policy")
+	}
+
+	return response.Success(c, fiber.StatusOK, "approval policy updated successfully", policy)
+}
+
+func (h *ApprovalPolicyHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid approval policy id")
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.policyService.Delete(c.Context(), id, userID); err != nil {
+		if err.Error() == "approval policy not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to delete approval policy")
+	}
+
+	return response.Success(c, fiber.StatusOK, "approval policy deleted successfully", nil)
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+var allowedStatementExtensions = map[string]bool{
+	".csv":   true,
+	".txt":   true,
+	".sta":   true,
+	".mt940": true,
+	".940":   true,
+}
+
+type BankStatementHandler struct {
+	statementService *service.BankStatementService
+}
+
+func NewBankStatementHandler(statementService *service.BankStatementService) *BankStatementHandler {
+	return &BankStatementHandler{statementService: statementService}
+}
+
+func (h *BankStatementHandler) Import(c *fiber.Ctx) error {
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "file is required")
+	}
+
+	// Max 5MB
+	if file.Size > 5*1024*1024 {
+		return response.Error(c, fiber.StatusBadRequest, "file size must be less than 5MB")
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !allowedStatementExtensions[ext] {
+		return response.Error(c, fiber.StatusBadRequest, "only CSV and MT940 (STA/TXT/940) statement files are allowed")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to read file")
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to read file")
+	}
+
+	userID := middleware.GetUserID(c)
+	accountNumber := strings.TrimSpace(c.FormValue("account_number"))
+
+	result, err := h.statementService.Import(c.Context(), filepath.Base(file.Filename), data, accountNumber, userID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid statement file") {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to import bank statement")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "bank statement imported successfully", result)
+}
+
+func (h *BankStatementHandler) List(c *fiber.Ctx) error {
+	statements, err := h.statementService.List(c.Context())
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list bank statements")
+	}
+
+	return response.Success(c, fiber.StatusOK, "bank statements retrieved successfully", statements)
+}
+
+func (h *BankStatementHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid bank statement id")
+	}
+
+	status := strings.ToUpper(c.Query("status"))
+	switch status {
+	case "", "UNMATCHED", "MATCHED", "IGNORED":
+	default:
+		return response.Error(c, fiber.StatusBadRequest, "invalid status filter")
+	}
+
+	result, err := h.statementService.Get(c.Context(), id, status)
+	if err != nil {
+		if err.Error() == "bank statement not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get bank statement")
+	}
+
+	return response.Success(c, fiber.StatusOK, "bank statement retrieved successfully", result)
+}
+
+func (h *BankStatementHandler) ConfirmMatches(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid bank statement id")
+	}
+
+	var req request.ConfirmBankMatchesRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	results, err := h.statementService.ConfirmMatches(c.Context(), id, &req, userID)
+	if err != nil {
+		if err.Error() == "bank statement not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to confirm matches")
+	}
+
+	return response.Success(c, fiber.StatusOK, "matches processed", results)
+}
+
+func (h *BankStatementHandler) Ignore(c *fiber.Ctx) error {
+	return h.setIgnored(c, true)
+}
+
+func (h *BankStatementHandler) Unignore(c *fiber.Ctx) error {
+	return h.setIgnored(c, false)
+}
+
+func (h *BankStatementHandler) setIgnored(c *fiber.Ctx, ignored bool) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid bank statement id")
+	}
+
+	txID, err := strconv.ParseUint(c.Params("txId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid transaction id")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	if err := h.statementService.SetIgnored(c.Context(), id, txID, ignored, userID); err != nil {
+		switch err.Error() {
+		case "bank transaction not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "bank transaction status cannot be changed":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update bank transaction")
+	}
+
+	return response.Success(c, fiber.StatusOK, "bank transaction updated successfully", nil)
+}
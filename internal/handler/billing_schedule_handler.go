@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type BillingScheduleHandler struct {
+	scheduleService *service.BillingScheduleService
+}
+
+func NewBillingScheduleHandler(scheduleService *service.BillingScheduleService) *BillingScheduleHandler {
+	return &BillingScheduleHandler{scheduleService: scheduleService}
+}
+
+func (h *BillingScheduleHandler) Get(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid project id")
+	}
+
+	schedule, err := h.scheduleService.Get(c.Context(), projectID)
+	if err != nil {
+		switch err.Error() {
+		case "project not found", "billing schedule not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get billing schedule")
+	}
+
+	return response.Success(c, fiber.StatusOK, "billing schedule retrieved successfully", schedule)
+}
+
+func (h *BillingScheduleHandler) Save(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid project id")
+	}
+
+	var req request.SaveBillingScheduleRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	schedule, err := h.scheduleService.Save(c.Context(), projectID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "project not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "duplicate billing term type",
+			"invalid expected date format, use YYYY-MM-DD",
+			"billing terms exceed the contract value",
+			"billing term not found",
+			"contract value is below the amount already invoiced",
+			"invoiced terms cannot be changed or removed":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to save billing schedule")
+	}
+
+	return response.Success(c, fiber.StatusOK, "billing schedule saved successfully", schedule)
+}
+
+func (h *BillingScheduleHandler) GenerateInvoice(c *fiber.Ctx) error {
+	projectID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid project id")
+	}
+	termID, err := strconv.ParseUint(c.Params("termId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid billing term id")
+	}
+
+	var req request.GenerateTermInvoiceRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.scheduleService.GenerateInvoice(c.Context(), projectID, termID, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "project not found", "billing schedule not found", "billing term not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "billing term already invoiced",
+			"invoice exceeds remaining contract value",
+			"invalid invoice date format, use YYYY-MM-DD",
+			"client not found",
+			"client is inactive",
+			"recipient_name is required":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to generate invoice")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "invoice generated successfully", result)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type ClientHandler struct {
+	clientService *service.ClientService
+}
+
+func NewClientHandler(clientService *service.ClientService) *ClientHandler {
+	return &ClientHandler{clientService: clientService}
+}
+
+func (h *ClientHandler) Create(c *fiber.Ctx) error {
+	var req request.CreateClientRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	result, err := h.clientService.Create(c.Context(), &req, userID)
+	if err != nil {
+		if err.Error() == "invalid npwp, must be 15 or 16 digits" {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create client")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "client created successfully", result)
+}
+
+func (h *ClientHandler) List(c *fiber.Ctx) error {
+	search := c.Query("search")
+	includeInactive := c.Query("include_inactive") == "true"
+
+	clients, err := h.clientService.List(c.Context(), search, includeInactive)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list clients")
+	}
+
+	return response.Success(c, fiber.StatusOK, "clients retrieved successfully", clients)
+}
+
+func (h *ClientHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid client id")
+	}
+
+	client, err := h.clientService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "client not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get client")
+	}
+
+	return response.Success(c, fiber.StatusOK, "client retrieved successfully", client)
+}
+
+func (h *ClientHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid client id")
+	}
+
+	var req request.UpdateClientRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	client, err := h.clientService.Update(c.Context(), id, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "client not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "invalid npwp, must be 15 or 16 digits":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update client")
+	}
+
+	return response.Success(c, fiber.StatusOK, "client updated successfully", client)
+}
+
+func (h *ClientHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid client id")
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.clientService.Delete(c.Context(), id, userID); err != nil {
+		switch err.Error() {
+		case "client not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "client is referenced by projects or invoices":
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to delete client")
+	}
+
+	return response.Success(c, fiber.StatusOK, "client deleted successfully", nil)
+}
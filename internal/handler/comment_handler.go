@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+func (h *CommentHandler) Create(c *fiber.Ctx) error {
+	var req request.CreateCommentRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.commentService.Create(c.Context(), &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "commented record not found", "parent comment not found", "mentioned user not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "invalid entity type", "mentioned user cannot access this record":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create comment")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "comment created successfully", result)
+}
+
+func (h *CommentHandler) List(c *fiber.Ctx) error {
+	var req request.ListCommentsRequest
+	if err := validator.ParseQueryAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	comments, err := h.commentService.List(c.Context(), &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "commented record not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "invalid entity type":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list comments")
+	}
+
+	return response.Success(c, fiber.StatusOK, "comments retrieved successfully", comments)
+}
+
+func (h *CommentHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid comment id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	comment, err := h.commentService.GetByID(c.Context(), id, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "comment not found", "commented record not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get comment")
+	}
+
+	return response.Success(c, fiber.StatusOK, "comment retrieved successfully", comment)
+}
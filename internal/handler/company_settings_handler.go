@@ -37,6 +37,10 @@ func (h *CompanySettingsHandler) Upsert(c *fiber.Ctx) error {
 
 	result, err := h.service.Upsert(c.Context(), &req)
 	if err != nil {
+		switch err.Error() {
+		case "invalid invoice number pattern", "invalid credit note number pattern", "invalid receipt number pattern":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to save company settings")
 	}
 
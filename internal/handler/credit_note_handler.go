@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type CreditNoteHandler struct {
+	creditNoteService *service.CreditNoteService
+}
+
+func NewCreditNoteHandler(creditNoteService *service.CreditNoteService) *CreditNoteHandler {
+	return &CreditNoteHandler{creditNoteService: creditNoteService}
+}
+
+func (h *CreditNoteHandler) Create(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.CreateCreditNoteRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.creditNoteService.Create(c.Context(), invoiceID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "credit notes can only be issued for approved invoices",
+			"invalid credit date format, use YYYY-MM-DD",
+			"use either discount_percent or discount_amount",
+			"line discount exceeds line amount":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "credit note amount") {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create credit note")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "credit note created successfully", result)
+}
+
+func (h *CreditNoteHandler) ListByInvoice(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	notes, err := h.creditNoteService.ListByInvoice(c.Context(), invoiceID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list credit notes")
+	}
+
+	return response.Success(c, fiber.StatusOK, "credit notes retrieved successfully", notes)
+}
+
+func (h *CreditNoteHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid credit note id")
+	}
+
+	note, err := h.creditNoteService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "credit note not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get credit note")
+	}
+
+	return response.Success(c, fiber.StatusOK, "credit note retrieved successfully", note)
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type ExpenseCategoryHandler struct {
+	categoryService *service.ExpenseCategoryService
+}
+
+func NewExpenseCategoryHandler(categoryService *service.ExpenseCategoryService) *ExpenseCategoryHandler {
+	return &ExpenseCategoryHandler{categoryService: categoryService}
+}
+
+func (h *ExpenseCategoryHandler) Create(c *fiber.Ctx) error {
+	var req request.CreateExpenseCategoryRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.categoryService.Create(c.Context(), &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "code is required":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		case "expense category code already exists":
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create expense category")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "expense category created successfully", result)
+}
+
+func (h *ExpenseCategoryHandler) List(c *fiber.Ctx) error {
+	includeInactive := c.Query("include_inactive") == "true"
+
+	categories, err := h.categoryService.List(c.Context(), includeInactive)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list expense categories")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense categories retrieved successfully", categories)
+}
+
+func (h *ExpenseCategoryHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid expense category id")
+	}
+
+	category, err := h.categoryService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "expense category not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get expense category")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense category retrieved successfully", category)
+}
+
+func (h *ExpenseCategoryHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid expense category id")
+	}
+
+	var req request.UpdateExpenseCategoryRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.categoryService.Update(c.Context(), id, &req, userID)
+	if err != nil {
+		if err.Error() == "expense category not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update expense category")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense category updated successfully", result)
+}
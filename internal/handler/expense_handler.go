@@ -36,6 +36,8 @@ func (h *ExpenseHandler) Create(c *fiber.Ctx) error {
 			return response.Error(c, fiber.StatusNotFound, err.Error())
 		case "not a member of this project":
 			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "expense exceeds project budget", "invalid expense category", "expense category is inactive":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to create expense")
 	}
@@ -93,6 +95,9 @@ func (h *ExpenseHandler) Update(c *fiber.Ctx) error {
 			return response.Error(c, fiber.StatusNotFound, err.Error())
 		case "not authorized to update this expense":
 			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "approved expenses cannot be changed", "expense exceeds project budget",
+			"invalid expense category", "expense category is inactive":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to update expense")
 	}
@@ -115,6 +120,8 @@ func (h *ExpenseHandler) Delete(c *fiber.Ctx) error {
 			return response.Error(c, fiber.StatusNotFound, err.Error())
 		case "not authorized to delete this expense":
 			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "approved expenses cannot be deleted":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to delete expense")
 	}
@@ -122,3 +129,81 @@ func (h *ExpenseHandler) Delete(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, "expense deleted successfully", nil)
 }
 
+func (h *ExpenseHandler) Approve(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid expense id")
+	}
+
+	var req request.ApproveExpenseRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.expenseService.Approve(c.Context(), id, userID, req.Notes, req.ProofURL)
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "expense is not pending", "expense exceeds project budget":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to approve expense")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense approved successfully", result)
+}
+
+func (h *ExpenseHandler) Reject(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid expense id")
+	}
+
+	var req request.RejectExpenseRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.expenseService.Reject(c.Context(), id, userID, req.Notes, req.ProofURL)
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "expense is not pending":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to reject expense")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense rejected successfully", result)
+}
+
+func (h *ExpenseHandler) Resubmit(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid expense id")
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.expenseService.Resubmit(c.Context(), id, userID)
+	if err != nil {
+		switch err.Error() {
+		case "expense not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not authorized to resubmit this expense":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "expense is not rejected":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to resubmit expense")
+	}
+
+	return response.Success(c, fiber.StatusOK, "expense resubmitted successfully", result)
+}
+
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type InvoiceDeliveryHandler struct {
+	deliveryService *service.InvoiceDeliveryService
+}
+
+func NewInvoiceDeliveryHandler(deliveryService *service.InvoiceDeliveryService) *InvoiceDeliveryHandler {
+	return &InvoiceDeliveryHandler{deliveryService: deliveryService}
+}
+
+func (h *InvoiceDeliveryHandler) Send(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.SendInvoiceRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.deliveryService.Send(c.Context(), id, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "only approved invoices can be sent":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		case "email delivery is not configured":
+			return response.Error(c, fiber.StatusServiceUnavailable, err.Error())
+		case "email delivery failed":
+			msg := err.Error()
+			if result != nil && result.ErrorMessage != "" {
+				msg += ": " + result.ErrorMessage
+			}
+			return response.Error(c, fiber.StatusBadGateway, msg)
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to send invoice")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice sent successfully", result)
+}
+
+func (h *InvoiceDeliveryHandler) ListByInvoice(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	deliveries, err := h.deliveryService.ListByInvoice(c.Context(), id)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list invoice deliveries")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice deliveries retrieved successfully", deliveries)
+}
@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
 	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
 	"github.com/gilangrmdnii/invoice-backend/internal/service"
 	"github.com/gilangrmdnii/invoice-backend/pkg/response"
 	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
@@ -36,7 +40,12 @@ func (h *InvoiceHandler) Create(c *fiber.Ctx) error {
 			return response.Error(c, fiber.StatusNotFound, err.Error())
 		case "not a member of this project":
 			return response.Error(c, fiber.StatusForbidden, err.Error())
-		case "invalid invoice date format, use YYYY-MM-DD":
+		case "invalid invoice date format, use YYYY-MM-DD",
+			"client not found",
+			"client is inactive",
+			"recipient_name is required",
+			"invalid npwp, must be 15 or 16 digits",
+			"invoice exceeds remaining contract value":
 			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to create invoice")
@@ -45,18 +54,156 @@ func (h *InvoiceHandler) Create(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusCreated, "invoice created successfully", result)
 }
 
+func (h *InvoiceHandler) Clone(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	// The body is optional: an empty request clones with today's date
+	var req request.CloneInvoiceRequest
+	if len(c.Body()) > 0 {
+		if err := validator.ParseAndValidate(c, &req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.invoiceService.Clone(c.Context(), id, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found", "project not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "invalid invoice date format, use YYYY-MM-DD",
+			"items or labels are required",
+			"client not found",
+			"client is inactive",
+			"recipient_name is required",
+			"invalid npwp, must be 15 or 16 digits",
+			"invoice exceeds remaining contract value":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to clone invoice")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "invoice cloned successfully", result)
+}
+
 func (h *InvoiceHandler) List(c *fiber.Ctx) error {
+	var req request.ListInvoicesRequest
+	if err := validator.ParseQueryAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	userID := middleware.GetUserID(c)
 	role := middleware.GetUserRole(c)
 
-	invoices, err := h.invoiceService.List(c.Context(), userID, role)
+	invoices, err := h.invoiceService.List(c.Context(), &req, userID, role)
 	if err != nil {
+		switch err.Error() {
+		case "invalid cursor", "invalid date format, use YYYY-MM-DD":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to list invoices")
 	}
 
 	return response.Success(c, fiber.StatusOK, "invoices retrieved successfully", invoices)
 }
 
+func (h *InvoiceHandler) Aging(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	asOf := time.Now()
+	if v := c.Query("as_of"); v != "" {
+		parsed, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, "invalid as_of format, use YYYY-MM-DD")
+		}
+		asOf = parsed
+	}
+
+	report, err := h.invoiceService.Aging(c.Context(), userID, role, asOf)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get aging report")
+	}
+
+	if c.Query("format") == "csv" {
+		content, err := service.AgingCSV(report, c.Query("group_by", "recipient"))
+		if err != nil {
+			return response.Error(c, fiber.StatusInternalServerError, "failed to export aging report")
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="ar-aging-%s.csv"`, report.AsOf))
+		return c.Send(content)
+	}
+
+	return response.Success(c, fiber.StatusOK, "aging report retrieved successfully", report)
+}
+
+// EFakturExport returns the e-Faktur export report of approved invoices in a
+// date range, or with format=csv the import file itself.
+func (h *InvoiceHandler) EFakturExport(c *fiber.Ctx) error {
+	var req request.EFakturExportRequest
+	if err := validator.ParseQueryAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	report, content, err := h.invoiceService.EFakturExport(c.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid date format, use YYYY-MM-DD", "date_to must not be before date_from",
+			"company NPWP is not set", "company NPWP is invalid":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to export e-Faktur")
+	}
+
+	if req.Format == "csv" {
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="efaktur-%s-%s.csv"`, report.DateFrom, report.DateTo))
+		return c.Send(content)
+	}
+
+	return response.Success(c, fiber.StatusOK, "e-Faktur export retrieved successfully", report)
+}
+
+// SetTaxInvoiceNumber records the tax invoice number (NSFP) of an approved
+// invoice.
+func (h *InvoiceHandler) SetTaxInvoiceNumber(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.SetTaxInvoiceNumberRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.invoiceService.SetTaxInvoiceNumber(c.Context(), id, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "invalid tax invoice number, must be 13 digits",
+			"tax invoice numbers can only be set on approved invoices":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		case "tax invoice number is already used by another invoice":
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to set tax invoice number")
+	}
+
+	return response.Success(c, fiber.StatusOK, "tax invoice number updated successfully", result)
+}
+
 func (h *InvoiceHandler) GetByID(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -74,6 +221,203 @@ func (h *InvoiceHandler) GetByID(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, "invoice retrieved successfully", invoice)
 }
 
+func (h *InvoiceHandler) DownloadPDF(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	content, filename, err := h.invoiceService.RenderPDF(c.Context(), id, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to render invoice pdf")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, filename))
+	return c.Send(content)
+}
+
+// DownloadReceipt returns the receipt (kwitansi) of a payment as a PDF, or as
+// HTML with ?format=html.
+func (h *InvoiceHandler) DownloadReceipt(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid payment id")
+	}
+
+	format := strings.ToLower(c.Query("format", "pdf"))
+	if format != "pdf" && format != "html" {
+		return response.Error(c, fiber.StatusBadRequest, "format must be pdf or html")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	content, filename, err := h.invoiceService.RenderReceipt(c.Context(), invoiceID, paymentID, userID, role, format)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found", "payment not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "receipts are only issued for payments":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to render payment receipt")
+	}
+
+	if format == "html" {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	} else {
+		c.Set(fiber.HeaderContentType, "application/pdf")
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, filename))
+	return c.Send(content)
+}
+
+func (h *InvoiceHandler) ListRevisions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	revisions, err := h.invoiceService.ListRevisions(c.Context(), id, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get invoice revisions")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice revisions retrieved successfully", revisions)
+}
+
+func (h *InvoiceHandler) GetRevision(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+	revision, err := strconv.Atoi(c.Params("revision"))
+	if err != nil || revision < 1 {
+		return response.Error(c, fiber.StatusBadRequest, "invalid revision number")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.invoiceService.GetRevision(c.Context(), id, revision, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found", "revision not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get invoice revision")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice revision retrieved successfully", result)
+}
+
+// DiffRevisions compares ?from= and ?to= revisions. Both are optional and
+// default to the latest revision and the one before it.
+func (h *InvoiceHandler) DiffRevisions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+	from := c.QueryInt("from", 0)
+	to := c.QueryInt("to", 0)
+	if from < 0 || to < 0 {
+		return response.Error(c, fiber.StatusBadRequest, "invalid revision number")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.invoiceService.DiffRevisions(c.Context(), id, from, to, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found", "revision not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "two different revisions are required":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to compare invoice revisions")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice revision diff retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) ListApprovals(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	result, err := h.invoiceService.ListApprovals(c.Context(), id, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get invoice approvals")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice approvals retrieved successfully", result)
+}
+
+func (h *InvoiceHandler) GeneratePDF(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+
+	fileURL, err := h.invoiceService.SavePDF(c.Context(), id, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not authorized to generate invoice pdf":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to generate invoice pdf")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice pdf generated successfully", fiber.Map{
+		"file_url": fileURL,
+	})
+}
+
 func (h *InvoiceHandler) Update(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
 	if err != nil {
@@ -94,7 +438,13 @@ func (h *InvoiceHandler) Update(c *fiber.Ctx) error {
 			return response.Error(c, fiber.StatusNotFound, err.Error())
 		case "not authorized to update this invoice":
 			return response.Error(c, fiber.StatusForbidden, err.Error())
-		case "only pending invoices can be updated":
+		case "only pending or rejected invoices can be updated",
+			"invalid invoice date format",
+			"invalid due date format",
+			"client not found",
+			"client is inactive",
+			"invalid npwp, must be 15 or 16 digits",
+			"invoice exceeds remaining contract value":
 			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to update invoice")
@@ -138,18 +488,24 @@ func (h *InvoiceHandler) Approve(c *fiber.Ctx) error {
 	}
 
 	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
 
-	result, err := h.invoiceService.Approve(c.Context(), id, userID, req.Notes)
+	result, err := h.invoiceService.Approve(c.Context(), id, userID, role, req.Notes)
 	if err != nil {
 		switch err.Error() {
 		case "invoice not found":
 			return response.Error(c, fiber.StatusNotFound, err.Error())
-		case "invoice is not pending":
+		case "not authorized to approve this step", "approver already approved an earlier step":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "invoice is not pending", "invoice has no open approval step":
 			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to approve invoice")
 	}
 
+	if result.Status != string(model.InvoiceStatusApproved) {
+		return response.Success(c, fiber.StatusOK, "approval step recorded, waiting for next approver", result)
+	}
 	return response.Success(c, fiber.StatusOK, "invoice approved successfully", result)
 }
 
@@ -165,13 +521,16 @@ func (h *InvoiceHandler) Reject(c *fiber.Ctx) error {
 	}
 
 	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
 
-	result, err := h.invoiceService.Reject(c.Context(), id, userID, req.Notes)
+	result, err := h.invoiceService.Reject(c.Context(), id, userID, role, req.Notes)
 	if err != nil {
 		switch err.Error() {
 		case "invoice not found":
 			return response.Error(c, fiber.StatusNotFound, err.Error())
-		case "invoice is not pending":
+		case "not authorized to reject this step":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "invoice is not pending", "invoice has no open approval step":
 			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to reject invoice")
@@ -179,3 +538,30 @@ func (h *InvoiceHandler) Reject(c *fiber.Ctx) error {
 
 	return response.Success(c, fiber.StatusOK, "invoice rejected successfully", result)
 }
+
+func (h *InvoiceHandler) Void(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.VoidInvoiceRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.invoiceService.Void(c.Context(), id, userID, req.Reason)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "only approved invoices can be voided", "invoice with payments cannot be voided":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to void invoice")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice voided successfully", result)
+}
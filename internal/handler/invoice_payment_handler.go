@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
@@ -60,22 +61,68 @@ func (h *InvoicePaymentHandler) ListByInvoice(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, "payments retrieved successfully", payments)
 }
 
-func (h *InvoicePaymentHandler) Delete(c *fiber.Ctx) error {
+func (h *InvoicePaymentHandler) Reverse(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
 	paymentID, err := strconv.ParseUint(c.Params("paymentId"), 10, 64)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid payment id")
 	}
 
+	var req request.ReverseInvoicePaymentRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.paymentService.Reverse(c.Context(), invoiceID, paymentID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found", "payment not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "invalid reversal date format, use YYYY-MM-DD",
+			"only payments can be reversed",
+			"payment is already reversed",
+			"reversal exceeds the net paid amount":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to reverse payment")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "payment reversed successfully", result)
+}
+
+func (h *InvoicePaymentHandler) Refund(c *fiber.Ctx) error {
 	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
 	}
 
+	var req request.CreateInvoiceRefundRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	userID := middleware.GetUserID(c)
 
-	if err := h.paymentService.Delete(c.Context(), paymentID, invoiceID, userID); err != nil {
-		return response.Error(c, fiber.StatusInternalServerError, "failed to delete payment")
+	result, err := h.paymentService.Refund(c.Context(), invoiceID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "invalid refund date format, use YYYY-MM-DD",
+			"invoice has no overpaid balance":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "refund amount") {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to record refund")
 	}
 
-	return response.Success(c, fiber.StatusOK, "payment deleted successfully", nil)
+	return response.Success(c, fiber.StatusCreated, "refund recorded successfully", result)
 }
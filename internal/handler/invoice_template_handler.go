@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type InvoiceTemplateHandler struct {
+	templateService *service.InvoiceTemplateService
+}
+
+func NewInvoiceTemplateHandler(templateService *service.InvoiceTemplateService) *InvoiceTemplateHandler {
+	return &InvoiceTemplateHandler{templateService: templateService}
+}
+
+func (h *InvoiceTemplateHandler) Create(c *fiber.Ctx) error {
+	var req request.SaveInvoiceTemplateRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	result, err := h.templateService.Create(c.Context(), &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "items or labels are required",
+			"project not found",
+			"invalid npwp, must be 15 or 16 digits":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create invoice template")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "invoice template created successfully", result)
+}
+
+func (h *InvoiceTemplateHandler) List(c *fiber.Ctx) error {
+	templates, err := h.templateService.List(c.Context(), c.Query("invoice_type"))
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list invoice templates")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice templates retrieved successfully", templates)
+}
+
+func (h *InvoiceTemplateHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice template id")
+	}
+
+	template, err := h.templateService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "invoice template not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get invoice template")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice template retrieved successfully", template)
+}
+
+func (h *InvoiceTemplateHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice template id")
+	}
+
+	var req request.SaveInvoiceTemplateRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+	template, err := h.templateService.Update(c.Context(), id, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice template not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not authorized to update this template":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "items or labels are required",
+			"project not found",
+			"invalid npwp, must be 15 or 16 digits":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update invoice template")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice template updated successfully", template)
+}
+
+func (h *InvoiceTemplateHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice template id")
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+	if err := h.templateService.Delete(c.Context(), id, userID, role); err != nil {
+		switch err.Error() {
+		case "invoice template not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not authorized to delete this template":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to delete invoice template")
+	}
+
+	return response.Success(c, fiber.StatusOK, "invoice template deleted successfully", nil)
+}
+
+func (h *InvoiceTemplateHandler) CreateInvoice(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice template id")
+	}
+
+	var req request.CreateInvoiceFromTemplateRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+	role := middleware.GetUserRole(c)
+	result, err := h.templateService.CreateInvoice(c.Context(), id, &req, userID, role)
+	if err != nil {
+		switch err.Error() {
+		case "invoice template not found", "project not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "not a member of this project":
+			return response.Error(c, fiber.StatusForbidden, err.Error())
+		case "project_id is required",
+			"invalid invoice date format, use YYYY-MM-DD",
+			"items or labels are required",
+			"recipient_name is required",
+			"client not found",
+			"client is inactive",
+			"invalid npwp, must be 15 or 16 digits",
+			"invoice exceeds remaining contract value":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create invoice from template")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "invoice created successfully", result)
+}
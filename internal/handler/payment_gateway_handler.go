@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type PaymentGatewayHandler struct {
+	gatewayService *service.PaymentGatewayService
+}
+
+func NewPaymentGatewayHandler(gatewayService *service.PaymentGatewayService) *PaymentGatewayHandler {
+	return &PaymentGatewayHandler{gatewayService: gatewayService}
+}
+
+func (h *PaymentGatewayHandler) CreateCharge(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.CreatePaymentChargeRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.gatewayService.CreateCharge(c.Context(), invoiceID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "payment gateway is not configured":
+			return response.Error(c, fiber.StatusServiceUnavailable, err.Error())
+		case "payment gateway rejected the charge":
+			return response.Error(c, fiber.StatusBadGateway, err.Error())
+		case "invoice must be approved before recording payments",
+			"invoice is already fully paid",
+			"bank is required for virtual account payments",
+			"invoice already has an active charge for this method":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "payment amount (") {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create payment charge")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "payment charge created successfully", result)
+}
+
+func (h *PaymentGatewayHandler) ListByInvoice(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	charges, err := h.gatewayService.ListByInvoice(c.Context(), invoiceID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list payment charges")
+	}
+
+	return response.Success(c, fiber.StatusOK, "payment charges retrieved successfully", charges)
+}
+
+// Webhook receives payment gateway callbacks. It is not behind JWT auth; the
+// provider verifies the request signature instead.
+func (h *PaymentGatewayHandler) Webhook(c *fiber.Ctx) error {
+	result, err := h.gatewayService.HandleWebhook(c.Context(), c.Body(), func(key string) string {
+		return c.Get(key)
+	})
+	if err != nil {
+		switch {
+		case err.Error() == "payment gateway is not configured":
+			return response.Error(c, fiber.StatusServiceUnavailable, err.Error())
+		case err.Error() == "invalid webhook signature":
+			return response.Error(c, fiber.StatusUnauthorized, err.Error())
+		case strings.HasPrefix(err.Error(), "invalid webhook payload"):
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to process webhook")
+	}
+
+	return response.Success(c, fiber.StatusOK, "webhook received", result)
+}
+
+// ListWebhookEvents lists received gateway callbacks, e.g. ?result=FAILED for
+// the ones that could not be applied.
+func (h *PaymentGatewayHandler) ListWebhookEvents(c *fiber.Ctx) error {
+	var req request.ListPaymentWebhookEventsRequest
+	if err := validator.ParseQueryAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	events, err := h.gatewayService.ListWebhookEvents(c.Context(), &req)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list payment webhook events")
+	}
+
+	return response.Success(c, fiber.StatusOK, "payment webhook events retrieved successfully", events)
+}
+
+func (h *PaymentGatewayHandler) SimulatePayment(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid payment charge id")
+	}
+
+	var req request.SimulatePaymentChargeRequest
+	if len(c.Body()) > 0 {
+		if err := validator.ParseAndValidate(c, &req); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	result, err := h.gatewayService.SimulatePayment(c.Context(), id, &req)
+	if err != nil {
+		switch err.Error() {
+		case "payment charge not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "payment simulation requires the mock payment provider":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to simulate payment")
+	}
+
+	return response.Success(c, fiber.StatusOK, "payment simulated", result)
+}
@@ -29,6 +29,9 @@ func (h *ProjectHandler) Create(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
 	result, err := h.projectService.Create(c.Context(), &req, userID)
 	if err != nil {
+		if err.Error() == "client not found" {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to create project")
 	}
 
@@ -77,8 +80,11 @@ func (h *ProjectHandler) Update(c *fiber.Ctx) error {
 
 	project, err := h.projectService.Update(c.Context(), id, &req)
 	if err != nil {
-		if err.Error() == "project not found" {
+		switch err.Error() {
+		case "project not found":
 			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "client not found":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
 		}
 		return response.Error(c, fiber.StatusInternalServerError, "failed to update project")
 	}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/pkg/response"
+	"github.com/gilangrmdnii/invoice-backend/pkg/validator"
+)
+
+type WithholdingCertificateHandler struct {
+	certService *service.WithholdingCertificateService
+}
+
+func NewWithholdingCertificateHandler(certService *service.WithholdingCertificateService) *WithholdingCertificateHandler {
+	return &WithholdingCertificateHandler{certService: certService}
+}
+
+func (h *WithholdingCertificateHandler) Create(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	var req request.CreateWithholdingCertificateRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.certService.Create(c.Context(), invoiceID, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "invoice not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "withholding certificates can only be recorded for approved invoices",
+			"invoice has no withholding for this tax type",
+			"invalid certificate date format, use YYYY-MM-DD",
+			"certificate_date is required with a certificate number":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		case "a withholding certificate for this tax is already recorded":
+			return response.Error(c, fiber.StatusConflict, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to create withholding certificate")
+	}
+
+	return response.Success(c, fiber.StatusCreated, "withholding certificate created successfully", result)
+}
+
+func (h *WithholdingCertificateHandler) ListByInvoice(c *fiber.Ctx) error {
+	invoiceID, err := strconv.ParseUint(c.Params("invoiceId"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid invoice id")
+	}
+
+	certs, err := h.certService.ListByInvoice(c.Context(), invoiceID)
+	if err != nil {
+		return response.Error(c, fiber.StatusInternalServerError, "failed to list withholding certificates")
+	}
+
+	return response.Success(c, fiber.StatusOK, "withholding certificates retrieved successfully", certs)
+}
+
+func (h *WithholdingCertificateHandler) GetByID(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid withholding certificate id")
+	}
+
+	cert, err := h.certService.GetByID(c.Context(), id)
+	if err != nil {
+		if err.Error() == "withholding certificate not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get withholding certificate")
+	}
+
+	return response.Success(c, fiber.StatusOK, "withholding certificate retrieved successfully", cert)
+}
+
+func (h *WithholdingCertificateHandler) Update(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid withholding certificate id")
+	}
+
+	var req request.UpdateWithholdingCertificateRequest
+	if err := validator.ParseAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	userID := middleware.GetUserID(c)
+
+	result, err := h.certService.Update(c.Context(), id, &req, userID)
+	if err != nil {
+		switch err.Error() {
+		case "withholding certificate not found":
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		case "invalid certificate date format, use YYYY-MM-DD",
+			"certificate_date is required with a certificate number":
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to update withholding certificate")
+	}
+
+	return response.Success(c, fiber.StatusOK, "withholding certificate updated successfully", result)
+}
+
+func (h *WithholdingCertificateHandler) Delete(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, "invalid withholding certificate id")
+	}
+
+	userID := middleware.GetUserID(c)
+	if err := h.certService.Delete(c.Context(), id, userID); err != nil {
+		if err.Error() == "withholding certificate not found" {
+			return response.Error(c, fiber.StatusNotFound, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to delete withholding certificate")
+	}
+
+	return response.Success(c, fiber.StatusOK, "withholding certificate deleted successfully", nil)
+}
+
+func (h *WithholdingCertificateHandler) Report(c *fiber.Ctx) error {
+	var req request.WithholdingReportRequest
+	if err := validator.ParseQueryAndValidate(c, &req); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	report, err := h.certService.Report(c.Context(), &req)
+	if err != nil {
+		if err.Error() == "invalid period format, use YYYY-MM" {
+			return response.Error(c, fiber.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, fiber.StatusInternalServerError, "failed to get withholding report")
+	}
+
+	return response.Success(c, fiber.StatusOK, "withholding report retrieved successfully", report)
+}
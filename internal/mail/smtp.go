@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// Sender delivers email messages.
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPSender sends mail through an SMTP server. With implicit TLS (SMTPS,
+// port 465) the connection is encrypted from the start; otherwise STARTTLS is
+// used when the server offers it. Authentication is only attempted when a
+// username is set, so it also works against a local fake SMTP server.
+type SMTPSender struct {
+	host        string
+	port        int
+	username    string
+	password    string
+	implicitTLS bool
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+}
+
+// NewSMTPSender creates a sender. Implicit TLS is used when implicitTLS is set
+// or the port is 465.
+func NewSMTPSender(host string, port int, username, password string, implicitTLS bool) *SMTPSender {
+	return &SMTPSender{
+		host:        host,
+		port:        port,
+		username:    username,
+		password:    password,
+		implicitTLS: implicitTLS || port == 465,
+		tlsConfig:   &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+		timeout:     30 * time.Second,
+	}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	body, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("encode message: %w", err)
+	}
+
+	addr := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	if s.implicitTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok && !s.implicitTLS {
+		if err := c.StartTLS(s.tlsConfig); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(msg.From.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	for _, rcpt := range msg.Recipients() {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("smtp rcpt %s: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("smtp write: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp data close: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,279 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	netmail "net/mail"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSMTPServer is a minimal in-process SMTP server that accepts every
+// message and records the envelope and data of each one.
+type fakeSMTPServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	received []receivedMail
+	done     chan struct{}
+}
+
+type receivedMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+func startFakeSMTPServer(t *testing.T, tlsConfig *tls.Config) *fakeSMTPServer {
+	t.Helper()
+	var ln net.Listener
+	var err error
+	if tlsConfig != nil {
+		ln, err = tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{ln: ln, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() {
+		ln.Close()
+		<-s.done
+	})
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) messages() []receivedMail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedMail(nil), s.received...)
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		io.WriteString(conn, line+"\r\n")
+	}
+
+	reply("220 localhost fake ESMTP")
+	var cur receivedMail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			cur = receivedMail{from: envelopeAddress(line[len("MAIL FROM:"):])}
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			cur.to = append(cur.to, envelopeAddress(line[len("RCPT TO:"):]))
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data bytes.Buffer
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			cur.data = data.Bytes()
+			s.mu.Lock()
+			s.received = append(s.received, cur)
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// envelopeAddress extracts the path from a MAIL FROM or RCPT TO argument,
+// dropping any ESMTP parameters such as BODY=8BITMIME.
+func envelopeAddress(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if end := strings.IndexByte(arg, '>'); end >= 0 {
+		arg = arg[:end]
+	}
+	return strings.TrimPrefix(arg, "<")
+}
+
+func testMessage() *Message {
+	return &Message{
+		From:    netmail.Address{Name: "PT Contoh", Address: "invoice@example.com"},
+		To:      []string{"client@example.com"},
+		Cc:      []string{"finance@example.com"},
+		Subject: "Invoice 001/INV/2026 - PT Contoh",
+		Text:    "Terlampir invoice 001/INV/2026.",
+		HTML:    "<p>Terlampir invoice 001/INV/2026.</p>",
+		Attachments: []Attachment{{
+			Filename:    "001-INV-2026.pdf",
+			ContentType: "application/pdf",
+			Data:        bytes.Repeat([]byte("%PDF-1.4 fake content\n"), 20),
+		}},
+	}
+}
+
+// checkDelivered verifies the envelope, headers and PDF attachment of the one
+// message the server received.
+func checkDelivered(t *testing.T, srv *fakeSMTPServer, msg *Message) {
+	t.Helper()
+	got := srv.messages()
+	if len(got) != 1 {
+		t.Fatalf("received %d messages, want 1", len(got))
+	}
+	rcv := got[0]
+
+	if rcv.from != "invoice@example.com" {
+		t.Errorf("MAIL FROM = %q, want invoice@example.com", rcv.from)
+	}
+	if strings.Join(rcv.to, ",") != "client@example.com,finance@example.com" {
+		t.Errorf("RCPT TO = %v, want To and Cc recipients", rcv.to)
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(rcv.data))
+	if err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	if subject != msg.Subject {
+		t.Errorf("Subject = %q, want %q", subject, msg.Subject)
+	}
+	if to := parsed.Header.Get("To"); to != "client@example.com" {
+		t.Errorf("To = %q", to)
+	}
+	if cc := parsed.Header.Get("Cc"); cc != "finance@example.com" {
+		t.Errorf("Cc = %q", cc)
+	}
+	from, err := parsed.Header.AddressList("From")
+	if err != nil || len(from) != 1 || from[0].Address != "invoice@example.com" || from[0].Name != "PT Contoh" {
+		t.Errorf("From = %v (%v)", from, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q (%v)", parsed.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(parsed.Body, params["boundary"])
+
+	body, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("body part: %v", err)
+	}
+	if ct := body.Header.Get("Content-Type"); !strings.HasPrefix(ct, "multipart/alternative") {
+		t.Errorf("body Content-Type = %q", ct)
+	}
+
+	att, err := mr.NextPart()
+	if err != nil {
+		t.Fatalf("attachment part: %v", err)
+	}
+	if ct := att.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/pdf") {
+		t.Errorf("attachment Content-Type = %q", ct)
+	}
+	if att.FileName() != "001-INV-2026.pdf" {
+		t.Errorf("attachment filename = %q", att.FileName())
+	}
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, att))
+	if err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+	if !bytes.Equal(data, msg.Attachments[0].Data) {
+		t.Errorf("attachment content differs: got %d bytes, want %d", len(data), len(msg.Attachments[0].Data))
+	}
+
+	if _, err := mr.NextPart(); err != io.EOF {
+		t.Errorf("unexpected extra part (%v)", err)
+	}
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	srv := startFakeSMTPServer(t, nil)
+
+	sender := NewSMTPSender("127.0.0.1", srv.port(), "", "", false)
+	msg := testMessage()
+	if err := sender.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	checkDelivered(t, srv, msg)
+}
+
+func TestSMTPSenderImplicitTLS(t *testing.T) {
+	// Borrow the self-signed certificate of an httptest TLS server
+	ts := httptest.NewTLSServer(nil)
+	cert := ts.TLS.Certificates[0]
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+	ts.Close()
+
+	srv := startFakeSMTPServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+
+	sender := NewSMTPSender("127.0.0.1", srv.port(), "", "", true)
+	sender.tlsConfig = &tls.Config{ServerName: "127.0.0.1", RootCAs: pool, MinVersion: tls.VersionTLS12}
+	msg := testMessage()
+	if err := sender.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	checkDelivered(t, srv, msg)
+}
+
+func TestNewSMTPSenderImplicitTLSOnPort465(t *testing.T) {
+	for _, tt := range []struct {
+		port     int
+		flag     bool
+		implicit bool
+	}{
+		{587, false, false},
+		{465, false, true},
+		{2525, true, true},
+	} {
+		s := NewSMTPSender("smtp.example.com", tt.port, "", "", tt.flag)
+		if s.implicitTLS != tt.implicit {
+			t.Errorf("port %d, flag %v: implicitTLS = %v, want %v", tt.port, tt.flag, s.implicitTLS, tt.implicit)
+		}
+	}
+}
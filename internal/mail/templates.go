@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+var (
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+)
+
+// InvoiceEmailData holds the pre-formatted values used by the invoice email templates.
+type InvoiceEmailData struct {
+	CompanyName       string
+	RecipientName     string
+	Attention         string
+	InvoiceNumber     string
+	InvoiceDate       string
+	DueDate           string
+	ProjectName       string
+	Amount            string
+	Message           string
+	BankName          string
+	BankAccountNumber string
+	BankAccountName   string
+}
+
+// RenderInvoiceEmail renders the subject, text and HTML bodies of the invoice
+// email in the given language (ID or EN).
+func RenderInvoiceEmail(lang string, data InvoiceEmailData) (subject, text, html string, err error) {
+	name := "invoice_id"
+	subject = fmt.Sprintf("Invoice %s - %s", data.InvoiceNumber, data.CompanyName)
+	if lang == "EN" {
+		name = "invoice_en"
+	}
+
+	var textBuf, htmlBuf bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&textBuf, name+".txt", data); err != nil {
+		return "", "", "", fmt.Errorf("render text template: %w", err)
+	}
+	if err := htmlTemplates.ExecuteTemplate(&htmlBuf, name+".html", data); err != nil {
+		return "", "", "", fmt.Errorf("render html template: %w", err)
+	}
+	return subject, textBuf.String(), htmlBuf.String(), nil
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// DefaultApproverRoles approve invoices that no approval policy applies to.
+var DefaultApproverRoles = []string{string(RoleFinance), string(RoleOwner)}
+
+// ApprovalPolicy defines the approval chain for invoices of at least
+// MinAmount. A nil InvoiceType applies to all invoice types.
+type ApprovalPolicy struct {
+	ID          uint64               `json:"id"`
+	Name        string               `json:"name"`
+	InvoiceType *InvoiceType         `json:"invoice_type,omitempty"`
+	MinAmount   money.Money          `json:"min_amount"`
+	IsActive    bool                 `json:"is_active"`
+	Steps       []ApprovalPolicyStep `json:"steps"`
+	CreatedBy   uint64               `json:"created_by"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+type ApprovalPolicyStep struct {
+	ID            uint64   `json:"id"`
+	PolicyID      uint64   `json:"policy_id"`
+	StepOrder     int      `json:"step_order"`
+	ApproverRoles []string `json:"approver_roles"`
+}
+
+type ApprovalStepStatus string
+
+const (
+	ApprovalStepPending  ApprovalStepStatus = "PENDING"
+	ApprovalStepApproved ApprovalStepStatus = "APPROVED"
+	ApprovalStepRejected ApprovalStepStatus = "REJECTED"
+	// Steps left after a rejection
+	ApprovalStepSkipped ApprovalStepStatus = "SKIPPED"
+)
+
+// InvoiceApprovalStep is one step of an invoice's approval chain. Round is
+// incremented each time the invoice is resubmitted.
+type InvoiceApprovalStep struct {
+	ID            uint64             `json:"id"`
+	InvoiceID     uint64             `json:"invoice_id"`
+	Round         int                `json:"round"`
+	StepOrder     int                `json:"step_order"`
+	PolicyID      *uint64            `json:"policy_id,omitempty"`
+	ApproverRoles []string           `json:"approver_roles"`
+	Status        ApprovalStepStatus `json:"status"`
+	ActedBy       *uint64            `json:"acted_by,omitempty"`
+	ActorName     string             `json:"actor_name,omitempty"`
+	ActedAt       *time.Time         `json:"acted_at,omitempty"`
+	Notes         string             `json:"notes,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// CanAct reports whether a user with the given role may approve or reject the step.
+func (s *InvoiceApprovalStep) CanAct(role string) bool {
+	for _, r := range s.ApproverRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
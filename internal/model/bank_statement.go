@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type BankTransactionDirection string
+
+const (
+	BankTransactionCredit BankTransactionDirection = "CREDIT"
+	BankTransactionDebit  BankTransactionDirection = "DEBIT"
+)
+
+type BankTransactionStatus string
+
+const (
+	BankTransactionUnmatched BankTransactionStatus = "UNMATCHED"
+	BankTransactionMatched   BankTransactionStatus = "MATCHED"
+	BankTransactionIgnored   BankTransactionStatus = "IGNORED"
+)
+
+type BankStatement struct {
+	ID               uint64     `json:"id"`
+	Format           string     `json:"format"`
+	AccountNumber    string     `json:"account_number"`
+	FileName         string     `json:"file_name"`
+	PeriodStart      *time.Time `json:"period_start,omitempty"`
+	PeriodEnd        *time.Time `json:"period_end,omitempty"`
+	TransactionCount int        `json:"transaction_count"`
+	DuplicateCount   int        `json:"duplicate_count"`
+	MatchedCount     int        `json:"matched_count"`
+	UnmatchedCount   int        `json:"unmatched_count"`
+	UploadedBy       uint64     `json:"uploaded_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type BankTransaction struct {
+	ID              uint64                   `json:"id"`
+	StatementID     uint64                   `json:"statement_id"`
+	TransactionDate time.Time                `json:"transaction_date"`
+	Description     string                   `json:"description"`
+	Reference       string                   `json:"reference,omitempty"`
+	Amount          money.Money              `json:"amount"`
+	Direction       BankTransactionDirection `json:"direction"`
+	Status          BankTransactionStatus    `json:"status"`
+	InvoiceID       *uint64                  `json:"invoice_id,omitempty"`
+	InvoiceNumber   string                   `json:"invoice_number,omitempty"`
+	PaymentID       *uint64                  `json:"payment_id,omitempty"`
+	MatchedBy       *uint64                  `json:"matched_by,omitempty"`
+	MatchedAt       *time.Time               `json:"matched_at,omitempty"`
+	Fingerprint     string                   `json:"-"`
+	CreatedAt       time.Time                `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// BillingSchedule is the contract of a project: its value before tax and the
+// payment terms it is billed in.
+type BillingSchedule struct {
+	ProjectID     uint64        `json:"project_id"`
+	ContractValue money.Money   `json:"contract_value"`
+	PPNPercentage float64       `json:"ppn_percentage"`
+	PPHPercentage float64       `json:"pph_percentage"`
+	Notes         string        `json:"notes,omitempty"`
+	UpdatedBy     uint64        `json:"updated_by"`
+	Terms         []BillingTerm `json:"terms,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type BillingTerm struct {
+	ID           uint64      `json:"id"`
+	ProjectID    uint64      `json:"project_id"`
+	InvoiceType  InvoiceType `json:"invoice_type"`
+	Description  string      `json:"description"`
+	Percentage   float64     `json:"percentage"`
+	Amount       money.Money `json:"amount"`
+	ExpectedDate *time.Time  `json:"expected_date,omitempty"`
+	SortOrder    int         `json:"sort_order"`
+
+	// Active (not rejected or void) invoice generated from this term, if any
+	InvoiceID     *uint64       `json:"invoice_id,omitempty"`
+	InvoiceNumber string        `json:"invoice_number,omitempty"`
+	InvoiceStatus InvoiceStatus `json:"invoice_status,omitempty"`
+}
+
+// IsContractInvoiceType reports whether invoices of this type bill against the
+// project contract. MEALS and ADDITIONAL are billed outside the contract.
+func IsContractInvoiceType(t InvoiceType) bool {
+	switch t {
+	case InvoiceTypeDP, InvoiceTypeTOP1, InvoiceTypeTOP2, InvoiceTypeTOP3, InvoiceTypeFinalPayment:
+		return true
+	}
+	return false
+}
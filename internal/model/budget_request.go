@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type BudgetRequestStatus string
 
@@ -14,7 +18,7 @@ type BudgetRequest struct {
 	ID               uint64              `json:"id"`
 	ProjectID        uint64              `json:"project_id"`
 	RequestedBy      uint64              `json:"requested_by"`
-	Amount           float64             `json:"amount"`
+	Amount           money.Money         `json:"amount"`
 	Reason           string              `json:"reason"`
 	ProofURL         *string             `json:"proof_url,omitempty"`
 	Status           BudgetRequestStatus `json:"status"`
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+type Client struct {
+	ID               uint64          `json:"id"`
+	Name             string          `json:"name"`
+	NPWP             string          `json:"npwp,omitempty"`
+	BillingAddress   string          `json:"billing_address,omitempty"`
+	Email            string          `json:"email,omitempty"`
+	Phone            string          `json:"phone,omitempty"`
+	PaymentTermsDays int             `json:"payment_terms_days"`
+	Notes            string          `json:"notes,omitempty"`
+	IsActive         bool            `json:"is_active"`
+	CreatedBy        uint64          `json:"created_by"`
+	Contacts         []ClientContact `json:"contacts,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+type ClientContact struct {
+	ID        uint64 `json:"id"`
+	ClientID  uint64 `json:"client_id"`
+	Name      string `json:"name"`
+	Position  string `json:"position,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	IsPrimary bool   `json:"is_primary"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// PrimaryContact returns the contact flagged as primary, or the first contact.
+func (c *Client) PrimaryContact() *ClientContact {
+	for i := range c.Contacts {
+		if c.Contacts[i].IsPrimary {
+			return &c.Contacts[i]
+		}
+	}
+	if len(c.Contacts) > 0 {
+		return &c.Contacts[0]
+	}
+	return nil
+}
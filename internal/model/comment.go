@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// CommentEntityType is the kind of record a comment thread is attached to.
+type CommentEntityType string
+
+const (
+	CommentOnInvoice       CommentEntityType = "INVOICE"
+	CommentOnBudgetRequest CommentEntityType = "BUDGET_REQUEST"
+	CommentOnExpense       CommentEntityType = "EXPENSE"
+	CommentOnQCReport      CommentEntityType = "QC_REPORT"
+)
+
+// Comment is a message on an invoice, budget request, expense or QC report.
+// ParentID is nil for the first comment of a thread and points at it for
+// replies. ProjectID is the project of the record, used for visibility.
+type Comment struct {
+	ID          uint64            `json:"id"`
+	EntityType  CommentEntityType `json:"entity_type"`
+	EntityID    uint64            `json:"entity_id"`
+	ProjectID   uint64            `json:"project_id"`
+	ParentID    *uint64           `json:"parent_id,omitempty"`
+	Body        string            `json:"body"`
+	CreatedBy   uint64            `json:"created_by"`
+	AuthorName  string            `json:"author_name"`
+	Attachments []string          `json:"attachments,omitempty"`
+	MentionIDs  []uint64          `json:"mention_ids,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type CreditNote struct {
+	ID               uint64           `json:"id"`
+	CreditNoteNumber string           `json:"credit_note_number"`
+	InvoiceID        uint64           `json:"invoice_id"`
+	CreditDate       time.Time        `json:"credit_date"`
+	Reason           string           `json:"reason"`
+	Subtotal         money.Money      `json:"subtotal"`
+	DiscountAmount   money.Money      `json:"discount_amount"` // share of the invoice discount
+	DPPAmount        money.Money      `json:"dpp_amount"`
+	PPNMode          PPNMode          `json:"ppn_mode"`
+	PPNBase          money.Money      `json:"ppn_base"` // DPP the PPN rate is applied to
+	PPNPercentage    float64          `json:"ppn_percentage"`
+	PPNAmount        money.Money      `json:"ppn_amount"`
+	PPHPercentage    float64          `json:"pph_percentage"`
+	PPHAmount        money.Money      `json:"pph_amount"`
+	PPH21Percentage  float64          `json:"pph21_percentage"`
+	PPH21Amount      money.Money      `json:"pph21_amount"`
+	Amount           money.Money      `json:"amount"`
+	CreatedBy        uint64           `json:"created_by"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Items            []CreditNoteItem `json:"items,omitempty"`
+}
+
+type CreditNoteItem struct {
+	ID              uint64      `json:"id"`
+	CreditNoteID    uint64      `json:"credit_note_id"`
+	Description     string      `json:"description"`
+	Quantity        float64     `json:"quantity"`
+	Unit            string      `json:"unit"`
+	UnitPrice       money.Money `json:"unit_price"`
+	DiscountPercent float64     `json:"discount_percent"`
+	DiscountAmount  money.Money `json:"discount_amount"`
+	Subtotal        money.Money `json:"subtotal"`
+	PPNCode         PPNCode     `json:"ppn_code"`
+	PPHCode         PPHCode     `json:"pph_code"`
+	SortOrder       int         `json:"sort_order"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
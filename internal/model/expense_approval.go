@@ -10,10 +10,12 @@ const (
 )
 
 type ExpenseApproval struct {
-	ID         uint64         `json:"id"`
-	ExpenseID  uint64         `json:"expense_id"`
-	ApprovedBy uint64         `json:"approved_by"`
-	Status     ApprovalStatus `json:"status"`
-	Notes      string         `json:"notes,omitempty"`
-	CreatedAt  time.Time      `json:"created_at"`
+	ID           uint64         `json:"id"`
+	ExpenseID    uint64         `json:"expense_id"`
+	ApprovedBy   uint64         `json:"approved_by"`
+	ApproverName string         `json:"approver_name"`
+	Status       ApprovalStatus `json:"status"`
+	Notes        string         `json:"notes,omitempty"`
+	ProofURL     string         `json:"proof_url,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
 }
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// ExpenseCategoryBudgetRequest is the category of the expenses recorded when
+// a budget request is approved.
+const ExpenseCategoryBudgetRequest = "BUDGET_REQUEST"
+
+// ExpenseCategory is an entry of the expense category master. Expenses store
+// its Code, and ReportCategory is the FinCat* bucket the finance report
+// breakdown puts them in.
+type ExpenseCategory struct {
+	ID             uint64    `json:"id"`
+	Code           string    `json:"code"`
+	LabelID        string    `json:"label_id"`
+	LabelEN        string    `json:"label_en"`
+	ReportCategory string    `json:"report_category"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -49,7 +49,7 @@ type FinanceManualExpense struct {
 }
 
 // Aggregation category constants for Finance Report breakdown.
-// Maps to both Expense.Category values AND FinanceManualExpense.Category values.
+// Expenses reach them through ExpenseCategory.ReportCategory, FinanceManualExpense.Category values are normalized to them.
 const (
 	FinCatSPV         = "SPV"
 	FinCatUangMakan   = "UANG_MAKAN"
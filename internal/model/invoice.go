@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type InvoiceType string
 
@@ -20,6 +24,18 @@ const (
 	InvoiceStatusPending  InvoiceStatus = "PENDING"
 	InvoiceStatusApproved InvoiceStatus = "APPROVED"
 	InvoiceStatusRejected InvoiceStatus = "REJECTED"
+	InvoiceStatusVoid     InvoiceStatus = "VOID"
+)
+
+// PPNMode selects the base PPN is charged on.
+type PPNMode string
+
+const (
+	// PPNModeStandard charges PPN on the DPP itself
+	PPNModeStandard PPNMode = "STANDARD"
+	// PPNModeNilaiLain charges PPN on a DPP nilai lain of 11/12 of the DPP,
+	// normally at 12% (PMK 131/2024)
+	PPNModeNilaiLain PPNMode = "DPP_NILAI_LAIN"
 )
 
 type Invoice struct {
@@ -27,28 +43,43 @@ type Invoice struct {
 	InvoiceNumber    string        `json:"invoice_number"`
 	InvoiceType      InvoiceType   `json:"invoice_type"`
 	ProjectID        uint64        `json:"project_id"`
-	Amount           float64       `json:"amount"`
-	PaidAmount       float64       `json:"paid_amount"`
+	ClientID         *uint64       `json:"client_id,omitempty"`
+	BillingTermID    *uint64       `json:"billing_term_id,omitempty"`
+	Amount           money.Money   `json:"amount"`
+	PaidAmount       money.Money   `json:"paid_amount"`
+	CreditedAmount   money.Money   `json:"credited_amount"`
 	Status           InvoiceStatus `json:"status"`
 	PaymentStatus    PaymentStatus `json:"payment_status"`
 	FileURL          string        `json:"file_url,omitempty"`
 	RecipientName    string        `json:"recipient_name"`
 	RecipientAddress string        `json:"recipient_address,omitempty"`
+	RecipientNPWP    string        `json:"recipient_npwp,omitempty"`
+	TaxInvoiceNumber string        `json:"tax_invoice_number,omitempty"` // NSFP, 13 digits
 	Attention        string        `json:"attention,omitempty"`
 	PONumber         string        `json:"po_number,omitempty"`
 	InvoiceDate      time.Time     `json:"invoice_date"`
 	DueDate          *time.Time    `json:"due_date,omitempty"`
 	DPPercentage     *float64      `json:"dp_percentage,omitempty"`
-	Subtotal       float64 `json:"subtotal"`
+	Subtotal       money.Money `json:"subtotal"` // after line discounts
+	DiscountPercent float64    `json:"discount_percent"`
+	DiscountAmount money.Money `json:"discount_amount"`
+	DPPAmount      money.Money `json:"dpp_amount"` // subtotal less the invoice discount
+	PPNMode        PPNMode     `json:"ppn_mode"`
+	PPNBase        money.Money `json:"ppn_base"` // DPP the PPN rate is applied to
 	PPNPercentage  float64 `json:"ppn_percentage"`
-	PPNAmount      float64 `json:"ppn_amount"`
-	PPHPercentage  float64 `json:"pph_percentage"`
-	PPHAmount      float64 `json:"pph_amount"`
+	PPNAmount      money.Money `json:"ppn_amount"`
+	PPHPercentage  float64 `json:"pph_percentage"` // PPh 23
+	PPHAmount      money.Money `json:"pph_amount"`
+	PPH21Percentage float64    `json:"pph21_percentage"`
+	PPH21Amount    money.Money `json:"pph21_amount"`
 	Notes            string        `json:"notes,omitempty"`
 	Language         string        `json:"language"`
 	CreatedBy        uint64        `json:"created_by"`
 	ApprovedBy       *uint64       `json:"approved_by,omitempty"`
 	RejectNotes      string        `json:"reject_notes,omitempty"`
+	VoidReason       string        `json:"void_reason,omitempty"`
+	VoidedBy         *uint64       `json:"voided_by,omitempty"`
+	VoidedAt         *time.Time    `json:"voided_at,omitempty"`
 	CreatedAt        time.Time     `json:"created_at"`
 	UpdatedAt        time.Time     `json:"updated_at"`
 }
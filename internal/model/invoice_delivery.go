@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending DeliveryStatus = "PENDING"
+	DeliveryStatusSent    DeliveryStatus = "SENT"
+	DeliveryStatusFailed  DeliveryStatus = "FAILED"
+)
+
+type InvoiceDelivery struct {
+	ID           uint64         `json:"id"`
+	InvoiceID    uint64         `json:"invoice_id"`
+	Channel      string         `json:"channel"`
+	Recipients   []string       `json:"recipients"`
+	Cc           []string       `json:"cc,omitempty"`
+	Subject      string         `json:"subject"`
+	Status       DeliveryStatus `json:"status"`
+	ErrorMessage string         `json:"error_message,omitempty"`
+	SentBy       uint64         `json:"sent_by"`
+	SentAt       *time.Time     `json:"sent_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
@@ -1,18 +1,79 @@
 package model
 
-import "time"
+import (
+	"sort"
+	"time"
 
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// PPNCode selects whether PPN applies to an invoice line.
+type PPNCode string
+
+const (
+	PPNCodeStandard PPNCode = "STANDARD"
+	PPNCodeExempt   PPNCode = "EXEMPT"
+)
+
+// PPHCode selects the income tax withheld on an invoice line.
+type PPHCode string
+
+const (
+	PPHCode23   PPHCode = "PPH23"
+	PPHCode21   PPHCode = "PPH21"
+	PPHCodeNone PPHCode = "NONE"
+)
+
+// InvoiceItem is a line or label of an invoice. Subtotal is unit price ×
+// quantity before the line discount. A line with a discount percentage has
+// its discount amount computed from it; otherwise the amount is a fixed
+// discount.
 type InvoiceItem struct {
-	ID          uint64        `json:"id"`
-	InvoiceID   uint64        `json:"invoice_id"`
-	ParentID    *uint64       `json:"parent_id,omitempty"`
-	IsLabel     bool          `json:"is_label"`
-	Description string        `json:"description"`
-	Quantity    float64       `json:"quantity"`
-	Unit        string        `json:"unit"`
-	UnitPrice   float64       `json:"unit_price"`
-	Subtotal    float64       `json:"subtotal"`
-	SortOrder   int           `json:"sort_order"`
-	CreatedAt   time.Time     `json:"created_at"`
-	Children    []InvoiceItem `json:"-"` // transient, used during create
+	ID              uint64        `json:"id"`
+	InvoiceID       uint64        `json:"invoice_id"`
+	ParentID        *uint64       `json:"parent_id,omitempty"`
+	IsLabel         bool          `json:"is_label"`
+	Description     string        `json:"description"`
+	Quantity        float64       `json:"quantity"`
+	Unit            string        `json:"unit"`
+	UnitPrice       money.Money   `json:"unit_price"`
+	DiscountPercent float64       `json:"discount_percent"`
+	DiscountAmount  money.Money   `json:"discount_amount"`
+	Subtotal        money.Money   `json:"subtotal"`
+	PPNCode         PPNCode       `json:"ppn_code"`
+	PPHCode         PPHCode       `json:"pph_code"`
+	SortOrder       int           `json:"sort_order"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Children        []InvoiceItem `json:"-"` // transient, used during create
+}
+
+// NestInvoiceItems returns the top-level items of a stored invoice in sort
+// order, with the children of each label attached to it.
+func NestInvoiceItems(items []InvoiceItem) []InvoiceItem {
+	sorted := make([]InvoiceItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].SortOrder != sorted[j].SortOrder {
+			return sorted[i].SortOrder < sorted[j].SortOrder
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	children := make(map[uint64][]InvoiceItem)
+	for _, it := range sorted {
+		if it.ParentID != nil {
+			children[*it.ParentID] = append(children[*it.ParentID], it)
+		}
+	}
+	roots := []InvoiceItem{}
+	for _, it := range sorted {
+		if it.ParentID != nil {
+			continue
+		}
+		if it.IsLabel {
+			it.Children = children[it.ID]
+		}
+		roots = append(roots, it)
+	}
+	return roots
 }
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
 
 type PaymentMethod string
 
@@ -9,6 +13,20 @@ const (
 	PaymentMethodCash     PaymentMethod = "CASH"
 	PaymentMethodGiro     PaymentMethod = "GIRO"
 	PaymentMethodOther    PaymentMethod = "OTHER"
+
+	// Set on payments received through the payment gateway
+	PaymentMethodVirtualAccount PaymentMethod = "VIRTUAL_ACCOUNT"
+	PaymentMethodQRIS           PaymentMethod = "QRIS"
+)
+
+// PaymentEntryType distinguishes money received from its corrections. Amounts
+// are always positive; reversals and refunds reduce the paid amount.
+type PaymentEntryType string
+
+const (
+	PaymentEntryPayment  PaymentEntryType = "PAYMENT"
+	PaymentEntryReversal PaymentEntryType = "REVERSAL"
+	PaymentEntryRefund   PaymentEntryType = "REFUND"
 )
 
 type PaymentStatus string
@@ -20,14 +38,19 @@ const (
 )
 
 type InvoicePayment struct {
-	ID            uint64        `json:"id"`
-	InvoiceID     uint64        `json:"invoice_id"`
-	Amount        float64       `json:"amount"`
-	PaymentDate   time.Time     `json:"payment_date"`
-	PaymentMethod PaymentMethod `json:"payment_method"`
-	ProofURL      string        `json:"proof_url,omitempty"`
-	Notes         string        `json:"notes,omitempty"`
-	CreatedBy     uint64        `json:"created_by"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	ID                uint64           `json:"id"`
+	InvoiceID         uint64           `json:"invoice_id"`
+	EntryType         PaymentEntryType `json:"entry_type"`
+	ReceiptNumber     string           `json:"receipt_number,omitempty"` // assigned when the first receipt is issued
+	ReversedPaymentID *uint64          `json:"reversed_payment_id,omitempty"`
+	ReversedByID      *uint64          `json:"reversed_by_id,omitempty"` // set on payments that have been reversed
+	Amount            money.Money      `json:"amount"`
+	PaymentDate       time.Time        `json:"payment_date"`
+	PaymentMethod     PaymentMethod    `json:"payment_method"`
+	ProofURL          string           `json:"proof_url,omitempty"`
+	Reason            string           `json:"reason,omitempty"`
+	Notes             string           `json:"notes,omitempty"`
+	CreatedBy         uint64           `json:"created_by"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
 }
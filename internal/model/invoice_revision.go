@@ -0,0 +1,145 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type InvoiceRevisionAction string
+
+const (
+	InvoiceRevisionCreate InvoiceRevisionAction = "CREATE"
+	InvoiceRevisionUpdate InvoiceRevisionAction = "UPDATE"
+	// Taken before the first tracked save of an invoice created before
+	// revisions were recorded
+	InvoiceRevisionBaseline InvoiceRevisionAction = "BASELINE"
+)
+
+// InvoiceRevision is an immutable snapshot of an invoice taken when it is saved.
+type InvoiceRevision struct {
+	ID             uint64                `json:"id"`
+	InvoiceID      uint64                `json:"invoice_id"`
+	RevisionNumber int                   `json:"revision_number"`
+	Action         InvoiceRevisionAction `json:"action"`
+	Snapshot       InvoiceSnapshot       `json:"snapshot"`
+	CreatedBy      uint64                `json:"created_by"`
+	CreatorName    string                `json:"creator_name,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+}
+
+// InvoiceSnapshot is the stored form of an invoice header and its items.
+// Item IDs are left out because items are replaced on every save.
+type InvoiceSnapshot struct {
+	InvoiceNumber    string                `json:"invoice_number"`
+	InvoiceType      InvoiceType           `json:"invoice_type"`
+	Status           InvoiceStatus         `json:"status"`
+	ProjectID        uint64                `json:"project_id"`
+	ClientID         *uint64               `json:"client_id"`
+	RecipientName    string                `json:"recipient_name"`
+	RecipientAddress string                `json:"recipient_address"`
+	RecipientNPWP    string                `json:"recipient_npwp"`
+	Attention        string                `json:"attention"`
+	PONumber         string                `json:"po_number"`
+	InvoiceDate      string                `json:"invoice_date"`
+	DueDate          string                `json:"due_date"`
+	DPPercentage     *float64              `json:"dp_percentage"`
+	Subtotal         money.Money           `json:"subtotal"`
+	DiscountPercent  float64               `json:"discount_percent"`
+	DiscountAmount   money.Money           `json:"discount_amount"`
+	DPPAmount        money.Money           `json:"dpp_amount"`
+	PPNMode          PPNMode               `json:"ppn_mode,omitempty"`
+	PPNBase          money.Money           `json:"ppn_base"`
+	PPNPercentage    float64               `json:"ppn_percentage"`
+	PPNAmount        money.Money           `json:"ppn_amount"`
+	PPHPercentage    float64               `json:"pph_percentage"`
+	PPHAmount        money.Money           `json:"pph_amount"`
+	PPH21Percentage  float64               `json:"pph21_percentage"`
+	PPH21Amount      money.Money           `json:"pph21_amount"`
+	Amount           money.Money           `json:"amount"`
+	Notes            string                `json:"notes"`
+	Language         string                `json:"language"`
+	FileURL          string                `json:"file_url"`
+	Items            []InvoiceSnapshotItem `json:"items"`
+}
+
+// InvoiceSnapshotItem is a standalone item or a label with its children.
+type InvoiceSnapshotItem struct {
+	IsLabel         bool                  `json:"is_label"`
+	Description     string                `json:"description"`
+	Quantity        float64               `json:"quantity"`
+	Unit            string                `json:"unit"`
+	UnitPrice       money.Money           `json:"unit_price"`
+	DiscountPercent float64               `json:"discount_percent,omitempty"`
+	DiscountAmount  money.Money           `json:"discount_amount,omitempty"`
+	Subtotal        money.Money           `json:"subtotal"`
+	PPNCode         PPNCode               `json:"ppn_code,omitempty"`
+	PPHCode         PPHCode               `json:"pph_code,omitempty"`
+	Children        []InvoiceSnapshotItem `json:"children,omitempty"`
+}
+
+// NewInvoiceSnapshot builds a snapshot from an invoice and its stored items,
+// nesting children under their label in sort order.
+func NewInvoiceSnapshot(inv *Invoice, items []InvoiceItem) InvoiceSnapshot {
+	s := InvoiceSnapshot{
+		InvoiceNumber:    inv.InvoiceNumber,
+		InvoiceType:      inv.InvoiceType,
+		Status:           inv.Status,
+		ProjectID:        inv.ProjectID,
+		ClientID:         inv.ClientID,
+		RecipientName:    inv.RecipientName,
+		RecipientAddress: inv.RecipientAddress,
+		RecipientNPWP:    inv.RecipientNPWP,
+		Attention:        inv.Attention,
+		PONumber:         inv.PONumber,
+		InvoiceDate:      inv.InvoiceDate.Format("2006-01-02"),
+		DPPercentage:     inv.DPPercentage,
+		Subtotal:         inv.Subtotal,
+		DiscountPercent:  inv.DiscountPercent,
+		DiscountAmount:   inv.DiscountAmount,
+		DPPAmount:        inv.DPPAmount,
+		PPNMode:          inv.PPNMode,
+		PPNBase:          inv.PPNBase,
+		PPNPercentage:    inv.PPNPercentage,
+		PPNAmount:        inv.PPNAmount,
+		PPHPercentage:    inv.PPHPercentage,
+		PPHAmount:        inv.PPHAmount,
+		PPH21Percentage:  inv.PPH21Percentage,
+		PPH21Amount:      inv.PPH21Amount,
+		Amount:           inv.Amount,
+		Notes:            inv.Notes,
+		Language:         inv.Language,
+		FileURL:          inv.FileURL,
+		Items:            []InvoiceSnapshotItem{},
+	}
+	if inv.DueDate != nil {
+		s.DueDate = inv.DueDate.Format("2006-01-02")
+	}
+
+	for _, it := range NestInvoiceItems(items) {
+		si := snapshotItem(it)
+		for _, child := range it.Children {
+			si.Children = append(si.Children, snapshotItem(child))
+		}
+		s.Items = append(s.Items, si)
+	}
+	return s
+}
+
+func snapshotItem(it InvoiceItem) InvoiceSnapshotItem {
+	si := InvoiceSnapshotItem{
+		IsLabel:     it.IsLabel,
+		Description: it.Description,
+		Quantity:    it.Quantity,
+		Unit:        it.Unit,
+		UnitPrice:   it.UnitPrice,
+		Subtotal:    it.Subtotal,
+	}
+	if !it.IsLabel {
+		si.DiscountPercent = it.DiscountPercent
+		si.DiscountAmount = it.DiscountAmount
+		si.PPNCode = it.PPNCode
+		si.PPHCode = it.PPHCode
+	}
+	return si
+}
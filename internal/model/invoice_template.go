@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// InvoiceTemplate holds the defaults and lines of an invoice that is billed
+// repeatedly, such as the monthly MEALS invoice of a project.
+type InvoiceTemplate struct {
+	ID               uint64                 `json:"id"`
+	Name             string                 `json:"name"`
+	InvoiceType      InvoiceType            `json:"invoice_type"`
+	ProjectID        *uint64                `json:"project_id,omitempty"`
+	ClientID         *uint64                `json:"client_id,omitempty"`
+	RecipientName    string                 `json:"recipient_name"`
+	RecipientAddress string                 `json:"recipient_address"`
+	RecipientNPWP    string                 `json:"recipient_npwp"`
+	Attention        string                 `json:"attention"`
+	DPPercentage     *float64               `json:"dp_percentage,omitempty"`
+	DiscountPercent  float64                `json:"discount_percent"`
+	DiscountAmount   money.Money            `json:"discount_amount"`
+	PPNMode          PPNMode                `json:"ppn_mode,omitempty"` // empty uses the company default
+	PPNPercentage    float64                `json:"ppn_percentage"`
+	PPHPercentage    float64                `json:"pph_percentage"`
+	PPH21Percentage  float64                `json:"pph21_percentage"`
+	Language         string                 `json:"language"`
+	Notes            string                 `json:"notes"`
+	Items            []InvoiceTemplateLine  `json:"items"`
+	Labels           []InvoiceTemplateLabel `json:"labels"`
+	CreatedBy        uint64                 `json:"created_by"`
+	CreatorName      string                 `json:"creator_name,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// InvoiceTemplateLine is a billable line of a template. Its subtotal is
+// computed when an invoice is created from the template.
+type InvoiceTemplateLine struct {
+	Description     string      `json:"description"`
+	Quantity        float64     `json:"quantity"`
+	Unit            string      `json:"unit"`
+	UnitPrice       money.Money `json:"unit_price"`
+	DiscountPercent float64     `json:"discount_percent,omitempty"`
+	DiscountAmount  money.Money `json:"discount_amount,omitempty"`
+	PPNCode         PPNCode     `json:"ppn_code,omitempty"`
+	PPHCode         PPHCode     `json:"pph_code,omitempty"`
+}
+
+// InvoiceTemplateLabel groups template lines under a heading.
+type InvoiceTemplateLabel struct {
+	Description string                `json:"description"`
+	Items       []InvoiceTemplateLine `json:"items"`
+}
@@ -14,12 +14,22 @@ const (
 	NotifInvoiceCreated  NotificationType = "INVOICE_CREATED"
 	NotifInvoiceApproved NotificationType = "INVOICE_APPROVED"
 	NotifInvoiceRejected    NotificationType = "INVOICE_REJECTED"
+	NotifInvoiceApproval    NotificationType = "INVOICE_APPROVAL_REQUIRED"
+	NotifInvoiceVoided      NotificationType = "INVOICE_VOIDED"
+	NotifInvoiceOverdue     NotificationType = "INVOICE_OVERDUE"
+	NotifCreditNoteCreated  NotificationType = "CREDIT_NOTE_CREATED"
+	NotifPaymentReversed    NotificationType = "PAYMENT_REVERSED"
+	NotifPaymentRefunded    NotificationType = "PAYMENT_REFUNDED"
+	NotifPaymentReceived    NotificationType = "PAYMENT_RECEIVED"
+	NotifPaymentOverpaid    NotificationType = "PAYMENT_OVERPAID"
+	NotifPaymentWebhookFailed NotificationType = "PAYMENT_WEBHOOK_FAILED"
 	NotifQCDocumentCreated  NotificationType = "QC_DOCUMENT_CREATED"
 	NotifQCReportCreated    NotificationType = "QC_REPORT_CREATED"
 	NotifQCReportUpdated    NotificationType = "QC_REPORT_UPDATED"
 	NotifQCReportSubmitted  NotificationType = "QC_REPORT_SUBMITTED"
 	NotifQCReportApproved   NotificationType = "QC_REPORT_APPROVED"
 	NotifQCReportRejected   NotificationType = "QC_REPORT_REJECTED"
+	NotifCommentMention     NotificationType = "COMMENT_MENTION"
 )
 
 type Notification struct {
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type PaymentChargeStatus string
+
+const (
+	PaymentChargePending PaymentChargeStatus = "PENDING"
+	PaymentChargePaid    PaymentChargeStatus = "PAID"
+	PaymentChargeExpired PaymentChargeStatus = "EXPIRED"
+	PaymentChargeFailed  PaymentChargeStatus = "FAILED"
+)
+
+// PaymentCharge is a virtual account number or QRIS code issued by the
+// payment gateway for an invoice.
+type PaymentCharge struct {
+	ID            uint64              `json:"id"`
+	InvoiceID     uint64              `json:"invoice_id"`
+	InvoiceNumber string              `json:"invoice_number"`
+	Provider      string              `json:"provider"`
+	Method        PaymentMethod       `json:"method"`
+	Bank          string              `json:"bank,omitempty"`
+	Reference     string              `json:"reference"`
+	VANumber      string              `json:"va_number,omitempty"`
+	QRString      string              `json:"qr_string,omitempty"`
+	Amount        money.Money         `json:"amount"`
+	Status        PaymentChargeStatus `json:"status"`
+	ExpiresAt     time.Time           `json:"expires_at"`
+	PaidAt        *time.Time          `json:"paid_at,omitempty"`
+	PaymentID     *uint64             `json:"payment_id,omitempty"`
+	CreatedBy     uint64              `json:"created_by"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+type WebhookEventResult string
+
+const (
+	WebhookEventReceived  WebhookEventResult = "RECEIVED"
+	WebhookEventProcessed WebhookEventResult = "PROCESSED"
+	WebhookEventIgnored   WebhookEventResult = "IGNORED"
+	WebhookEventFailed    WebhookEventResult = "FAILED"
+)
+
+// PaymentWebhookEvent is a verified gateway callback as received.
+type PaymentWebhookEvent struct {
+	ID        uint64              `json:"id"`
+	Provider  string              `json:"provider"`
+	EventID   string              `json:"event_id"`
+	Reference string              `json:"reference"`
+	Status    PaymentChargeStatus `json:"status"`
+	Amount    money.Money         `json:"amount"`
+	PaidAt    time.Time           `json:"paid_at"`
+	Payload   string              `json:"-"`
+	Result    WebhookEventResult  `json:"result"`
+	Error     string              `json:"error,omitempty"`
+	ChargeID  *uint64             `json:"charge_id,omitempty"`
+	InvoiceID *uint64             `json:"invoice_id,omitempty"` // of the charge, when listed
+	CreatedAt time.Time           `json:"created_at"`
+}
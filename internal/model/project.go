@@ -14,6 +14,7 @@ type Project struct {
 	ID          uint64        `json:"id"`
 	Name        string        `json:"name"`
 	Description string        `json:"description"`
+	ClientID    *uint64       `json:"client_id,omitempty"`
 	Status      ProjectStatus `json:"status"`
 	CreatedBy   uint64        `json:"created_by"`
 	CreatedAt   time.Time     `json:"created_at"`
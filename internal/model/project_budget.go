@@ -1,12 +1,26 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// OverspendPolicy decides what happens when approved expenses would push a
+// project's spending past its total budget.
+type OverspendPolicy string
+
+const (
+	OverspendWarn  OverspendPolicy = "WARN"
+	OverspendBlock OverspendPolicy = "BLOCK"
+)
 
 type ProjectBudget struct {
 	ID          uint64  `json:"id"`
 	ProjectID   uint64  `json:"project_id"`
-	TotalBudget float64 `json:"total_budget"`
-	SpentAmount float64 `json:"spent_amount"`
+	TotalBudget money.Money `json:"total_budget"`
+	SpentAmount money.Money `json:"spent_amount"`
+	OverspendPolicy OverspendPolicy `json:"overspend_policy"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
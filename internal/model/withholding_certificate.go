@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type WithholdingCertificateStatus string
+
+const (
+	// No certificate has arrived yet
+	WithholdingCertificateExpected WithholdingCertificateStatus = "EXPECTED"
+	// The certificate arrived and its amount matches the invoice
+	WithholdingCertificateReceived WithholdingCertificateStatus = "RECEIVED"
+	// The certificate arrived with a different amount than the invoice withheld
+	WithholdingCertificateMismatch WithholdingCertificateStatus = "MISMATCH"
+)
+
+// WithholdingCertificate is the bukti potong a client issues for the PPh it
+// withheld on an invoice. TaxType is PPHCode23 or PPHCode21, and an invoice
+// has at most one certificate per tax type.
+type WithholdingCertificate struct {
+	ID                uint64                       `json:"id"`
+	InvoiceID         uint64                       `json:"invoice_id"`
+	TaxType           PPHCode                      `json:"tax_type"`
+	CertificateNumber string                       `json:"certificate_number"`
+	CertificateDate   *time.Time                   `json:"certificate_date,omitempty"`
+	WithheldAmount    money.Money                  `json:"withheld_amount"`
+	ExpectedAmount    money.Money                  `json:"expected_amount"` // the invoice's PPh when last saved
+	FileURL           string                       `json:"file_url"`
+	Status            WithholdingCertificateStatus `json:"status"`
+	Notes             string                       `json:"notes"`
+	CreatedBy         uint64                       `json:"created_by"`
+	CreatedAt         time.Time                    `json:"created_at"`
+	UpdatedAt         time.Time                    `json:"updated_at"`
+}
@@ -0,0 +1,68 @@
+// Package payment integrates online payment gateways. A Provider issues
+// virtual account numbers and QRIS codes for invoices and verifies the
+// callbacks the gateway sends when a charge is paid or expires.
+package payment
+
+import (
+	"context"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type Method string
+
+const (
+	MethodVirtualAccount Method = "VIRTUAL_ACCOUNT"
+	MethodQRIS           Method = "QRIS"
+)
+
+// Status is the state of a charge reported in a callback.
+type Status string
+
+const (
+	StatusPending Status = "PENDING"
+	StatusPaid    Status = "PAID"
+	StatusExpired Status = "EXPIRED"
+	StatusFailed  Status = "FAILED"
+)
+
+// ChargeRequest asks the gateway for a payment code. OrderID must be unique
+// per charge.
+type ChargeRequest struct {
+	OrderID      string
+	Method       Method
+	Bank         string // virtual account only
+	Amount       money.Money
+	CustomerName string
+	Description  string
+	ExpiresAt    time.Time
+}
+
+// Charge is the payment code issued by the gateway. Reference identifies the
+// charge in callbacks.
+type Charge struct {
+	Reference string
+	VANumber  string
+	QRString  string
+	ExpiresAt time.Time
+}
+
+// Notification is a verified callback. EventID is unique per callback and is
+// used to ignore redeliveries.
+type Notification struct {
+	EventID   string
+	Reference string
+	Status    Status
+	Amount    money.Money
+	PaidAt    time.Time
+}
+
+// Provider is implemented by each payment gateway.
+type Provider interface {
+	Name() string
+	CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error)
+	// ParseWebhook verifies the signature of a callback and decodes it.
+	// header returns a request header by name.
+	ParseWebhook(body []byte, header func(key string) string) (*Notification, error)
+}
@@ -0,0 +1,237 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type labels struct {
+	page         string
+	billTo       string
+	attention    string
+	npwp         string
+	invoiceNo    string
+	date         string
+	dueDate      string
+	poNumber     string
+	project      string
+	no           string
+	description  string
+	qty          string
+	unit         string
+	unitPrice    string
+	amount       string
+	subtotal     string
+	discount     string
+	dpp          string
+	ppnBase      string
+	ppn          string
+	pph          string
+	pph21        string
+	exempt       string
+	total        string
+	downPayment  string
+	notes        string
+	remitTo      string
+	bank         string
+	accountNo    string
+	accountName  string
+	branch       string
+	regards      string
+	phone        string
+	invoiceTypes map[string]string
+}
+
+var labelsID = &labels{
+	page:        "Halaman",
+	billTo:      "Kepada Yth.",
+	attention:   "Up.",
+	npwp:        "NPWP",
+	invoiceNo:   "No. Invoice",
+	date:        "Tanggal",
+	dueDate:     "Jatuh Tempo",
+	poNumber:    "No. PO",
+	project:     "Proyek",
+	no:          "No",
+	description: "Uraian",
+	qty:         "Qty",
+	unit:        "Satuan",
+	unitPrice:   "Harga Satuan",
+	amount:      "Jumlah",
+	subtotal:    "Subtotal",
+	discount:    "Diskon",
+	dpp:         "DPP",
+	ppnBase:     "DPP Nilai Lain (11/12)",
+	ppn:         "PPN",
+	pph:         "PPh 23",
+	pph21:       "PPh 21",
+	exempt:      "Bebas PPN",
+	total:       "Total",
+	downPayment: "Uang Muka",
+	notes:       "Catatan",
+	remitTo:     "Pembayaran ditransfer ke:",
+	bank:        "Bank",
+	accountNo:   "No. Rekening",
+	accountName: "Atas Nama",
+	branch:      "Cabang",
+	regards:     "Hormat kami,",
+	phone:       "Telp.",
+	invoiceTypes: map[string]string{
+		"DP":            "Uang Muka (Down Payment)",
+		"FINAL_PAYMENT": "Pelunasan",
+		"TOP_1":         "Termin 1",
+		"TOP_2":         "Termin 2",
+		"TOP_3":         "Termin 3",
+		"MEALS":         "Konsumsi",
+		"ADDITIONAL":    "Tambahan",
+	},
+}
+
+var labelsEN = &labels{
+	page:        "Page",
+	billTo:      "Bill To",
+	attention:   "Attn.",
+	npwp:        "Tax ID (NPWP)",
+	invoiceNo:   "Invoice No.",
+	date:        "Date",
+	dueDate:     "Due Date",
+	poNumber:    "PO No.",
+	project:     "Project",
+	no:          "No",
+	description: "Description",
+	qty:         "Qty",
+	unit:        "Unit",
+	unitPrice:   "Unit Price",
+	amount:      "Amount",
+	subtotal:    "Subtotal",
+	discount:    "Discount",
+	dpp:         "Tax Base",
+	ppnBase:     "Other Tax Base (11/12)",
+	ppn:         "VAT",
+	pph:         "Withholding Tax (Art. 23)",
+	pph21:       "Withholding Tax (Art. 21)",
+	exempt:      "VAT exempt",
+	total:       "Total",
+	downPayment: "Down Payment",
+	notes:       "Notes",
+	remitTo:     "Please remit payment to:",
+	bank:        "Bank",
+	accountNo:   "Account No.",
+	accountName: "Account Name",
+	branch:      "Branch",
+	regards:     "Sincerely,",
+	phone:       "Phone",
+	invoiceTypes: map[string]string{
+		"DP":            "Down Payment",
+		"FINAL_PAYMENT": "Final Payment",
+		"TOP_1":         "Term of Payment 1",
+		"TOP_2":         "Term of Payment 2",
+		"TOP_3":         "Term of Payment 3",
+		"MEALS":         "Meals",
+		"ADDITIONAL":    "Additional",
+	},
+}
+
+func labelsFor(lang string) *labels {
+	if lang == "EN" {
+		return labelsEN
+	}
+	return labelsID
+}
+
+// FormatDate formats a date as "17 Oktober 2026" (ID) or "October 17, 2026" (EN).
+func FormatDate(t time.Time, lang string) string {
+	return formatDate(t, lang)
+}
+
+// FormatAmount formats an amount with currency as "Rp 1.234.567" (ID) or "IDR 1,234,567" (EN).
+func FormatAmount(v money.Money, lang string) string {
+	return formatMoney(v, lang, true)
+}
+
+// formatNPWP formats a 15-digit NPWP as 99.999.999.9-999.999. Other values
+// (such as the 16-digit NIK-based NPWP) are returned unchanged.
+func formatNPWP(npwp string) string {
+	if len(npwp) != 15 {
+		return npwp
+	}
+	return fmt.Sprintf("%s.%s.%s.%s-%s.%s", npwp[0:2], npwp[2:5], npwp[5:8], npwp[8:9], npwp[9:12], npwp[12:15])
+}
+
+var monthsID = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+func formatDate(t time.Time, lang string) string {
+	if t.IsZero() {
+		return "-"
+	}
+	if lang == "EN" {
+		return t.Format("January 2, 2006")
+	}
+	return fmt.Sprintf("%d %s %d", t.Day(), monthsID[t.Month()-1], t.Year())
+}
+
+// formatMoney formats an amount using Indonesian (1.234.567,50) or English
+// (1,234,567.50) grouping. Decimals are shown only when the amount has cents.
+func formatMoney(v money.Money, lang string, withCurrency bool) string {
+	s := groupCents(v.Cents(), lang)
+	if !withCurrency {
+		return s
+	}
+	if lang == "EN" {
+		return "IDR " + s
+	}
+	return "Rp " + s
+}
+
+func formatQuantity(v float64, lang string) string {
+	return groupNumber(v, lang)
+}
+
+func formatPercent(v float64, lang string) string {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if lang != "EN" {
+		s = strings.Replace(s, ".", ",", 1)
+	}
+	return s
+}
+
+func groupNumber(v float64, lang string) string {
+	return groupCents(int64(math.Round(v*100)), lang)
+}
+
+// groupCents formats an amount given in hundredths with thousand separators.
+func groupCents(cents int64, lang string) string {
+	thousandSep, decimalSep := ".", ","
+	if lang == "EN" {
+		thousandSep, decimalSep = ",", "."
+	}
+
+	neg := cents < 0
+	if neg {
+		cents = -cents
+	}
+	whole := cents / 100
+	frac := cents % 100
+
+	digits := strconv.FormatInt(whole, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(thousandSep)
+		}
+		b.WriteRune(d)
+	}
+	out := b.String()
+	if frac != 0 {
+		out += fmt.Sprintf("%s%02d", decimalSep, frac)
+	}
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
@@ -0,0 +1,421 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// InvoiceDocument holds everything needed to render a single invoice PDF.
+type InvoiceDocument struct {
+	Invoice     *model.Invoice
+	Items       []model.InvoiceItem
+	Company     *model.CompanySettings
+	ProjectName string
+	// LogoPath is a local file path to the company logo (JPG/PNG). Optional.
+	LogoPath string
+}
+
+const (
+	pageMargin = 15.0
+	lineHeight = 5.0
+)
+
+// column widths: No | Description | Qty | Unit | Unit Price | Amount
+var itemColumns = []float64{10, 74, 16, 18, 31, 31}
+
+// RenderInvoice renders the invoice as an A4 PDF and returns the raw bytes.
+// The layout language follows Invoice.Language (ID or EN).
+func RenderInvoice(doc *InvoiceDocument) ([]byte, error) {
+	if doc == nil || doc.Invoice == nil {
+		return nil, fmt.Errorf("invoice is required")
+	}
+	inv := doc.Invoice
+	lang := inv.Language
+	if lang != "EN" {
+		lang = "ID"
+	}
+	t := labelsFor(lang)
+
+	f := fpdf.New("P", "mm", "A4", "")
+	f.SetMargins(pageMargin, pageMargin, pageMargin)
+	f.SetAutoPageBreak(true, 20)
+	f.SetTitle(fmt.Sprintf("Invoice %s", inv.InvoiceNumber), true)
+	tr := f.UnicodeTranslatorFromDescriptor("")
+
+	f.AliasNbPages("{nb}")
+	f.SetFooterFunc(func() {
+		f.SetY(-12)
+		f.SetFont("Helvetica", "I", 8)
+		f.SetTextColor(120, 120, 120)
+		f.CellFormat(0, 4, tr(fmt.Sprintf("%s - %s %d/{nb}", inv.InvoiceNumber, t.page, f.PageNo())), "", 0, "R", false, 0, "")
+		f.SetTextColor(0, 0, 0)
+	})
+	f.AddPage()
+
+	pageW, _ := f.GetPageSize()
+	contentW := pageW - 2*pageMargin
+
+	// ---- Letterhead ----
+	writeLetterhead(f, tr, doc.Company, doc.LogoPath, t, contentW)
+
+	// ---- Title ----
+	f.Ln(4)
+	f.SetFont("Helvetica", "B", 16)
+	f.CellFormat(contentW, 8, "INVOICE", "", 1, "C", false, 0, "")
+	if inv.Status == model.InvoiceStatusVoid {
+		f.SetFont("Helvetica", "B", 12)
+		f.SetTextColor(200, 0, 0)
+		f.CellFormat(contentW, 6, "VOID", "", 1, "C", false, 0, "")
+		f.SetTextColor(0, 0, 0)
+	}
+	if name, ok := t.invoiceTypes[string(inv.InvoiceType)]; ok {
+		f.SetFont("Helvetica", "", 10)
+		f.CellFormat(contentW, 5, tr(name), "", 1, "C", false, 0, "")
+	}
+	f.Ln(4)
+
+	// ---- Recipient (left) and invoice meta (right) ----
+	top := f.GetY()
+	leftW := contentW * 0.55
+	rightW := contentW - leftW
+
+	f.SetFont("Helvetica", "B", 10)
+	f.CellFormat(leftW, lineHeight, tr(t.billTo), "", 2, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 10)
+	f.MultiCell(leftW-4, lineHeight, tr(inv.RecipientName), "", "L", false)
+	if inv.RecipientAddress != "" {
+		f.MultiCell(leftW-4, lineHeight, tr(inv.RecipientAddress), "", "L", false)
+	}
+	if inv.RecipientNPWP != "" {
+		f.MultiCell(leftW-4, lineHeight, tr(t.npwp+": "+formatNPWP(inv.RecipientNPWP)), "", "L", false)
+	}
+	if inv.Attention != "" {
+		f.MultiCell(leftW-4, lineHeight, tr(t.attention+" "+inv.Attention), "", "L", false)
+	}
+	leftBottom := f.GetY()
+
+	meta := [][2]string{
+		{t.invoiceNo, inv.InvoiceNumber},
+		{t.date, formatDate(inv.InvoiceDate, lang)},
+	}
+	if inv.DueDate != nil {
+		meta = append(meta, [2]string{t.dueDate, formatDate(*inv.DueDate, lang)})
+	}
+	if inv.PONumber != "" {
+		meta = append(meta, [2]string{t.poNumber, inv.PONumber})
+	}
+	if doc.ProjectName != "" {
+		meta = append(meta, [2]string{t.project, doc.ProjectName})
+	}
+	f.SetY(top)
+	for _, m := range meta {
+		f.SetX(pageMargin + leftW)
+		f.SetFont("Helvetica", "B", 10)
+		f.CellFormat(rightW*0.4, lineHeight, tr(m[0]), "", 0, "L", false, 0, "")
+		f.SetFont("Helvetica", "", 10)
+		f.CellFormat(rightW*0.6, lineHeight, tr(": "+m[1]), "", 1, "L", false, 0, "")
+	}
+	if f.GetY() < leftBottom {
+		f.SetY(leftBottom)
+	}
+	f.Ln(6)
+
+	// ---- Items table ----
+	writeItemsTable(f, tr, doc.Items, t, lang)
+
+	// ---- Totals ----
+	f.Ln(2)
+	writeTotals(f, tr, inv, t, lang, contentW)
+
+	// ---- Notes ----
+	if inv.Notes != "" {
+		f.Ln(4)
+		f.SetFont("Helvetica", "B", 10)
+		f.CellFormat(contentW, lineHeight, tr(t.notes), "", 1, "L", false, 0, "")
+		f.SetFont("Helvetica", "", 9)
+		f.MultiCell(contentW, lineHeight, tr(inv.Notes), "", "L", false)
+	}
+
+	// ---- Bank account + signatory ----
+	f.Ln(6)
+	writePaymentAndSignature(f, tr, doc.Company, t, lang, inv.InvoiceDate, contentW)
+
+	if err := f.Error(); err != nil {
+		return nil, fmt.Errorf("render invoice pdf: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Output(&buf); err != nil {
+		return nil, fmt.Errorf("write invoice pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeLetterhead(f *fpdf.Fpdf, tr func(string) string, cs *model.CompanySettings, logoPath string, t *labels, contentW float64) {
+	if cs == nil {
+		return
+	}
+	startY := f.GetY()
+	textX := pageMargin
+	if logoUsable(logoPath) {
+		f.ImageOptions(logoPath, pageMargin, startY, 0, 20, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+		textX = pageMargin + 35
+	}
+
+	f.SetXY(textX, startY)
+	f.SetFont("Helvetica", "B", 14)
+	f.CellFormat(contentW-(textX-pageMargin), 7, tr(cs.CompanyName), "", 2, "L", false, 0, "")
+	f.SetFont("Helvetica", "", 9)
+	if cs.Address != "" {
+		f.MultiCell(contentW-(textX-pageMargin), 4, tr(cs.Address), "", "L", false)
+		f.SetX(textX)
+	}
+	var contact []string
+	if cs.Phone != "" {
+		contact = append(contact, t.phone+" "+cs.Phone)
+	}
+	if cs.Email != "" {
+		contact = append(contact, cs.Email)
+	}
+	if len(contact) > 0 {
+		f.CellFormat(contentW-(textX-pageMargin), 4, tr(strings.Join(contact, "  |  ")), "", 2, "L", false, 0, "")
+	}
+	if cs.NPWP != "" {
+		f.CellFormat(contentW-(textX-pageMargin), 4, tr("NPWP: "+cs.NPWP), "", 2, "L", false, 0, "")
+	}
+
+	if f.GetY() < startY+22 && textX != pageMargin {
+		f.SetY(startY + 22)
+	}
+	f.Ln(2)
+	y := f.GetY()
+	f.SetLineWidth(0.6)
+	f.Line(pageMargin, y, pageMargin+contentW, y)
+	f.SetLineWidth(0.2)
+}
+
+func writeItemsTable(f *fpdf.Fpdf, tr func(string) string, items []model.InvoiceItem, t *labels, lang string) {
+	headers := []string{t.no, t.description, t.qty, t.unit, t.unitPrice, t.amount}
+	aligns := []string{"C", "L", "R", "C", "R", "R"}
+
+	writeHeader := func() {
+		f.SetFont("Helvetica", "B", 9)
+		f.SetFillColor(230, 230, 230)
+		for i, h := range headers {
+			f.CellFormat(itemColumns[i], 7, tr(h), "1", 0, "C", true, 0, "")
+		}
+		f.Ln(-1)
+	}
+	writeHeader()
+
+	// Group children under their label, keeping sort order
+	children := make(map[uint64][]model.InvoiceItem)
+	var roots []model.InvoiceItem
+	for _, it := range items {
+		if it.ParentID != nil {
+			children[*it.ParentID] = append(children[*it.ParentID], it)
+		} else {
+			roots = append(roots, it)
+		}
+	}
+
+	_, pageH := f.GetPageSize()
+	ensureSpace := func(h float64) {
+		if f.GetY()+h > pageH-25 {
+			f.AddPage()
+			writeHeader()
+		}
+	}
+
+	writeRow := func(no string, it model.InvoiceItem) {
+		desc := tr(it.Description + lineNote(it, t, lang))
+		lines := f.SplitText(desc, itemColumns[1]-2)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		h := float64(len(lines)) * lineHeight
+		ensureSpace(h)
+
+		x, y := f.GetX(), f.GetY()
+		f.SetFont("Helvetica", "", 9)
+		cells := []string{
+			no,
+			"",
+			formatQuantity(it.Quantity, lang),
+			tr(it.Unit),
+			formatMoney(it.UnitPrice, lang, false),
+			formatMoney(it.Subtotal-it.DiscountAmount, lang, false),
+		}
+		for i, c := range cells {
+			f.Rect(x, y, itemColumns[i], h, "D")
+			if i == 1 {
+				for li, line := range lines {
+					f.SetXY(x+1, y+float64(li)*lineHeight)
+					f.CellFormat(itemColumns[i]-2, lineHeight, line, "", 0, "L", false, 0, "")
+				}
+			} else {
+				f.SetXY(x, y)
+				f.CellFormat(itemColumns[i], lineHeight, c, "", 0, aligns[i], false, 0, "")
+			}
+			x += itemColumns[i]
+		}
+		f.SetXY(pageMargin, y+h)
+	}
+
+	no := 0
+	for _, root := range roots {
+		if root.IsLabel {
+			ensureSpace(7)
+			f.SetFont("Helvetica", "B", 9)
+			var total money.Money
+			for _, c := range children[root.ID] {
+				total += c.Subtotal - c.DiscountAmount
+			}
+			width := 0.0
+			for _, w := range itemColumns[:5] {
+				width += w
+			}
+			f.SetFillColor(245, 245, 245)
+			f.CellFormat(width, 6, tr(" "+root.Description), "1", 0, "L", true, 0, "")
+			f.CellFormat(itemColumns[5], 6, formatMoney(total, lang, false), "1", 1, "R", true, 0, "")
+			for ci, c := range children[root.ID] {
+				writeRow(fmt.Sprintf("%d.%d", no+1, ci+1), c)
+			}
+			no++
+			continue
+		}
+		no++
+		writeRow(fmt.Sprintf("%d", no), root)
+	}
+}
+
+// lineNote returns the discount and PPN exemption of a line as a suffix for
+// its description, such as " (Diskon 10%, Bebas PPN)".
+func lineNote(it model.InvoiceItem, t *labels, lang string) string {
+	var notes []string
+	if it.DiscountPercent > 0 {
+		notes = append(notes, fmt.Sprintf("%s %s%%", t.discount, formatPercent(it.DiscountPercent, lang)))
+	} else if it.DiscountAmount != 0 {
+		notes = append(notes, fmt.Sprintf("%s %s", t.discount, formatMoney(it.DiscountAmount, lang, true)))
+	}
+	if it.PPNCode == model.PPNCodeExempt {
+		notes = append(notes, t.exempt)
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(notes, ", ") + ")"
+}
+
+func writeTotals(f *fpdf.Fpdf, tr func(string) string, inv *model.Invoice, t *labels, lang string, contentW float64) {
+	labelW := 50.0
+	valueW := 40.0
+	x := pageMargin + contentW - labelW - valueW
+
+	row := func(label, value string, bold bool) {
+		style := ""
+		if bold {
+			style = "B"
+		}
+		f.SetX(x)
+		f.SetFont("Helvetica", style, 10)
+		f.CellFormat(labelW, 6, tr(label), "", 0, "L", false, 0, "")
+		f.CellFormat(valueW, 6, tr(value), "", 1, "R", false, 0, "")
+	}
+
+	row(t.subtotal, formatMoney(inv.Subtotal, lang, true), false)
+	if inv.DiscountAmount != 0 {
+		label := t.discount
+		if inv.DiscountPercent > 0 {
+			label = fmt.Sprintf("%s %s%%", t.discount, formatPercent(inv.DiscountPercent, lang))
+		}
+		row(label, "("+formatMoney(inv.DiscountAmount, lang, true)+")", false)
+		row(t.dpp, formatMoney(inv.DPPAmount, lang, true), false)
+	}
+	if inv.PPNPercentage > 0 || inv.PPNAmount != 0 {
+		if inv.PPNMode == model.PPNModeNilaiLain {
+			row(t.ppnBase, formatMoney(inv.PPNBase, lang, true), false)
+		}
+		row(fmt.Sprintf("%s %s%%", t.ppn, formatPercent(inv.PPNPercentage, lang)), formatMoney(inv.PPNAmount, lang, true), false)
+	}
+	if inv.PPHPercentage > 0 || inv.PPHAmount != 0 {
+		row(fmt.Sprintf("%s %s%%", t.pph, formatPercent(inv.PPHPercentage, lang)), "("+formatMoney(inv.PPHAmount, lang, true)+")", false)
+	}
+	if inv.PPH21Percentage > 0 || inv.PPH21Amount != 0 {
+		row(fmt.Sprintf("%s %s%%", t.pph21, formatPercent(inv.PPH21Percentage, lang)), "("+formatMoney(inv.PPH21Amount, lang, true)+")", false)
+	}
+	y := f.GetY()
+	f.Line(x, y, x+labelW+valueW, y)
+	row(t.total, formatMoney(inv.Amount, lang, true), true)
+	if inv.DPPercentage != nil {
+		f.SetX(x)
+		f.SetFont("Helvetica", "I", 9)
+		f.CellFormat(labelW+valueW, 5, tr(fmt.Sprintf("%s %s%%", t.downPayment, formatPercent(*inv.DPPercentage, lang))), "", 1, "R", false, 0, "")
+	}
+}
+
+func writePaymentAndSignature(f *fpdf.Fpdf, tr func(string) string, cs *model.CompanySettings, t *labels, lang string, date time.Time, contentW float64) {
+	_, pageH := f.GetPageSize()
+	if f.GetY()+45 > pageH-20 {
+		f.AddPage()
+	}
+	top := f.GetY()
+	leftW := contentW * 0.6
+	rightW := contentW - leftW
+
+	if cs != nil && cs.BankAccountNumber != "" {
+		f.SetFont("Helvetica", "B", 10)
+		f.CellFormat(leftW, lineHeight, tr(t.remitTo), "", 2, "L", false, 0, "")
+		f.SetFont("Helvetica", "", 10)
+		bank := [][2]string{
+			{t.bank, cs.BankName},
+			{t.accountNo, cs.BankAccountNumber},
+			{t.accountName, cs.BankAccountName},
+		}
+		if cs.BankBranch != "" {
+			bank = append(bank, [2]string{t.branch, cs.BankBranch})
+		}
+		for _, b := range bank {
+			f.CellFormat(30, lineHeight, tr(b[0]), "", 0, "L", false, 0, "")
+			f.CellFormat(leftW-30, lineHeight, tr(": "+b[1]), "", 1, "L", false, 0, "")
+		}
+	}
+
+	if cs == nil {
+		return
+	}
+	f.SetXY(pageMargin+leftW, top)
+	f.SetFont("Helvetica", "", 10)
+	f.CellFormat(rightW, lineHeight, tr(formatDate(date, lang)), "", 2, "C", false, 0, "")
+	f.CellFormat(rightW, lineHeight, tr(t.regards), "", 2, "C", false, 0, "")
+	f.CellFormat(rightW, lineHeight, tr(cs.CompanyName), "", 2, "C", false, 0, "")
+	f.SetY(f.GetY() + 20)
+	f.SetX(pageMargin + leftW)
+	f.SetFont("Helvetica", "BU", 10)
+	f.CellFormat(rightW, lineHeight, tr(cs.SignatoryName), "", 2, "C", false, 0, "")
+	f.SetFont("Helvetica", "", 10)
+	f.CellFormat(rightW, lineHeight, tr(cs.SignatoryTitle), "", 1, "C", false, 0, "")
+}
+
+// logoUsable reports whether the logo file exists and is a format fpdf can embed.
+func logoUsable(path string) bool {
+	if path == "" {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg", ".png":
+	default:
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
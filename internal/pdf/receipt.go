@@ -0,0 +1,301 @@
+package pdf
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/terbilang"
+)
+
+//go:embed templates/receipt.html
+var receiptFS embed.FS
+
+var receiptTemplate = template.Must(template.ParseFS(receiptFS, "templates/receipt.html"))
+
+// ReceiptDocument holds everything needed to render the receipt (kwitansi) of
+// a payment.
+type ReceiptDocument struct {
+	ReceiptNumber string
+	Payment       *model.InvoicePayment
+	Invoice       *model.Invoice
+	Company       *model.CompanySettings
+	ProjectName   string
+	// LogoPath is a local file path to the company logo (JPG/PNG). Optional.
+	LogoPath string
+	// StampDuty marks receipts above the stamp duty threshold, which need a
+	// meterai next to the signature.
+	StampDuty bool
+	// Cancelled marks receipts of payments that have been reversed.
+	Cancelled bool
+}
+
+type receiptLabels struct {
+	title        string
+	receiptNo    string
+	receivedFrom string
+	amountWords  string
+	purpose      string
+	invoicePay   string
+	method       string
+	paymentDate  string
+	amount       string
+	receivedBy   string
+	stampDuty    string
+	cancelled    string
+	methods      map[string]string
+}
+
+var receiptLabelsID = &receiptLabels{
+	title:        "KWITANSI",
+	receiptNo:    "No.",
+	receivedFrom: "Telah terima dari",
+	amountWords:  "Uang sejumlah",
+	purpose:      "Untuk pembayaran",
+	invoicePay:   "Pembayaran invoice",
+	method:       "Cara pembayaran",
+	paymentDate:  "Tanggal pembayaran",
+	amount:       "Jumlah",
+	receivedBy:   "Yang menerima,",
+	stampDuty:    "Meterai Rp10.000",
+	cancelled:    "DIBATALKAN",
+	methods: map[string]string{
+		"TRANSFER":        "Transfer bank",
+		"CASH":            "Tunai",
+		"GIRO":            "Giro",
+		"OTHER":           "Lainnya",
+		"VIRTUAL_ACCOUNT": "Virtual account",
+		"QRIS":            "QRIS",
+	},
+}
+
+var receiptLabelsEN = &receiptLabels{
+	title:        "OFFICIAL RECEIPT",
+	receiptNo:    "No.",
+	receivedFrom: "Received from",
+	amountWords:  "The sum of",
+	purpose:      "Being payment for",
+	invoicePay:   "Payment of invoice",
+	method:       "Payment method",
+	paymentDate:  "Payment date",
+	amount:       "Amount",
+	receivedBy:   "Received by,",
+	stampDuty:    "Stamp duty Rp10,000",
+	cancelled:    "CANCELLED",
+	methods: map[string]string{
+		"TRANSFER":        "Bank transfer",
+		"CASH":            "Cash",
+		"GIRO":            "Giro",
+		"OTHER":           "Other",
+		"VIRTUAL_ACCOUNT": "Virtual account",
+		"QRIS":            "QRIS",
+	},
+}
+
+// receiptView is the formatted content of a receipt, shared by the PDF and
+// HTML renderers.
+type receiptView struct {
+	Lang        string
+	Title       string
+	Number      string
+	Cancelled   string
+	Company     *model.CompanySettings
+	Rows        [][2]string
+	Words       string // in the receipt language
+	WordsAlt    string // in the other language
+	Amount      string
+	Date        string
+	ReceivedBy  string
+	StampDuty   string
+	LabelNumber string
+	LabelWords  string
+}
+
+func newReceiptView(doc *ReceiptDocument) (*receiptView, error) {
+	if doc == nil || doc.Payment == nil || doc.Invoice == nil {
+		return nil, fmt.Errorf("payment and invoice are required")
+	}
+	p, inv := doc.Payment, doc.Invoice
+	lang := inv.Language
+	if lang != "EN" {
+		lang = "ID"
+	}
+	t := receiptLabelsID
+	words, wordsAlt := terbilang.Rupiah(p.Amount), terbilang.RupiahEnglish(p.Amount)
+	if lang == "EN" {
+		t = receiptLabelsEN
+		words, wordsAlt = wordsAlt, words
+	}
+
+	purpose := t.invoicePay + " " + inv.InvoiceNumber
+	if name, ok := labelsFor(lang).invoiceTypes[string(inv.InvoiceType)]; ok {
+		purpose += ", " + name
+	}
+	if doc.ProjectName != "" {
+		purpose += " - " + doc.ProjectName
+	}
+	method := t.methods[string(p.PaymentMethod)]
+	if method == "" {
+		method = string(p.PaymentMethod)
+	}
+
+	v := &receiptView{
+		Lang:        lang,
+		Title:       t.title,
+		Number:      doc.ReceiptNumber,
+		Company:     doc.Company,
+		Words:       capitalize(words),
+		WordsAlt:    capitalize(wordsAlt),
+		Amount:      formatMoney(p.Amount, lang, true),
+		Date:        formatDate(p.PaymentDate, lang),
+		ReceivedBy:  t.receivedBy,
+		LabelNumber: t.receiptNo,
+		LabelWords:  t.amountWords,
+	}
+	v.Rows = [][2]string{
+		{t.receivedFrom, inv.RecipientName},
+		{t.amountWords, ""}, // rendered from Words and WordsAlt
+		{t.purpose, purpose},
+		{t.method, method},
+		{t.paymentDate, formatDate(p.PaymentDate, lang)},
+	}
+	if doc.StampDuty {
+		v.StampDuty = t.stampDuty
+	}
+	if doc.Cancelled {
+		v.Cancelled = t.cancelled
+	}
+	return v, nil
+}
+
+// RenderReceipt renders the receipt as an A5 landscape PDF.
+func RenderReceipt(doc *ReceiptDocument) ([]byte, error) {
+	v, err := newReceiptView(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	f := fpdf.New("L", "mm", "A5", "")
+	f.SetMargins(pageMargin, pageMargin, pageMargin)
+	f.SetAutoPageBreak(true, 10)
+	f.SetTitle(fmt.Sprintf("%s %s", v.Title, v.Number), true)
+	tr := f.UnicodeTranslatorFromDescriptor("")
+	f.AddPage()
+
+	pageW, _ := f.GetPageSize()
+	contentW := pageW - 2*pageMargin
+
+	writeLetterhead(f, tr, doc.Company, doc.LogoPath, labelsFor(v.Lang), contentW)
+
+	// ---- Title ----
+	f.Ln(1)
+	f.SetFont("Helvetica", "B", 14)
+	f.CellFormat(contentW, 7, tr(v.Title), "", 1, "C", false, 0, "")
+	f.SetFont("Helvetica", "", 10)
+	f.CellFormat(contentW, 5, tr(v.LabelNumber+" "+v.Number), "", 1, "C", false, 0, "")
+	if v.Cancelled != "" {
+		f.SetFont("Helvetica", "B", 11)
+		f.SetTextColor(200, 0, 0)
+		f.CellFormat(contentW, 6, tr(v.Cancelled), "", 1, "C", false, 0, "")
+		f.SetTextColor(0, 0, 0)
+	}
+	f.Ln(3)
+
+	// ---- Body ----
+	labelW := 42.0
+	valueW := contentW - labelW - 4
+	for _, row := range v.Rows {
+		y := f.GetY()
+		f.SetFont("Helvetica", "", 10)
+		f.CellFormat(labelW, lineHeight+1, tr(row[0]), "", 0, "L", false, 0, "")
+		f.CellFormat(4, lineHeight+1, ":", "", 0, "L", false, 0, "")
+		if row[0] == v.LabelWords {
+			f.SetFillColor(240, 240, 240)
+			f.SetFont("Helvetica", "BI", 10)
+			f.MultiCell(valueW, lineHeight+1, tr("# "+v.Words+" #"), "", "L", true)
+			f.SetX(pageMargin + labelW + 4)
+			f.SetFont("Helvetica", "I", 8)
+			f.MultiCell(valueW, lineHeight-1, tr("("+v.WordsAlt+")"), "", "L", false)
+		} else {
+			f.MultiCell(valueW, lineHeight+1, tr(row[1]), "", "L", false)
+		}
+		if f.GetY() < y+lineHeight+1 {
+			f.SetY(y + lineHeight + 1)
+		}
+		f.Ln(0.5)
+	}
+
+	// ---- Amount (left) and signature (right) ----
+	f.Ln(2)
+	top := f.GetY()
+	signW := 60.0
+	signX := pageMargin + contentW - signW
+
+	f.SetFont("Helvetica", "B", 13)
+	f.SetLineWidth(0.5)
+	f.CellFormat(70, 10, tr(v.Amount), "1", 0, "C", false, 0, "")
+	f.SetLineWidth(0.2)
+
+	f.SetXY(signX, top)
+	f.SetFont("Helvetica", "", 10)
+	f.CellFormat(signW, lineHeight, tr(v.Date), "", 2, "C", false, 0, "")
+	f.CellFormat(signW, lineHeight, tr(v.ReceivedBy), "", 2, "C", false, 0, "")
+	if doc.Company != nil {
+		f.CellFormat(signW, lineHeight, tr(doc.Company.CompanyName), "", 2, "C", false, 0, "")
+	}
+	signTop := f.GetY()
+	if v.StampDuty != "" {
+		// Place for the meterai, left of the signature
+		boxW, boxH := 24.0, 13.0
+		f.SetDashPattern([]float64{1, 1}, 0)
+		f.Rect(signX-boxW+6, signTop+1, boxW, boxH, "D")
+		f.SetDashPattern([]float64{}, 0)
+		f.SetXY(signX-boxW+6, signTop+3.5)
+		f.SetFont("Helvetica", "", 7)
+		f.MultiCell(boxW, 3.5, tr(v.StampDuty), "", "C", false)
+	}
+	f.SetXY(signX, signTop+15)
+	if doc.Company != nil {
+		f.SetFont("Helvetica", "BU", 10)
+		f.CellFormat(signW, lineHeight, tr(doc.Company.SignatoryName), "", 2, "C", false, 0, "")
+		f.SetFont("Helvetica", "", 10)
+		f.CellFormat(signW, lineHeight, tr(doc.Company.SignatoryTitle), "", 1, "C", false, 0, "")
+	}
+
+	if err := f.Error(); err != nil {
+		return nil, fmt.Errorf("render receipt pdf: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := f.Output(&buf); err != nil {
+		return nil, fmt.Errorf("write receipt pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderReceiptHTML renders the receipt as a standalone HTML page, for
+// previewing and printing from the browser.
+func RenderReceiptHTML(doc *ReceiptDocument) ([]byte, error) {
+	v, err := newReceiptView(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := receiptTemplate.Execute(&buf, v); err != nil {
+		return nil, fmt.Errorf("render receipt html: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type ApprovalPolicyRepository struct {
+	db *sql.DB
+}
+
+func NewApprovalPolicyRepository(db *sql.DB) *ApprovalPolicyRepository {
+	return &ApprovalPolicyRepository{db: db}
+}
+
+func (r *ApprovalPolicyRepository) Create(ctx context.Context, p *model.ApprovalPolicy) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO approval_policies (name, invoice_type, min_amount, is_active, created_by) VALUES (?, ?, ?, ?, ?)`,
+		p.Name, p.InvoiceType, p.MinAmount, p.IsActive, p.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert approval policy: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertApprovalPolicySteps(ctx, tx, uint64(id), p.Steps); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return uint64(id), nil
+}
+
+// Update saves the policy fields. Steps are replaced when steps is non-nil.
+func (r *ApprovalPolicyRepository) Update(ctx context.Context, p *model.ApprovalPolicy, steps []model.ApprovalPolicyStep) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE approval_policies SET name = ?, invoice_type = ?, min_amount = ?, is_active = ? WHERE id = ?`,
+		p.Name, p.InvoiceType, p.MinAmount, p.IsActive, p.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update approval policy: %w", err)
+	}
+
+	if steps != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM approval_policy_steps WHERE policy_id = ?`, p.ID); err != nil {
+			return fmt.Errorf("delete old approval steps: %w", err)
+		}
+		if err := insertApprovalPolicySteps(ctx, tx, p.ID, steps); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func insertApprovalPolicySteps(ctx context.Context, tx *sql.Tx, policyID uint64, steps []model.ApprovalPolicyStep) error {
+	for i, st := range steps {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO approval_policy_steps (policy_id, step_order, approver_roles) VALUES (?, ?, ?)`,
+			policyID, i+1, strings.Join(st.ApproverRoles, ","),
+		)
+		if err != nil {
+			return fmt.Errorf("insert approval step: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *ApprovalPolicyRepository) Delete(ctx context.Context, id uint64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM approval_policies WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const approvalPolicyColumns = `id, name, invoice_type, min_amount, is_active, created_by, created_at, updated_at`
+
+func scanApprovalPolicy(row rowScanner) (*model.ApprovalPolicy, error) {
+	var p model.ApprovalPolicy
+	var invoiceType sql.NullString
+	err := row.Scan(&p.ID, &p.Name, &invoiceType, &p.MinAmount, &p.IsActive, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if invoiceType.Valid {
+		t := model.InvoiceType(invoiceType.String)
+		p.InvoiceType = &t
+	}
+	return &p, nil
+}
+
+func (r *ApprovalPolicyRepository) FindByID(ctx context.Context, id uint64) (*model.ApprovalPolicy, error) {
+	p, err := scanApprovalPolicy(r.db.QueryRowContext(ctx,
+		`SELECT `+approvalPolicyColumns+` FROM approval_policies WHERE id = ?`, id,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if p.Steps, err = findApprovalPolicySteps(ctx, r.db, p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FindAll returns all policies ordered by invoice type and threshold.
+func (r *ApprovalPolicyRepository) FindAll(ctx context.Context) ([]model.ApprovalPolicy, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+approvalPolicyColumns+` FROM approval_policies ORDER BY invoice_type ASC, min_amount ASC, id ASC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []model.ApprovalPolicy
+	for rows.Next() {
+		p, err := scanApprovalPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range policies {
+		if policies[i].Steps, err = findApprovalPolicySteps(ctx, r.db, policies[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return policies, nil
+}
+
+// ExistsActive reports whether another active policy has the same invoice
+// type and threshold, which would make the match ambiguous.
+func (r *ApprovalPolicyRepository) ExistsActive(ctx context.Context, invoiceType *model.InvoiceType, minAmount money.Money, excludeID uint64) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM approval_policies
+		WHERE is_active = TRUE AND invoice_type <=> ? AND min_amount = ? AND id <> ?`,
+		invoiceType, minAmount, excludeID,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func findApprovalPolicySteps(ctx context.Context, q queryer, policyID uint64) ([]model.ApprovalPolicyStep, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, policy_id, step_order, approver_roles FROM approval_policy_steps WHERE policy_id = ? ORDER BY step_order ASC`,
+		policyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []model.ApprovalPolicyStep
+	for rows.Next() {
+		var st model.ApprovalPolicyStep
+		var roles string
+		if err := rows.Scan(&st.ID, &st.PolicyID, &st.StepOrder, &roles); err != nil {
+			return nil, err
+		}
+		st.ApproverRoles = splitList(roles)
+		steps = append(steps, st)
+	}
+	return steps, rows.Err()
+}
+
+// matchApprovalPolicy returns the active policy for an invoice: the one with
+// the highest threshold the amount reaches, preferring a policy for the
+// invoice's type over one for all types. It returns nil when none applies.
+func matchApprovalPolicy(ctx context.Context, q queryer, invoiceType model.InvoiceType, amount money.Money) (*model.ApprovalPolicy, error) {
+	p, err := scanApprovalPolicy(q.QueryRowContext(ctx,
+		`SELECT `+approvalPolicyColumns+` FROM approval_policies
+		WHERE is_active = TRUE AND (invoice_type IS NULL OR invoice_type = ?) AND min_amount <= ?
+		ORDER BY min_amount DESC, invoice_type IS NULL ASC, id DESC
+		LIMIT 1`,
+		invoiceType, amount,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.Steps, err = findApprovalPolicySteps(ctx, q, p.ID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type BankStatementRepository struct {
+	db *sql.DB
+}
+
+func NewBankStatementRepository(db *sql.DB) *BankStatementRepository {
+	return &BankStatementRepository{db: db}
+}
+
+// Create stores a statement and its transactions. Transactions whose
+// fingerprint was already imported from an earlier statement are skipped and
+// counted in DuplicateCount.
+func (r *BankStatementRepository) Create(ctx context.Context, st *model.BankStatement, txns []model.BankTransaction) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO bank_statements (format, account_number, file_name, period_start, period_end, uploaded_by)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		st.Format, st.AccountNumber, st.FileName, st.PeriodStart, st.PeriodEnd, st.UploadedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert bank statement: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	st.ID = uint64(id)
+
+	st.TransactionCount, st.DuplicateCount = 0, 0
+	for _, t := range txns {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO bank_transactions (statement_id, transaction_date, description, reference, amount, direction, fingerprint)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE id = id`,
+			st.ID, t.TransactionDate, t.Description, t.Reference, t.Amount, t.Direction, t.Fingerprint,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert bank transaction: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			st.DuplicateCount++
+			continue
+		}
+		st.TransactionCount++
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE bank_statements SET transaction_count = ?, duplicate_count = ? WHERE id = ?`,
+		st.TransactionCount, st.DuplicateCount, st.ID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update bank statement counts: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return st.ID, nil
+}
+
+const bankStatementColumns = `s.id, s.format, s.account_number, s.file_name, s.period_start, s.period_end,
+	s.transaction_count, s.duplicate_count,
+	(SELECT COUNT(1) FROM bank_transactions t WHERE t.statement_id = s.id AND t.status = 'MATCHED'),
+	(SELECT COUNT(1) FROM bank_transactions t WHERE t.statement_id = s.id AND t.status = 'UNMATCHED' AND t.direction = 'CREDIT'),
+	s.uploaded_by, s.created_at`
+
+func scanBankStatement(row rowScanner) (*model.BankStatement, error) {
+	var st model.BankStatement
+	var periodStart, periodEnd sql.NullTime
+	err := row.Scan(&st.ID, &st.Format, &st.AccountNumber, &st.FileName, &periodStart, &periodEnd,
+		&st.TransactionCount, &st.DuplicateCount, &st.MatchedCount, &st.UnmatchedCount,
+		&st.UploadedBy, &st.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if periodStart.Valid {
+		st.PeriodStart = &periodStart.Time
+	}
+	if periodEnd.Valid {
+		st.PeriodEnd = &periodEnd.Time
+	}
+	return &st, nil
+}
+
+func (r *BankStatementRepository) FindByID(ctx context.Context, id uint64) (*model.BankStatement, error) {
+	return scanBankStatement(r.db.QueryRowContext(ctx,
+		`SELECT `+bankStatementColumns+` FROM bank_statements s WHERE s.id = ?`, id,
+	))
+}
+
+func (r *BankStatementRepository) FindAll(ctx context.Context) ([]model.BankStatement, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+bankStatementColumns+` FROM bank_statements s ORDER BY s.created_at DESC, s.id DESC`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var statements []model.BankStatement
+	for rows.Next() {
+		st, err := scanBankStatement(rows)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, *st)
+	}
+	return statements, rows.Err()
+}
+
+const bankTransactionColumns = `t.id, t.statement_id, t.transaction_date, t.description, t.reference, t.amount,
+	t.direction, t.status, t.invoice_id, COALESCE(i.invoice_number, ''), t.payment_id, t.matched_by, t.matched_at,
+	t.fingerprint, t.created_at`
+
+func scanBankTransaction(row rowScanner) (*model.BankTransaction, error) {
+	var t model.BankTransaction
+	var invoiceID, paymentID, matchedBy sql.NullInt64
+	var matchedAt sql.NullTime
+	err := row.Scan(&t.ID, &t.StatementID, &t.TransactionDate, &t.Description, &t.Reference, &t.Amount,
+		&t.Direction, &t.Status, &invoiceID, &t.InvoiceNumber, &paymentID, &matchedBy, &matchedAt,
+		&t.Fingerprint, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if invoiceID.Valid {
+		v := uint64(invoiceID.Int64)
+		t.InvoiceID = &v
+	}
+	if paymentID.Valid {
+		v := uint64(paymentID.Int64)
+		t.PaymentID = &v
+	}
+	if matchedBy.Valid {
+		v := uint64(matchedBy.Int64)
+		t.MatchedBy = &v
+	}
+	if matchedAt.Valid {
+		t.MatchedAt = &matchedAt.Time
+	}
+	return &t, nil
+}
+
+// FindTransactions returns the transactions of a statement, optionally limited
+// to one status.
+func (r *BankStatementRepository) FindTransactions(ctx context.Context, statementID uint64, status string) ([]model.BankTransaction, error) {
+	query := `SELECT ` + bankTransactionColumns + `
+	FROM bank_transactions t
+	LEFT JOIN invoices i ON i.id = t.invoice_id
+	WHERE t.statement_id = ?`
+	args := []interface{}{statementID}
+	if status != "" {
+		query += ` AND t.status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY t.transaction_date ASC, t.id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []model.BankTransaction
+	for rows.Next() {
+		t, err := scanBankTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		txns = append(txns, *t)
+	}
+	return txns, rows.Err()
+}
+
+func (r *BankStatementRepository) FindTransactionByID(ctx context.Context, id uint64) (*model.BankTransaction, error) {
+	return scanBankTransaction(r.db.QueryRowContext(ctx,
+		`SELECT `+bankTransactionColumns+` FROM bank_transactions t LEFT JOIN invoices i ON i.id = t.invoice_id WHERE t.id = ?`, id,
+	))
+}
+
+// MatchTransaction records payment p for an incoming bank transaction and links
+// the two, in one transaction. The payment amount is the transaction amount.
+func (r *BankStatementRepository) MatchTransaction(ctx context.Context, transactionID uint64, p *model.InvoicePayment, matchedBy uint64) (uint64, *BalanceChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status model.BankTransactionStatus
+	var direction model.BankTransactionDirection
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, direction, amount, transaction_date FROM bank_transactions WHERE id = ? FOR UPDATE`, transactionID,
+	).Scan(&status, &direction, &p.Amount, &p.PaymentDate)
+	if err != nil {
+		return 0, nil, err
+	}
+	if direction != model.BankTransactionCredit {
+		return 0, nil, fmt.Errorf("only incoming transactions can be matched")
+	}
+	if status != model.BankTransactionUnmatched {
+		return 0, nil, fmt.Errorf("bank transaction is already reconciled")
+	}
+
+	paymentID, change, err := recordPayment(ctx, tx, p)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE bank_transactions SET status = 'MATCHED', invoice_id = ?, payment_id = ?, matched_by = ?, matched_at = NOW()
+		WHERE id = ?`,
+		p.InvoiceID, paymentID, matchedBy, transactionID,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("update bank transaction: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return paymentID, change, nil
+}
+
+// SetTransactionIgnored marks an unmatched transaction as ignored (e.g. an
+// internal transfer), or puts an ignored one back to unmatched.
+func (r *BankStatementRepository) SetTransactionIgnored(ctx context.Context, transactionID uint64, ignored bool) error {
+	from, to := model.BankTransactionUnmatched, model.BankTransactionIgnored
+	if !ignored {
+		from, to = to, from
+	}
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE bank_transactions SET status = ? WHERE id = ? AND status = ?`, to, transactionID, from,
+	)
+	if err != nil {
+		return fmt.Errorf("update bank transaction: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("bank transaction status cannot be changed")
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// activeInvoiceCondition matches invoices that count as billed.
+const activeInvoiceCondition = `status NOT IN ('REJECTED', 'VOID')`
+
+type BillingScheduleRepository struct {
+	db *sql.DB
+}
+
+func NewBillingScheduleRepository(db *sql.DB) *BillingScheduleRepository {
+	return &BillingScheduleRepository{db: db}
+}
+
+func (r *BillingScheduleRepository) FindByProjectID(ctx context.Context, projectID uint64) (*model.BillingSchedule, error) {
+	s := &model.BillingSchedule{}
+	var notes sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT project_id, contract_value, ppn_percentage, pph_percentage, notes, updated_by, created_at, updated_at
+		FROM project_billing_schedules WHERE project_id = ?`, projectID,
+	).Scan(&s.ProjectID, &s.ContractValue, &s.PPNPercentage, &s.PPHPercentage, &notes, &s.UpdatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	s.Notes = notes.String
+
+	s.Terms, err = queryBillingTerms(ctx, r.db, `t.project_id = ?`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *BillingScheduleRepository) FindTermByID(ctx context.Context, id uint64) (*model.BillingTerm, error) {
+	terms, err := queryBillingTerms(ctx, r.db, `t.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(terms) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &terms[0], nil
+}
+
+// InvoicedTotal returns the contract amount (before tax, net of credit notes)
+// already billed on a project.
+func (r *BillingScheduleRepository) InvoicedTotal(ctx context.Context, projectID uint64) (money.Money, error) {
+	return contractInvoicedTotal(ctx, r.db, projectID, 0)
+}
+
+// Save creates or replaces the schedule of a project. Terms with an ID update
+// the existing term, terms without one are added and existing terms missing
+// from the list are removed. Terms that already have an invoice keep their
+// type, percentage and amount and cannot be removed. When the terms add up to
+// 100% the last uninvoiced term takes the remainder so the schedule matches
+// the contract, and a schedule whose amounts exceed the contract is rejected.
+func (r *BillingScheduleRepository) Save(ctx context.Context, s *model.BillingSchedule) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Serialize with invoice creation on this project
+	var locked uint64
+	err = tx.QueryRowContext(ctx,
+		`SELECT project_id FROM project_billing_schedules WHERE project_id = ? FOR UPDATE`, s.ProjectID,
+	).Scan(&locked)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("lock billing schedule: %w", err)
+	}
+
+	invoiced, err := contractInvoicedTotal(ctx, tx, s.ProjectID, 0)
+	if err != nil {
+		return err
+	}
+	if s.ContractValue < invoiced {
+		return fmt.Errorf("contract value is below the amount already invoiced")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO project_billing_schedules (project_id, contract_value, ppn_percentage, pph_percentage, notes, updated_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE contract_value = VALUES(contract_value), ppn_percentage = VALUES(ppn_percentage),
+			pph_percentage = VALUES(pph_percentage), notes = VALUES(notes), updated_by = VALUES(updated_by)`,
+		s.ProjectID, s.ContractValue, s.PPNPercentage, s.PPHPercentage, s.Notes, s.UpdatedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert billing schedule: %w", err)
+	}
+
+	existing, err := queryBillingTerms(ctx, tx, `t.project_id = ?`, s.ProjectID)
+	if err != nil {
+		return err
+	}
+	existingByID := make(map[uint64]model.BillingTerm, len(existing))
+	for _, t := range existing {
+		existingByID[t.ID] = t
+	}
+
+	kept := make(map[uint64]bool)
+	for i, t := range s.Terms {
+		if t.ID == 0 {
+			continue
+		}
+		old, ok := existingByID[t.ID]
+		if !ok {
+			return fmt.Errorf("billing term not found")
+		}
+		if old.InvoiceID != nil {
+			if old.InvoiceType != t.InvoiceType || old.Percentage != t.Percentage {
+				return fmt.Errorf("invoiced terms cannot be changed or removed")
+			}
+			// Keep the amount that was billed even if the contract value changed
+			s.Terms[i].Amount = old.Amount
+			s.Terms[i].InvoiceID = old.InvoiceID
+		}
+		kept[t.ID] = true
+	}
+
+	if err := settleBillingTerms(s); err != nil {
+		return err
+	}
+
+	for _, old := range existing {
+		if kept[old.ID] {
+			continue
+		}
+		if old.InvoiceID != nil {
+			return fmt.Errorf("invoiced terms cannot be changed or removed")
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM project_billing_terms WHERE id = ?`, old.ID); err != nil {
+			return fmt.Errorf("delete billing term: %w", err)
+		}
+	}
+
+	// Clear the types that change first so a swap between two terms does not
+	// hit the unique (project_id, invoice_type) key halfway through
+	for _, t := range s.Terms {
+		if t.ID == 0 || existingByID[t.ID].InvoiceType == t.InvoiceType {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE project_billing_terms SET invoice_type = NULL WHERE id = ?`, t.ID); err != nil {
+			return fmt.Errorf("clear billing term type: %w", err)
+		}
+	}
+
+	for i, t := range s.Terms {
+		if t.ID != 0 {
+			_, err = tx.ExecContext(ctx,
+				`UPDATE project_billing_terms SET invoice_type = ?, description = ?, percentage = ?, amount = ?,
+					expected_date = ?, sort_order = ?
+				WHERE id = ?`,
+				t.InvoiceType, t.Description, t.Percentage, t.Amount, t.ExpectedDate, i, t.ID,
+			)
+			if err != nil {
+				return fmt.Errorf("update billing term: %w", err)
+			}
+			continue
+		}
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO project_billing_terms (project_id, invoice_type, description, percentage, amount, expected_date, sort_order)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			s.ProjectID, t.InvoiceType, t.Description, t.Percentage, t.Amount, t.ExpectedDate, i,
+		)
+		if err != nil {
+			return fmt.Errorf("insert billing term: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// settleBillingTerms gives the remainder of a fully scheduled contract to the
+// last uninvoiced term and rejects terms that add up to more than the contract.
+func settleBillingTerms(s *model.BillingSchedule) error {
+	var totalPct float64
+	var total money.Money
+	last := -1
+	for i, t := range s.Terms {
+		totalPct += t.Percentage
+		total += t.Amount
+		if t.InvoiceID == nil {
+			last = i
+		}
+	}
+
+	if last >= 0 && math.Round(totalPct*100) == 10000 {
+		s.Terms[last].Amount += s.ContractValue - total
+		total = s.ContractValue
+		if s.Terms[last].Amount < 0 {
+			return fmt.Errorf("billing terms exceed the contract value")
+		}
+	}
+	if total > s.ContractValue {
+		return fmt.Errorf("billing terms exceed the contract value")
+	}
+	return nil
+}
+
+// checkContractBilling guards invoice writes against the project contract:
+// contract-type invoices may not push the billed total past the contract
+// value, and a billing term may only have one active invoice. Projects without
+// a schedule are not limited. invoiceID is the invoice being updated, or 0.
+func checkContractBilling(ctx context.Context, tx *sql.Tx, inv *model.Invoice, invoiceID uint64) error {
+	if !model.IsContractInvoiceType(inv.InvoiceType) {
+		return nil
+	}
+
+	var contract money.Money
+	err := tx.QueryRowContext(ctx,
+		`SELECT contract_value FROM project_billing_schedules WHERE project_id = ? FOR UPDATE`, inv.ProjectID,
+	).Scan(&contract)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("lock billing schedule: %w", err)
+	}
+
+	invoiced, err := contractInvoicedTotal(ctx, tx, inv.ProjectID, invoiceID)
+	if err != nil {
+		return err
+	}
+	if invoiced+inv.DPPAmount > contract {
+		return fmt.Errorf("invoice exceeds remaining contract value")
+	}
+
+	if inv.BillingTermID != nil {
+		var count int
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM invoices WHERE billing_term_id = ? AND id <> ? AND `+activeInvoiceCondition,
+			*inv.BillingTermID, invoiceID,
+		).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("count term invoices: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("billing term already invoiced")
+		}
+	}
+	return nil
+}
+
+// contractInvoicedTotal sums the DPP (subtotal less discounts) of active
+// contract-type invoices of a project, net of credit notes, excluding excludeID.
+func contractInvoicedTotal(ctx context.Context, q queryer, projectID, excludeID uint64) (money.Money, error) {
+	var total money.Money
+	err := q.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(i.dpp_amount - COALESCE((SELECT SUM(cn.dpp_amount) FROM credit_notes cn WHERE cn.invoice_id = i.id), 0)), 0)
+		FROM invoices i
+		WHERE i.project_id = ? AND i.id <> ?
+			AND i.invoice_type IN ('DP', 'TOP_1', 'TOP_2', 'TOP_3', 'FINAL_PAYMENT')
+			AND i.`+activeInvoiceCondition,
+		projectID, excludeID,
+	).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("sum invoiced contract amount: %w", err)
+	}
+	return total, nil
+}
+
+func queryBillingTerms(ctx context.Context, q queryer, where string, args ...interface{}) ([]model.BillingTerm, error) {
+	query := `SELECT t.id, t.project_id, t.invoice_type, t.description, t.percentage, t.amount, t.expected_date, t.sort_order,
+		i.id, i.invoice_number, i.status
+	FROM project_billing_terms t
+	LEFT JOIN invoices i ON i.billing_term_id = t.id AND i.` + activeInvoiceCondition + `
+	WHERE ` + where + `
+	ORDER BY t.sort_order ASC, t.id ASC`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var terms []model.BillingTerm
+	for rows.Next() {
+		var t model.BillingTerm
+		var expectedDate sql.NullTime
+		var invoiceID sql.NullInt64
+		var invoiceNumber, invoiceStatus sql.NullString
+		err := rows.Scan(&t.ID, &t.ProjectID, &t.InvoiceType, &t.Description, &t.Percentage, &t.Amount, &expectedDate, &t.SortOrder,
+			&invoiceID, &invoiceNumber, &invoiceStatus)
+		if err != nil {
+			return nil, err
+		}
+		if expectedDate.Valid {
+			t.ExpectedDate = &expectedDate.Time
+		}
+		if invoiceID.Valid {
+			v := uint64(invoiceID.Int64)
+			t.InvoiceID = &v
+			t.InvoiceNumber = invoiceNumber.String
+			t.InvoiceStatus = model.InvoiceStatus(invoiceStatus.String)
+		}
+		terms = append(terms, t)
+	}
+	return terms, rows.Err()
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type BudgetRepository struct {
@@ -16,10 +18,10 @@ func NewBudgetRepository(db *sql.DB) *BudgetRepository {
 }
 
 func (r *BudgetRepository) FindByProjectID(ctx context.Context, projectID uint64) (*model.ProjectBudget, error) {
-	query := `SELECT id, project_id, total_budget, spent_amount, created_at, updated_at FROM project_budgets WHERE project_id = ?`
+	query := `SELECT id, project_id, total_budget, spent_amount, overspend_policy, created_at, updated_at FROM project_budgets WHERE project_id = ?`
 	b := &model.ProjectBudget{}
 	err := r.db.QueryRowContext(ctx, query, projectID).Scan(
-		&b.ID, &b.ProjectID, &b.TotalBudget, &b.SpentAmount, &b.CreatedAt, &b.UpdatedAt,
+		&b.ID, &b.ProjectID, &b.TotalBudget, &b.SpentAmount, &b.OverspendPolicy, &b.CreatedAt, &b.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -27,14 +29,42 @@ func (r *BudgetRepository) FindByProjectID(ctx context.Context, projectID uint64
 	return b, nil
 }
 
-func (r *BudgetRepository) UpdateSpentAmount(ctx context.Context, projectID uint64, amount float64) error {
-	query := `UPDATE project_budgets SET spent_amount = spent_amount + ? WHERE project_id = ?`
-	_, err := r.db.ExecContext(ctx, query, amount, projectID)
+func (r *BudgetRepository) UpdateOverspendPolicy(ctx context.Context, projectID uint64, policy model.OverspendPolicy) error {
+	query := `UPDATE project_budgets SET overspend_policy = ? WHERE project_id = ?`
+	_, err := r.db.ExecContext(ctx, query, policy, projectID)
 	return err
 }
 
-func (r *BudgetRepository) IncreaseTotalBudget(ctx context.Context, projectID uint64, amount float64) error {
-	query := `UPDATE project_budgets SET total_budget = total_budget + ?, updated_at = NOW() WHERE project_id = ?`
-	_, err := r.db.ExecContext(ctx, query, amount, projectID)
-	return err
+// updateSpentAmount adds delta (negative when an expense stops counting) to
+// the spent amount of a project inside tx. With enforce set, an increase that
+// takes spending past the total budget of a project with the BLOCK policy is
+// refused. Projects without a budget row are left alone.
+func updateSpentAmount(ctx context.Context, tx *sql.Tx, projectID uint64, delta money.Money, enforce bool) error {
+	if delta == 0 {
+		return nil
+	}
+
+	var total, spent money.Money
+	var policy model.OverspendPolicy
+	err := tx.QueryRowContext(ctx,
+		`SELECT total_budget, spent_amount, overspend_policy FROM project_budgets WHERE project_id = ? FOR UPDATE`, projectID,
+	).Scan(&total, &spent, &policy)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get budget: %w", err)
+	}
+	if enforce && delta > 0 && policy == model.OverspendBlock && spent+delta > total {
+		return fmt.Errorf("expense exceeds project budget")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE project_budgets SET spent_amount = GREATEST(spent_amount + ?, 0) WHERE project_id = ?`,
+		delta, projectID,
+	)
+	if err != nil {
+		return fmt.Errorf("update budget: %w", err)
+	}
+	return nil
 }
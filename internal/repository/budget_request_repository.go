@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type BudgetRequestRepository struct {
@@ -85,25 +86,30 @@ func (r *BudgetRequestRepository) FindByProjectIDs(ctx context.Context, projectI
 	return requests, rows.Err()
 }
 
-func (r *BudgetRequestRepository) ApproveBudgetRequest(ctx context.Context, id, approvedBy uint64, notes, proofURL string) error {
+// ApproveBudgetRequest approves a pending budget request, adds its amount to
+// the project budget and records it as an approved expense of the project,
+// all in one transaction. It returns the ID of that expense.
+func (r *BudgetRequestRepository) ApproveBudgetRequest(ctx context.Context, id, approvedBy uint64, notes, proofURL string) (uint64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return 0, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
 	// Lock row and verify PENDING status
 	var status model.BudgetRequestStatus
-	var projectID uint64
-	var amount float64
+	var projectID, requestedBy uint64
+	var amount money.Money
+	var reason string
+	var requestProof sql.NullString
 	err = tx.QueryRowContext(ctx,
-		`SELECT status, project_id, amount FROM budget_requests WHERE id = ? FOR UPDATE`, id,
-	).Scan(&status, &projectID, &amount)
+		`SELECT status, project_id, amount, reason, proof_url, requested_by FROM budget_requests WHERE id = ? FOR UPDATE`, id,
+	).Scan(&status, &projectID, &amount, &reason, &requestProof, &requestedBy)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if status != model.BudgetRequestPending {
-		return fmt.Errorf("budget request is not pending")
+		return 0, fmt.Errorf("budget request is not pending")
 	}
 
 	// Update budget request status with approval details
@@ -112,7 +118,7 @@ func (r *BudgetRequestRepository) ApproveBudgetRequest(ctx context.Context, id,
 		approvedBy, notes, proofURL, id,
 	)
 	if err != nil {
-		return fmt.Errorf("update budget request: %w", err)
+		return 0, fmt.Errorf("update budget request: %w", err)
 	}
 
 	// Update project budget total
@@ -121,13 +127,28 @@ func (r *BudgetRequestRepository) ApproveBudgetRequest(ctx context.Context, id,
 		amount, projectID,
 	)
 	if err != nil {
-		return fmt.Errorf("update budget: %w", err)
+		return 0, fmt.Errorf("update budget: %w", err)
+	}
+
+	// The approved amount is spent right away. It is funded by the request, so
+	// the overspend policy does not apply.
+	expenseID, err := insertExpense(ctx, tx, &model.Expense{
+		ProjectID:   projectID,
+		Description: fmt.Sprintf("Budget Request: %s", reason),
+		Amount:      amount,
+		Category:    model.ExpenseCategoryBudgetRequest,
+		ReceiptURL:  requestProof.String,
+		Status:      model.ExpenseStatusApproved,
+		CreatedBy:   requestedBy,
+	}, false)
+	if err != nil {
+		return 0, err
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit tx: %w", err)
+		return 0, fmt.Errorf("commit tx: %w", err)
 	}
-	return nil
+	return expenseID, nil
 }
 
 func (r *BudgetRequestRepository) RejectBudgetRequest(ctx context.Context, id, approvedBy uint64, notes, proofURL string) error {
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type ClientRepository struct {
+	db *sql.DB
+}
+
+func NewClientRepository(db *sql.DB) *ClientRepository {
+	return &ClientRepository{db: db}
+}
+
+func (r *ClientRepository) Create(ctx context.Context, c *model.Client) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO clients (name, npwp, billing_address, email, phone, payment_terms_days, notes, is_active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.Name, c.NPWP, c.BillingAddress, c.Email, c.Phone, c.PaymentTermsDays, c.Notes, c.IsActive, c.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert client: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insertClientContacts(ctx, tx, uint64(id), c.Contacts); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+// Update saves the client fields. Contacts are replaced when contacts is non-nil.
+func (r *ClientRepository) Update(ctx context.Context, c *model.Client, contacts []model.ClientContact) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE clients SET name = ?, npwp = ?, billing_address = ?, email = ?, phone = ?,
+			payment_terms_days = ?, notes = ?, is_active = ?
+		WHERE id = ?`,
+		c.Name, c.NPWP, c.BillingAddress, c.Email, c.Phone, c.PaymentTermsDays, c.Notes, c.IsActive, c.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update client: %w", err)
+	}
+
+	if contacts != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM client_contacts WHERE client_id = ?`, c.ID); err != nil {
+			return fmt.Errorf("delete old contacts: %w", err)
+		}
+		if err := insertClientContacts(ctx, tx, c.ID, contacts); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *ClientRepository) FindByID(ctx context.Context, id uint64) (*model.Client, error) {
+	query := `SELECT ` + clientColumns + ` FROM clients WHERE id = ?`
+	c, err := scanClient(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, err
+	}
+
+	c.Contacts, err = r.FindContactsByClientID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FindAll lists clients ordered by name. When search is non-empty only clients
+// whose name or NPWP contains it are returned; inactive clients are skipped
+// unless includeInactive is set.
+func (r *ClientRepository) FindAll(ctx context.Context, search string, includeInactive bool) ([]model.Client, error) {
+	query := `SELECT ` + clientColumns + ` FROM clients WHERE 1 = 1`
+	var args []interface{}
+	if !includeInactive {
+		query += ` AND is_active = TRUE`
+	}
+	if search != "" {
+		query += ` AND (name LIKE ? OR npwp LIKE ?)`
+		like := "%" + escapeLike(search) + "%"
+		args = append(args, like, like)
+	}
+	query += ` ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []model.Client
+	for rows.Next() {
+		c, err := scanClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, *c)
+	}
+	return clients, rows.Err()
+}
+
+func (r *ClientRepository) FindContactsByClientID(ctx context.Context, clientID uint64) ([]model.ClientContact, error) {
+	query := `SELECT id, client_id, name, position, email, phone, is_primary, sort_order
+	FROM client_contacts WHERE client_id = ? ORDER BY sort_order ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, clientID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []model.ClientContact
+	for rows.Next() {
+		var ct model.ClientContact
+		var position, email, phone sql.NullString
+		if err := rows.Scan(&ct.ID, &ct.ClientID, &ct.Name, &position, &email, &phone, &ct.IsPrimary, &ct.SortOrder); err != nil {
+			return nil, err
+		}
+		ct.Position = position.String
+		ct.Email = email.String
+		ct.Phone = phone.String
+		contacts = append(contacts, ct)
+	}
+	return contacts, rows.Err()
+}
+
+// Delete removes a client that is not referenced by any project or invoice.
+func (r *ClientRepository) Delete(ctx context.Context, id uint64) error {
+	var refs int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM projects WHERE client_id = ?) + (SELECT COUNT(*) FROM invoices WHERE client_id = ?)`,
+		id, id,
+	).Scan(&refs)
+	if err != nil {
+		return fmt.Errorf("count client references: %w", err)
+	}
+	if refs > 0 {
+		return fmt.Errorf("client is referenced by projects or invoices")
+	}
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM clients WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func insertClientContacts(ctx context.Context, tx *sql.Tx, clientID uint64, contacts []model.ClientContact) error {
+	for i, ct := range contacts {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO client_contacts (client_id, name, position, email, phone, is_primary, sort_order)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			clientID, ct.Name, ct.Position, ct.Email, ct.Phone, ct.IsPrimary, i,
+		)
+		if err != nil {
+			return fmt.Errorf("insert client contact: %w", err)
+		}
+	}
+	return nil
+}
+
+// clientColumns is the column list scanned by scanClient.
+const clientColumns = `id, name, npwp, billing_address, email, phone, payment_terms_days, notes, is_active,
+		created_by, created_at, updated_at`
+
+func scanClient(row rowScanner) (*model.Client, error) {
+	c := &model.Client{}
+	var npwp, address, email, phone, notes sql.NullString
+
+	err := row.Scan(&c.ID, &c.Name, &npwp, &address, &email, &phone, &c.PaymentTermsDays, &notes, &c.IsActive,
+		&c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.NPWP = npwp.String
+	c.BillingAddress = address.String
+	c.Email = email.String
+	c.Phone = phone.String
+	c.Notes = notes.String
+	return c, nil
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Create stores a comment with its attachments and mentions.
+func (r *CommentRepository) Create(ctx context.Context, c *model.Comment) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO comments (entity_type, entity_id, project_id, parent_id, body, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		c.EntityType, c.EntityID, c.ProjectID, c.ParentID, c.Body, c.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert comment: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, url := range c.Attachments {
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_attachments (comment_id, file_url) VALUES (?, ?)`, id, url)
+		if err != nil {
+			return 0, fmt.Errorf("insert comment attachment: %w", err)
+		}
+	}
+	for _, userID := range c.MentionIDs {
+		_, err = tx.ExecContext(ctx, `INSERT INTO comment_mentions (comment_id, user_id) VALUES (?, ?)`, id, userID)
+		if err != nil {
+			return 0, fmt.Errorf("insert comment mention: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return uint64(id), nil
+}
+
+const commentColumns = `c.id, c.entity_type, c.entity_id, c.project_id, c.parent_id, c.body,
+	c.created_by, COALESCE(u.full_name, ''), c.created_at, c.updated_at`
+
+func scanComment(row rowScanner) (*model.Comment, error) {
+	var c model.Comment
+	var parentID sql.NullInt64
+	err := row.Scan(&c.ID, &c.EntityType, &c.EntityID, &c.ProjectID, &parentID, &c.Body,
+		&c.CreatedBy, &c.AuthorName, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		v := uint64(parentID.Int64)
+		c.ParentID = &v
+	}
+	return &c, nil
+}
+
+func (r *CommentRepository) FindByID(ctx context.Context, id uint64) (*model.Comment, error) {
+	c, err := scanComment(r.db.QueryRowContext(ctx,
+		`SELECT `+commentColumns+` FROM comments c LEFT JOIN users u ON c.created_by = u.id WHERE c.id = ?`, id,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadDetails(ctx, []*model.Comment{c}); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FindByEntity returns all comments on a record, oldest first.
+func (r *CommentRepository) FindByEntity(ctx context.Context, entityType model.CommentEntityType, entityID uint64) ([]model.Comment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+commentColumns+` FROM comments c LEFT JOIN users u ON c.created_by = u.id
+		WHERE c.entity_type = ? AND c.entity_id = ? ORDER BY c.created_at ASC, c.id ASC`,
+		entityType, entityID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []model.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, *c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*model.Comment, len(comments))
+	for i := range comments {
+		ptrs[i] = &comments[i]
+	}
+	if err := r.loadDetails(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// loadDetails fills in the attachments and mentions of the comments.
+func (r *CommentRepository) loadDetails(ctx context.Context, comments []*model.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	byID := make(map[uint64]*model.Comment, len(comments))
+	ids := make([]uint64, len(comments))
+	for i, c := range comments {
+		byID[c.ID] = c
+		ids[i] = c.ID
+	}
+	placeholders, args := buildInClause(ids)
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT comment_id, file_url FROM comment_attachments WHERE comment_id IN (%s) ORDER BY id ASC`, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var commentID uint64
+		var url string
+		if err := rows.Scan(&commentID, &url); err != nil {
+			return err
+		}
+		byID[commentID].Attachments = append(byID[commentID].Attachments, url)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	mentionRows, err := r.db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT comment_id, user_id FROM comment_mentions WHERE comment_id IN (%s) ORDER BY user_id ASC`, placeholders), args...)
+	if err != nil {
+		return err
+	}
+	defer mentionRows.Close()
+	for mentionRows.Next() {
+		var commentID, userID uint64
+		if err := mentionRows.Scan(&commentID, &userID); err != nil {
+			return err
+		}
+		byID[commentID].MentionIDs = append(byID[commentID].MentionIDs, userID)
+	}
+	return mentionRows.Err()
+}
@@ -19,7 +19,8 @@ func NewCompanySettingsRepository(db *sql.DB) *CompanySettingsRepository {
 func (r *CompanySettingsRepository) Get(ctx context.Context) (*model.CompanySettings, error) {
 	query := `SELECT id, company_name, company_code, address, phone, email, npwp,
 		bank_name, bank_account_number, bank_account_name, bank_branch,
-		logo_url, signatory_name, signatory_title, created_at, updated_at
+		logo_url, signatory_name, signatory_title, invoice_number_pattern, credit_note_number_pattern,
+		receipt_number_pattern, stamp_duty_threshold, default_ppn_mode, default_ppn_percentage, created_at, updated_at
 	FROM company_settings LIMIT 1`
 
 	cs := &model.CompanySettings{}
@@ -29,7 +30,8 @@ func (r *CompanySettingsRepository) Get(ctx context.Context) (*model.CompanySett
 	err := r.db.QueryRowContext(ctx, query).Scan(
 		&cs.ID, &cs.CompanyName, &cs.CompanyCode, &address, &phone, &email, &npwp,
 		&bankName, &bankAccNum, &bankAccName, &bankBranch,
-		&logoURL, &sigName, &sigTitle, &cs.CreatedAt, &cs.UpdatedAt,
+		&logoURL, &sigName, &sigTitle, &cs.InvoiceNumberPattern, &cs.CreditNoteNumberPattern,
+		&cs.ReceiptNumberPattern, &cs.StampDutyThreshold, &cs.DefaultPPNMode, &cs.DefaultPPNPercentage, &cs.CreatedAt, &cs.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -60,11 +62,13 @@ func (r *CompanySettingsRepository) Upsert(ctx context.Context, cs *model.Compan
 		result, err := r.db.ExecContext(ctx,
 			`INSERT INTO company_settings (company_name, company_code, address, phone, email, npwp,
 				bank_name, bank_account_number, bank_account_name, bank_branch,
-				logo_url, signatory_name, signatory_title)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				logo_url, signatory_name, signatory_title, invoice_number_pattern, credit_note_number_pattern,
+				receipt_number_pattern, stamp_duty_threshold, default_ppn_mode, default_ppn_percentage)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			cs.CompanyName, cs.CompanyCode, cs.Address, cs.Phone, cs.Email, cs.NPWP,
 			cs.BankName, cs.BankAccountNumber, cs.BankAccountName, cs.BankBranch,
-			cs.LogoURL, cs.SignatoryName, cs.SignatoryTitle,
+			cs.LogoURL, cs.SignatoryName, cs.SignatoryTitle, cs.InvoiceNumberPattern, cs.CreditNoteNumberPattern,
+			cs.ReceiptNumberPattern, cs.StampDutyThreshold, cs.DefaultPPNMode, cs.DefaultPPNPercentage,
 		)
 		if err != nil {
 			return 0, fmt.Errorf("insert company settings: %w", err)
@@ -84,11 +88,14 @@ func (r *CompanySettingsRepository) Upsert(ctx context.Context, cs *model.Compan
 		`UPDATE company_settings SET company_name = ?, company_code = ?, address = ?,
 			phone = ?, email = ?, npwp = ?, bank_name = ?, bank_account_number = ?,
 			bank_account_name = ?, bank_branch = ?, logo_url = ?,
-			signatory_name = ?, signatory_title = ?
+			signatory_name = ?, signatory_title = ?, invoice_number_pattern = ?,
+			credit_note_number_pattern = ?, receipt_number_pattern = ?, stamp_duty_threshold = ?,
+			default_ppn_mode = ?, default_ppn_percentage = ?
 		WHERE id = ?`,
 		cs.CompanyName, cs.CompanyCode, cs.Address, cs.Phone, cs.Email, cs.NPWP,
 		cs.BankName, cs.BankAccountNumber, cs.BankAccountName, cs.BankBranch,
-		cs.LogoURL, cs.SignatoryName, cs.SignatoryTitle,
+		cs.LogoURL, cs.SignatoryName, cs.SignatoryTitle, cs.InvoiceNumberPattern, cs.CreditNoteNumberPattern,
+		cs.ReceiptNumberPattern, cs.StampDutyThreshold, cs.DefaultPPNMode, cs.DefaultPPNPercentage,
 		existingID,
 	)
 	if err != nil {
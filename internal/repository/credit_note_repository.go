@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type CreditNoteRepository struct {
+	db *sql.DB
+}
+
+func NewCreditNoteRepository(db *sql.DB) *CreditNoteRepository {
+	return &CreditNoteRepository{db: db}
+}
+
+// Create issues a credit note against an approved invoice. The invoice row is
+// locked while the credit is checked against the outstanding balance, the
+// credit note number is reserved and the invoice balance is recalculated.
+func (r *CreditNoteRepository) Create(ctx context.Context, cn *model.CreditNote, items []model.CreditNoteItem) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status model.InvoiceStatus
+	var amount, paid, credited money.Money
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, amount, paid_amount, credited_amount FROM invoices WHERE id = ? FOR UPDATE`, cn.InvoiceID,
+	).Scan(&status, &amount, &paid, &credited)
+	if err != nil {
+		return 0, err
+	}
+	if status != model.InvoiceStatusApproved {
+		return 0, fmt.Errorf("credit notes can only be issued for approved invoices")
+	}
+	if outstanding := amount - paid - credited; cn.Amount > outstanding {
+		return 0, fmt.Errorf("credit note amount (%s) exceeds outstanding balance (%s)", cn.Amount, outstanding)
+	}
+
+	number, err := nextDocumentNumber(ctx, tx, DocTypeCreditNote, cn.CreditDate)
+	if err != nil {
+		return 0, err
+	}
+	cn.CreditNoteNumber = number
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO credit_notes (credit_note_number, invoice_id, credit_date, reason, subtotal, discount_amount, dpp_amount,
+			ppn_mode, ppn_base, ppn_percentage, ppn_amount, pph_percentage, pph_amount, pph21_percentage, pph21_amount, amount, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		cn.CreditNoteNumber, cn.InvoiceID, cn.CreditDate, cn.Reason, cn.Subtotal, cn.DiscountAmount, cn.DPPAmount,
+		cn.PPNMode, cn.PPNBase,
+		cn.PPNPercentage, cn.PPNAmount, cn.PPHPercentage, cn.PPHAmount, cn.PPH21Percentage, cn.PPH21Amount, cn.Amount, cn.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert credit note: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, item := range items {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO credit_note_items (credit_note_id, description, quantity, unit, unit_price,
+				discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, item.Description, item.Quantity, item.Unit, item.UnitPrice,
+			item.DiscountPercent, item.DiscountAmount, item.Subtotal, item.PPNCode, item.PPHCode, i,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("insert credit note item: %w", err)
+		}
+	}
+
+	if err := recalcInvoiceBalance(ctx, tx, cn.InvoiceID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+func (r *CreditNoteRepository) FindByID(ctx context.Context, id uint64) (*model.CreditNote, error) {
+	query := `SELECT id, credit_note_number, invoice_id, credit_date, reason, subtotal, discount_amount, dpp_amount,
+		ppn_mode, ppn_base, ppn_percentage, ppn_amount, pph_percentage, pph_amount, pph21_percentage, pph21_amount, amount, created_by, created_at, updated_at
+	FROM credit_notes WHERE id = ?`
+
+	cn := &model.CreditNote{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&cn.ID, &cn.CreditNoteNumber, &cn.InvoiceID, &cn.CreditDate, &cn.Reason, &cn.Subtotal, &cn.DiscountAmount, &cn.DPPAmount,
+		&cn.PPNMode, &cn.PPNBase, &cn.PPNPercentage, &cn.PPNAmount, &cn.PPHPercentage, &cn.PPHAmount, &cn.PPH21Percentage, &cn.PPH21Amount,
+		&cn.Amount, &cn.CreatedBy, &cn.CreatedAt, &cn.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return cn, nil
+}
+
+func (r *CreditNoteRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.CreditNote, error) {
+	query := `SELECT id, credit_note_number, invoice_id, credit_date, reason, subtotal, discount_amount, dpp_amount,
+		ppn_mode, ppn_base, ppn_percentage, ppn_amount, pph_percentage, pph_amount, pph21_percentage, pph21_amount, amount, created_by, created_at, updated_at
+	FROM credit_notes WHERE invoice_id = ? ORDER BY credit_date ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notes []model.CreditNote
+	for rows.Next() {
+		var cn model.CreditNote
+		if err := rows.Scan(
+			&cn.ID, &cn.CreditNoteNumber, &cn.InvoiceID, &cn.CreditDate, &cn.Reason, &cn.Subtotal, &cn.DiscountAmount, &cn.DPPAmount,
+			&cn.PPNMode, &cn.PPNBase, &cn.PPNPercentage, &cn.PPNAmount, &cn.PPHPercentage, &cn.PPHAmount, &cn.PPH21Percentage, &cn.PPH21Amount,
+			&cn.Amount, &cn.CreatedBy, &cn.CreatedAt, &cn.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notes = append(notes, cn)
+	}
+	return notes, rows.Err()
+}
+
+func (r *CreditNoteRepository) FindItemsByCreditNoteID(ctx context.Context, creditNoteID uint64) ([]model.CreditNoteItem, error) {
+	query := `SELECT id, credit_note_id, description, quantity, unit, unit_price, discount_percent, discount_amount,
+		subtotal, ppn_code, pph_code, sort_order, created_at
+	FROM credit_note_items WHERE credit_note_id = ? ORDER BY sort_order ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, creditNoteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.CreditNoteItem
+	for rows.Next() {
+		var item model.CreditNoteItem
+		if err := rows.Scan(&item.ID, &item.CreditNoteID, &item.Description, &item.Quantity, &item.Unit,
+			&item.UnitPrice, &item.DiscountPercent, &item.DiscountAmount,
+			&item.Subtotal, &item.PPNCode, &item.PPHCode, &item.SortOrder, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
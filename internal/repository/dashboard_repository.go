@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type ProjectSummaryRow struct {
@@ -12,15 +14,16 @@ type ProjectSummaryRow struct {
 }
 
 type BudgetSummaryRow struct {
-	TotalBudget     float64
+	TotalBudget     money.Money
 	TotalPlanBudget float64
-	TotalSpent      float64
-	Remaining       float64
+	TotalSpent      money.Money
+	Remaining       money.Money
 }
 
 type ExpenseSummaryRow struct {
-	TotalExpenses int64
-	TotalAmount   float64
+	TotalExpenses   int64
+	TotalAmount     money.Money
+	PendingExpenses int64
 }
 
 type BudgetRequestSummaryRow struct {
@@ -28,12 +31,12 @@ type BudgetRequestSummaryRow struct {
 	PendingRequests  int64
 	ApprovedRequests int64
 	RejectedRequests int64
-	TotalAmount      float64
+	TotalAmount      money.Money
 }
 
 type InvoiceSummaryRow struct {
 	TotalInvoices int64
-	TotalAmount   float64
+	TotalAmount   money.Money
 }
 
 type DashboardRepository struct {
@@ -95,23 +98,36 @@ func (r *DashboardRepository) GetBudgetSummary(ctx context.Context, projectIDs [
 	return row, nil
 }
 
+// GetExpenseSummary counts and sums approved expenses only. Expenses still
+// waiting for review are counted separately.
 func (r *DashboardRepository) GetExpenseSummary(ctx context.Context, projectIDs []uint64) (*ExpenseSummaryRow, error) {
 	var query string
 	var args []interface{}
 
 	if len(projectIDs) > 0 {
 		placeholders, pArgs := buildInClause(projectIDs)
-		query = fmt.Sprintf(`SELECT COUNT(1), COALESCE(SUM(amount),0) FROM expenses WHERE project_id IN (%s)`, placeholders)
+		query = fmt.Sprintf(`SELECT
+			SUM(CASE WHEN status = 'APPROVED' THEN 1 ELSE 0 END),
+			COALESCE(SUM(CASE WHEN status = 'APPROVED' THEN amount ELSE 0 END),0),
+			SUM(CASE WHEN status = 'PENDING' THEN 1 ELSE 0 END)
+			FROM expenses WHERE project_id IN (%s)`, placeholders)
 		args = pArgs
 	} else {
-		query = `SELECT COUNT(1), COALESCE(SUM(amount),0) FROM expenses`
+		query = `SELECT
+			SUM(CASE WHEN status = 'APPROVED' THEN 1 ELSE 0 END),
+			COALESCE(SUM(CASE WHEN status = 'APPROVED' THEN amount ELSE 0 END),0),
+			SUM(CASE WHEN status = 'PENDING' THEN 1 ELSE 0 END)
+			FROM expenses`
 	}
 
 	row := &ExpenseSummaryRow{}
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&row.TotalExpenses, &row.TotalAmount)
+	var approved, pending sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&approved, &row.TotalAmount, &pending)
 	if err != nil {
 		return nil, err
 	}
+	row.TotalExpenses = approved.Int64
+	row.PendingExpenses = pending.Int64
 	return row, nil
 }
 
@@ -155,10 +171,10 @@ func (r *DashboardRepository) GetInvoiceSummary(ctx context.Context, projectIDs
 
 	if len(projectIDs) > 0 {
 		placeholders, pArgs := buildInClause(projectIDs)
-		query = fmt.Sprintf(`SELECT COUNT(1), COALESCE(SUM(amount),0) FROM invoices WHERE project_id IN (%s)`, placeholders)
+		query = fmt.Sprintf(`SELECT COUNT(1), COALESCE(SUM(amount - credited_amount),0) FROM invoices WHERE status <> 'VOID' AND project_id IN (%s)`, placeholders)
 		args = pArgs
 	} else {
-		query = `SELECT COUNT(1), COALESCE(SUM(amount),0) FROM invoices`
+		query = `SELECT COUNT(1), COALESCE(SUM(amount - credited_amount),0) FROM invoices WHERE status <> 'VOID'`
 	}
 
 	row := &InvoiceSummaryRow{}
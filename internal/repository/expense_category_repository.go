@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type ExpenseCategoryRepository struct {
+	db *sql.DB
+}
+
+func NewExpenseCategoryRepository(db *sql.DB) *ExpenseCategoryRepository {
+	return &ExpenseCategoryRepository{db: db}
+}
+
+func (r *ExpenseCategoryRepository) Create(ctx context.Context, c *model.ExpenseCategory) (uint64, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM expense_categories WHERE code = ?)`, c.Code).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, fmt.Errorf("expense category code already exists")
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO expense_categories (code, label_id, label_en, report_category, is_active) VALUES (?, ?, ?, ?, ?)`,
+		c.Code, c.LabelID, c.LabelEN, c.ReportCategory, c.IsActive,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert expense category: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+// Update saves the labels, report mapping and active flag. Codes never change
+// since expenses refer to them.
+func (r *ExpenseCategoryRepository) Update(ctx context.Context, c *model.ExpenseCategory) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE expense_categories SET label_id = ?, label_en = ?, report_category = ?, is_active = ? WHERE id = ?`,
+		c.LabelID, c.LabelEN, c.ReportCategory, c.IsActive, c.ID,
+	)
+	return err
+}
+
+const expenseCategoryColumns = `id, code, label_id, label_en, report_category, is_active, created_at, updated_at`
+
+func scanExpenseCategory(row rowScanner) (*model.ExpenseCategory, error) {
+	var c model.ExpenseCategory
+	err := row.Scan(&c.ID, &c.Code, &c.LabelID, &c.LabelEN, &c.ReportCategory, &c.IsActive, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *ExpenseCategoryRepository) FindByID(ctx context.Context, id uint64) (*model.ExpenseCategory, error) {
+	return scanExpenseCategory(r.db.QueryRowContext(ctx,
+		`SELECT `+expenseCategoryColumns+` FROM expense_categories WHERE id = ?`, id,
+	))
+}
+
+func (r *ExpenseCategoryRepository) FindByCode(ctx context.Context, code string) (*model.ExpenseCategory, error) {
+	return scanExpenseCategory(r.db.QueryRowContext(ctx,
+		`SELECT `+expenseCategoryColumns+` FROM expense_categories WHERE code = ?`, code,
+	))
+}
+
+func (r *ExpenseCategoryRepository) FindAll(ctx context.Context, includeInactive bool) ([]model.ExpenseCategory, error) {
+	query := `SELECT ` + expenseCategoryColumns + ` FROM expense_categories`
+	if !includeInactive {
+		query += ` WHERE is_active = TRUE`
+	}
+	query += ` ORDER BY label_id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []model.ExpenseCategory
+	for rows.Next() {
+		c, err := scanExpenseCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, *c)
+	}
+	return categories, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type ExpenseRepository struct {
@@ -23,38 +24,51 @@ func (r *ExpenseRepository) Create(ctx context.Context, expense *model.Expense)
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO expenses (project_id, description, amount, category, receipt_url, created_by) VALUES (?, ?, ?, ?, ?, ?)`
-	result, err := tx.ExecContext(ctx, query,
-		expense.ProjectID, expense.Description, expense.Amount, expense.Category, expense.ReceiptURL, expense.CreatedBy,
-	)
+	id, err := insertExpense(ctx, tx, expense, true)
 	if err != nil {
 		return 0, err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		return 0, err
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
 	}
+	return id, nil
+}
 
-	// Update project budget spent amount
-	_, err = tx.ExecContext(ctx,
-		`UPDATE project_budgets SET spent_amount = spent_amount + ? WHERE project_id = ?`,
-		expense.Amount, expense.ProjectID,
+// insertExpense stores an expense inside tx. An expense created as approved
+// is added to the project spent amount right away, under the overspend
+// policy of the project when enforce is set.
+func insertExpense(ctx context.Context, tx *sql.Tx, expense *model.Expense, enforce bool) (uint64, error) {
+	if expense.Status == "" {
+		expense.Status = model.ExpenseStatusPending
+	}
+
+	query := `INSERT INTO expenses (project_id, description, amount, category, receipt_url, status, created_by) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.ExecContext(ctx, query,
+		expense.ProjectID, expense.Description, expense.Amount, expense.Category, expense.ReceiptURL, expense.Status, expense.CreatedBy,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("update budget: %w", err)
+		return 0, fmt.Errorf("insert expense: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
 	}
 
-	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("commit tx: %w", err)
+	// Only approved expenses count towards the project budget spent amount
+	if expense.Status == model.ExpenseStatusApproved {
+		if err := updateSpentAmount(ctx, tx, expense.ProjectID, expense.Amount, enforce); err != nil {
+			return 0, err
+		}
 	}
 	return uint64(id), nil
 }
 
 func (r *ExpenseRepository) FindByID(ctx context.Context, id uint64) (*model.Expense, error) {
-	query := `SELECT id, project_id, description, amount, category, receipt_url, created_by, created_at, updated_at FROM expenses WHERE id = ?`
+	query := `SELECT id, project_id, description, amount, category, receipt_url, status, created_by, created_at, updated_at FROM expenses WHERE id = ?`
 	e := &model.Expense{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
+		&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.Status, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -63,7 +77,7 @@ func (r *ExpenseRepository) FindByID(ctx context.Context, id uint64) (*model.Exp
 }
 
 func (r *ExpenseRepository) FindAll(ctx context.Context) ([]model.Expense, error) {
-	query := `SELECT id, project_id, description, amount, category, receipt_url, created_by, created_at, updated_at FROM expenses ORDER BY created_at DESC`
+	query := `SELECT id, project_id, description, amount, category, receipt_url, status, created_by, created_at, updated_at FROM expenses ORDER BY created_at DESC`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -73,7 +87,7 @@ func (r *ExpenseRepository) FindAll(ctx context.Context) ([]model.Expense, error
 	var expenses []model.Expense
 	for rows.Next() {
 		var e model.Expense
-		if err := rows.Scan(&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.Status, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, err
 		}
 		expenses = append(expenses, e)
@@ -86,7 +100,7 @@ func (r *ExpenseRepository) FindByProjectIDs(ctx context.Context, projectIDs []u
 		return nil, nil
 	}
 	placeholders, args := buildInClause(projectIDs)
-	query := fmt.Sprintf(`SELECT id, project_id, description, amount, category, receipt_url, created_by, created_at, updated_at FROM expenses WHERE project_id IN (%s) ORDER BY created_at DESC`, placeholders)
+	query := fmt.Sprintf(`SELECT id, project_id, description, amount, category, receipt_url, status, created_by, created_at, updated_at FROM expenses WHERE project_id IN (%s) ORDER BY created_at DESC`, placeholders)
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -96,7 +110,7 @@ func (r *ExpenseRepository) FindByProjectIDs(ctx context.Context, projectIDs []u
 	var expenses []model.Expense
 	for rows.Next() {
 		var e model.Expense
-		if err := rows.Scan(&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.ID, &e.ProjectID, &e.Description, &e.Amount, &e.Category, &e.ReceiptURL, &e.Status, &e.CreatedBy, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, err
 		}
 		expenses = append(expenses, e)
@@ -104,10 +118,159 @@ func (r *ExpenseRepository) FindByProjectIDs(ctx context.Context, projectIDs []u
 	return expenses, rows.Err()
 }
 
+// Update saves the editable fields of an expense. When an approved expense
+// changes amount, the project spent amount follows in the same transaction.
 func (r *ExpenseRepository) Update(ctx context.Context, expense *model.Expense) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldAmount money.Money
+	var status model.ExpenseStatus
+	err = tx.QueryRowContext(ctx, `SELECT amount, status FROM expenses WHERE id = ? FOR UPDATE`, expense.ID).Scan(&oldAmount, &status)
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE expenses SET description = ?, amount = ?, category = ?, receipt_url = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, expense.Description, expense.Amount, expense.Category, expense.ReceiptURL, expense.ID)
-	return err
+	_, err = tx.ExecContext(ctx, query, expense.Description, expense.Amount, expense.Category, expense.ReceiptURL, expense.ID)
+	if err != nil {
+		return fmt.Errorf("update expense: %w", err)
+	}
+
+	if status == model.ExpenseStatusApproved {
+		if err := updateSpentAmount(ctx, tx, expense.ProjectID, expense.Amount-oldAmount, true); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Resubmit moves a rejected expense back to PENDING for another review.
+func (r *ExpenseRepository) Resubmit(ctx context.Context, id uint64) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE expenses SET status = 'PENDING' WHERE id = ? AND status = 'REJECTED'`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("expense is not rejected")
+	}
+	return nil
+}
+
+func (r *ExpenseRepository) ApproveExpense(ctx context.Context, id, approvedBy uint64, notes, proofURL string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock row and verify PENDING status
+	var status model.ExpenseStatus
+	var projectID uint64
+	var amount money.Money
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, project_id, amount FROM expenses WHERE id = ? FOR UPDATE`, id,
+	).Scan(&status, &projectID, &amount)
+	if err != nil {
+		return err
+	}
+	if status != model.ExpenseStatusPending {
+		return fmt.Errorf("expense is not pending")
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE expenses SET status = 'APPROVED' WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("update expense: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO expense_approvals (expense_id, approved_by, status, notes, proof_url) VALUES (?, ?, 'APPROVED', ?, ?)`,
+		id, approvedBy, notes, proofURL,
+	)
+	if err != nil {
+		return fmt.Errorf("insert expense approval: %w", err)
+	}
+
+	// Update project budget spent amount
+	if err := updateSpentAmount(ctx, tx, projectID, amount, true); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpenseRepository) RejectExpense(ctx context.Context, id, approvedBy uint64, notes, proofURL string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock row and verify PENDING status
+	var status model.ExpenseStatus
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM expenses WHERE id = ? FOR UPDATE`, id,
+	).Scan(&status)
+	if err != nil {
+		return err
+	}
+	if status != model.ExpenseStatusPending {
+		return fmt.Errorf("expense is not pending")
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE expenses SET status = 'REJECTED' WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("update expense: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO expense_approvals (expense_id, approved_by, status, notes, proof_url) VALUES (?, ?, 'REJECTED', ?, ?)`,
+		id, approvedBy, notes, proofURL,
+	)
+	if err != nil {
+		return fmt.Errorf("insert expense approval: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// FindApprovals returns the review history of an expense, oldest first.
+func (r *ExpenseRepository) FindApprovals(ctx context.Context, expenseID uint64) ([]model.ExpenseApproval, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.id, a.expense_id, a.approved_by, COALESCE(u.full_name, ''), a.status, COALESCE(a.notes, ''), COALESCE(a.proof_url, ''), a.created_at
+		FROM expense_approvals a
+		LEFT JOIN users u ON u.id = a.approved_by
+		WHERE a.expense_id = ?
+		ORDER BY a.created_at ASC, a.id ASC`, expenseID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []model.ExpenseApproval
+	for rows.Next() {
+		var a model.ExpenseApproval
+		if err := rows.Scan(&a.ID, &a.ExpenseID, &a.ApprovedBy, &a.ApproverName, &a.Status, &a.Notes, &a.ProofURL, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, rows.Err()
 }
 
 func (r *ExpenseRepository) Delete(ctx context.Context, id uint64) error {
@@ -117,10 +280,11 @@ func (r *ExpenseRepository) Delete(ctx context.Context, id uint64) error {
 	}
 	defer tx.Rollback()
 
-	// Get expense amount and project_id before deleting
-	var amount float64
+	// Get expense amount, project_id and status before deleting
+	var amount money.Money
 	var projectID uint64
-	err = tx.QueryRowContext(ctx, `SELECT amount, project_id FROM expenses WHERE id = ? FOR UPDATE`, id).Scan(&amount, &projectID)
+	var status model.ExpenseStatus
+	err = tx.QueryRowContext(ctx, `SELECT amount, project_id, status FROM expenses WHERE id = ? FOR UPDATE`, id).Scan(&amount, &projectID, &status)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return sql.ErrNoRows
@@ -141,13 +305,11 @@ func (r *ExpenseRepository) Delete(ctx context.Context, id uint64) error {
 		return sql.ErrNoRows
 	}
 
-	// Deduct from project budget spent amount
-	_, err = tx.ExecContext(ctx,
-		`UPDATE project_budgets SET spent_amount = GREATEST(spent_amount - ?, 0) WHERE project_id = ?`,
-		amount, projectID,
-	)
-	if err != nil {
-		return fmt.Errorf("update budget: %w", err)
+	// Deduct from project budget spent amount if the expense was counted
+	if status == model.ExpenseStatusApproved {
+		if err := updateSpentAmount(ctx, tx, projectID, -amount, false); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
@@ -184,15 +184,16 @@ func (r *FinanceReportRepository) ReplaceManualExpenses(ctx context.Context, pro
 
 // ============ Aggregations ============
 
-// ExpensesByMemberCategory aggregates expenses table by creator (member) + category.
-// Returns map[userID]map[category]amount.
+// AggregateExpenses sums approved expenses by creator (member) and the finance
+// report category their expense category maps to.
 func (r *FinanceReportRepository) AggregateExpenses(ctx context.Context, projectID uint64) ([]AggregatedExpense, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT e.created_by, u.full_name, u.role, e.category, COALESCE(SUM(e.amount), 0)
+		SELECT e.created_by, u.full_name, u.role, COALESCE(c.report_category, 'LAIN_LAIN'), COALESCE(SUM(e.amount), 0)
 		FROM expenses e
 		LEFT JOIN users u ON u.id = e.created_by
-		WHERE e.project_id = ?
-		GROUP BY e.created_by, u.full_name, u.role, e.category`, projectID)
+		LEFT JOIN expense_categories c ON c.code = e.category
+		WHERE e.project_id = ? AND e.status = 'APPROVED'
+		GROUP BY e.created_by, u.full_name, u.role, COALESCE(c.report_category, 'LAIN_LAIN')`, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,10 @@
 package repository
 
-import "strings"
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
 
 // buildInClause returns a SQL "IN (?, ?, ...)" placeholder string and
 // a []interface{} slice suitable for use with database/sql query args.
@@ -13,3 +17,31 @@ func buildInClause(ids []uint64) (string, []interface{}) {
 	}
 	return strings.Join(placeholders, ","), args
 }
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes s for use inside a LIKE pattern, so user input only
+// matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// splitList splits a comma-separated column value; an empty string yields nil.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
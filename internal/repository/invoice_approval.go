@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// ApprovalOutcome is the effect of approving one step of an invoice's
+// approval chain.
+type ApprovalOutcome struct {
+	Step       *model.InvoiceApprovalStep // the step that was approved
+	Next       *model.InvoiceApprovalStep // nil once the final step passed
+	TotalSteps int
+}
+
+const approvalStepColumns = `s.id, s.invoice_id, s.round, s.step_order, s.policy_id, s.approver_roles, s.status,
+	s.acted_by, COALESCE(u.full_name, ''), s.acted_at, s.notes, s.created_at`
+
+func scanApprovalStep(row rowScanner) (*model.InvoiceApprovalStep, error) {
+	var st model.InvoiceApprovalStep
+	var roles string
+	var policyID, actedBy sql.NullInt64
+	var actedAt sql.NullTime
+	var notes sql.NullString
+	err := row.Scan(&st.ID, &st.InvoiceID, &st.Round, &st.StepOrder, &policyID, &roles, &st.Status,
+		&actedBy, &st.ActorName, &actedAt, &notes, &st.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	st.ApproverRoles = splitList(roles)
+	if policyID.Valid {
+		v := uint64(policyID.Int64)
+		st.PolicyID = &v
+	}
+	if actedBy.Valid {
+		v := uint64(actedBy.Int64)
+		st.ActedBy = &v
+	}
+	if actedAt.Valid {
+		st.ActedAt = &actedAt.Time
+	}
+	st.Notes = notes.String
+	return &st, nil
+}
+
+func queryApprovalSteps(ctx context.Context, q queryer, where string, args ...interface{}) ([]model.InvoiceApprovalStep, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT `+approvalStepColumns+`
+		FROM invoice_approval_steps s LEFT JOIN users u ON s.acted_by = u.id
+		WHERE `+where+` ORDER BY s.round ASC, s.step_order ASC`, args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []model.InvoiceApprovalStep
+	for rows.Next() {
+		st, err := scanApprovalStep(rows)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, *st)
+	}
+	return steps, rows.Err()
+}
+
+// FindApprovalSteps returns the approval steps of all rounds of an invoice.
+func (r *InvoiceRepository) FindApprovalSteps(ctx context.Context, invoiceID uint64) ([]model.InvoiceApprovalStep, error) {
+	return queryApprovalSteps(ctx, r.db, `s.invoice_id = ?`, invoiceID)
+}
+
+// FindCurrentApprovalSteps returns the approval steps of the latest round.
+func (r *InvoiceRepository) FindCurrentApprovalSteps(ctx context.Context, invoiceID uint64) ([]model.InvoiceApprovalStep, error) {
+	return currentApprovalSteps(ctx, r.db, invoiceID)
+}
+
+func currentApprovalSteps(ctx context.Context, q queryer, invoiceID uint64) ([]model.InvoiceApprovalStep, error) {
+	return queryApprovalSteps(ctx, q,
+		`s.invoice_id = ? AND s.round = (SELECT MAX(round) FROM invoice_approval_steps WHERE invoice_id = ?)`,
+		invoiceID, invoiceID)
+}
+
+// startApprovalRound copies the steps of the matching approval policy to the
+// invoice as a new round. Without a matching policy the invoice gets a single
+// step for the default approver roles. It is called whenever the invoice is
+// submitted or edited, since edits invalidate earlier approvals. The invoice
+// row must be locked or newly inserted in tx.
+func startApprovalRound(ctx context.Context, tx *sql.Tx, invoiceID uint64) error {
+	var invoiceType model.InvoiceType
+	var amount money.Money
+	err := tx.QueryRowContext(ctx, `SELECT invoice_type, amount FROM invoices WHERE id = ?`, invoiceID).Scan(&invoiceType, &amount)
+	if err != nil {
+		return fmt.Errorf("load invoice for approval: %w", err)
+	}
+
+	policy, err := matchApprovalPolicy(ctx, tx, invoiceType, amount)
+	if err != nil {
+		return fmt.Errorf("match approval policy: %w", err)
+	}
+	var policyID *uint64
+	steps := [][]string{model.DefaultApproverRoles}
+	if policy != nil && len(policy.Steps) > 0 {
+		policyID = &policy.ID
+		steps = nil
+		for _, st := range policy.Steps {
+			steps = append(steps, st.ApproverRoles)
+		}
+	}
+
+	// A round nobody has acted on yet is replaced; otherwise a new round starts
+	// and steps still open in the previous one no longer apply
+	var round, acted int
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(round), 0) FROM invoice_approval_steps WHERE invoice_id = ?`, invoiceID,
+	).Scan(&round)
+	if err != nil {
+		return err
+	}
+	err = tx.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM invoice_approval_steps WHERE invoice_id = ? AND round = ? AND acted_by IS NOT NULL`,
+		invoiceID, round,
+	).Scan(&acted)
+	if err != nil {
+		return err
+	}
+	if round > 0 && acted == 0 {
+		_, err = tx.ExecContext(ctx, `DELETE FROM invoice_approval_steps WHERE invoice_id = ? AND round = ?`, invoiceID, round)
+		if err != nil {
+			return fmt.Errorf("delete approval round: %w", err)
+		}
+	} else {
+		round++
+		_, err = tx.ExecContext(ctx,
+			`UPDATE invoice_approval_steps SET status = 'SKIPPED' WHERE invoice_id = ? AND status = 'PENDING'`, invoiceID,
+		)
+		if err != nil {
+			return fmt.Errorf("close approval round: %w", err)
+		}
+	}
+
+	for i, roles := range steps {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO invoice_approval_steps (invoice_id, round, step_order, policy_id, approver_roles) VALUES (?, ?, ?, ?, ?)`,
+			invoiceID, round, i+1, policyID, strings.Join(roles, ","),
+		)
+		if err != nil {
+			return fmt.Errorf("insert invoice approval step: %w", err)
+		}
+	}
+	return nil
+}
+
+// pendingApprovalSteps returns the open steps of the current round, starting
+// a round first for invoices submitted before approval chains existed.
+func pendingApprovalSteps(ctx context.Context, tx *sql.Tx, invoiceID uint64) (current []model.InvoiceApprovalStep, pending []*model.InvoiceApprovalStep, err error) {
+	current, err = currentApprovalSteps(ctx, tx, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(current) == 0 {
+		if err := startApprovalRound(ctx, tx, invoiceID); err != nil {
+			return nil, nil, err
+		}
+		if current, err = currentApprovalSteps(ctx, tx, invoiceID); err != nil {
+			return nil, nil, err
+		}
+	}
+	for i := range current {
+		if current[i].Status == model.ApprovalStepPending {
+			pending = append(pending, &current[i])
+		}
+	}
+	if len(pending) == 0 {
+		return nil, nil, fmt.Errorf("invoice has no open approval step")
+	}
+	return current, pending, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// InvoiceBalance is the settlement state of an invoice at a point in time.
+type InvoiceBalance struct {
+	Status         model.InvoiceStatus
+	Amount         money.Money
+	PaidAmount     money.Money
+	CreditedAmount money.Money
+	PaymentStatus  model.PaymentStatus
+}
+
+// Outstanding returns the amount still to be paid. It is negative when the
+// invoice has been overpaid.
+func (b *InvoiceBalance) Outstanding() money.Money {
+	return b.Amount - b.PaidAmount - b.CreditedAmount
+}
+
+// BalanceChange holds an invoice balance before and after a write.
+type BalanceChange struct {
+	Before InvoiceBalance
+	After  InvoiceBalance
+}
+
+// lockInvoiceBalance reads the balance of an invoice and locks the row for the
+// rest of the transaction.
+func lockInvoiceBalance(ctx context.Context, tx *sql.Tx, invoiceID uint64) (*InvoiceBalance, error) {
+	b := &InvoiceBalance{}
+	err := tx.QueryRowContext(ctx,
+		`SELECT status, amount, paid_amount, credited_amount, payment_status FROM invoices WHERE id = ? FOR UPDATE`, invoiceID,
+	).Scan(&b.Status, &b.Amount, &b.PaidAmount, &b.CreditedAmount, &b.PaymentStatus)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// recalcInvoiceBalance recomputes paid_amount and credited_amount from the
+// payment entries and credit notes of an invoice, then derives payment_status.
+// Reversals and refunds count against the payments. An invoice counts as PAID
+// once payments plus credits cover its amount.
+func recalcInvoiceBalance(ctx context.Context, tx *sql.Tx, invoiceID uint64) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE invoices SET
+			paid_amount = (SELECT COALESCE(SUM(CASE WHEN entry_type = 'PAYMENT' THEN amount ELSE -amount END), 0)
+				FROM invoice_payments WHERE invoice_id = ?),
+			credited_amount = (SELECT COALESCE(SUM(amount), 0) FROM credit_notes WHERE invoice_id = ?)
+		WHERE id = ?`,
+		invoiceID, invoiceID, invoiceID,
+	)
+	if err != nil {
+		return fmt.Errorf("update invoice balance: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE invoices SET
+			payment_status = CASE
+				WHEN paid_amount + credited_amount >= amount THEN 'PAID'
+				WHEN paid_amount + credited_amount > 0 THEN 'PARTIAL_PAID'
+				ELSE 'UNPAID'
+			END
+		WHERE id = ?`,
+		invoiceID,
+	)
+	if err != nil {
+		return fmt.Errorf("update invoice payment status: %w", err)
+	}
+	return nil
+}
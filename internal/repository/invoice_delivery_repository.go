@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type InvoiceDeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceDeliveryRepository(db *sql.DB) *InvoiceDeliveryRepository {
+	return &InvoiceDeliveryRepository{db: db}
+}
+
+func (r *InvoiceDeliveryRepository) Create(ctx context.Context, d *model.InvoiceDelivery) (uint64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO invoice_deliveries (invoice_id, channel, recipients, cc, subject, status, sent_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		d.InvoiceID, d.Channel, strings.Join(d.Recipients, ","), strings.Join(d.Cc, ","), d.Subject, d.Status, d.SentBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert invoice delivery: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+// UpdateStatus records the outcome of a delivery attempt. sent_at is set when the status is SENT.
+func (r *InvoiceDeliveryRepository) UpdateStatus(ctx context.Context, id uint64, status model.DeliveryStatus, errorMessage string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE invoice_deliveries SET status = ?, error_message = ?,
+			sent_at = CASE WHEN ? = 'SENT' THEN NOW() ELSE sent_at END
+		WHERE id = ?`,
+		status, errorMessage, status, id,
+	)
+	if err != nil {
+		return fmt.Errorf("update invoice delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *InvoiceDeliveryRepository) FindByID(ctx context.Context, id uint64) (*model.InvoiceDelivery, error) {
+	query := `SELECT id, invoice_id, channel, recipients, cc, subject, status, error_message, sent_by, sent_at, created_at
+	FROM invoice_deliveries WHERE id = ?`
+	return scanInvoiceDelivery(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *InvoiceDeliveryRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.InvoiceDelivery, error) {
+	query := `SELECT id, invoice_id, channel, recipients, cc, subject, status, error_message, sent_by, sent_at, created_at
+	FROM invoice_deliveries WHERE invoice_id = ? ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []model.InvoiceDelivery
+	for rows.Next() {
+		d, err := scanInvoiceDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanInvoiceDelivery(row rowScanner) (*model.InvoiceDelivery, error) {
+	d := &model.InvoiceDelivery{}
+	var recipients string
+	var cc, errorMessage sql.NullString
+	var sentAt sql.NullTime
+
+	err := row.Scan(&d.ID, &d.InvoiceID, &d.Channel, &recipients, &cc, &d.Subject, &d.Status, &errorMessage,
+		&d.SentBy, &sentAt, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Recipients = splitList(recipients)
+	d.Cc = splitList(cc.String)
+	d.ErrorMessage = errorMessage.String
+	if sentAt.Valid {
+		d.SentAt = &sentAt.Time
+	}
+	return d, nil
+}
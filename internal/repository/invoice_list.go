@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// InvoiceFilter narrows and orders an invoice listing. Zero values do not
+// filter. ProjectIDs scopes the listing to a user's projects: nil means all
+// projects and an empty slice matches nothing.
+type InvoiceFilter struct {
+	ProjectIDs    []uint64
+	ProjectID     uint64
+	Status        model.InvoiceStatus
+	PaymentStatus model.PaymentStatus
+	InvoiceType   model.InvoiceType
+	DateFrom      *time.Time
+	DateTo        *time.Time
+	DueFrom       *time.Time
+	DueTo         *time.Time
+	Recipient     string
+	Search        string // matches invoice number or PO number
+
+	Sort  string // a key of invoiceSortColumns
+	Desc  bool
+	After *InvoiceCursor // continue after this row
+	Limit int
+}
+
+// InvoiceCursor identifies the last row of a page by its sort value and id,
+// so the next page can be read with a keyset condition instead of an offset.
+type InvoiceCursor struct {
+	Value string
+	ID    uint64
+}
+
+// invoiceSortColumns maps the accepted sort keys to their indexed columns.
+// Invoices without a due date sort by their invoice date, as in the aging
+// report, through the stored due_sort_date column.
+var invoiceSortColumns = map[string]string{
+	"created_at":     "created_at",
+	"invoice_date":   "invoice_date",
+	"due_date":       "due_sort_date",
+	"amount":         "amount",
+	"invoice_number": "invoice_number",
+}
+
+// FindPage returns one page of invoices matching f and the cursor of the
+// next page, which is nil on the last page.
+func (r *InvoiceRepository) FindPage(ctx context.Context, f *InvoiceFilter) ([]model.Invoice, *InvoiceCursor, error) {
+	if f.ProjectIDs != nil && len(f.ProjectIDs) == 0 {
+		return nil, nil, nil
+	}
+	column, ok := invoiceSortColumns[f.Sort]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown invoice sort key %q", f.Sort)
+	}
+	direction, cmp := "ASC", ">"
+	if f.Desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	where, args := invoiceFilterClause(f)
+	if f.After != nil {
+		where += fmt.Sprintf(` AND (%s %s ? OR (%s = ? AND id %s ?))`, column, cmp, column, cmp)
+		args = append(args, f.After.Value, f.After.Value, f.After.ID)
+	}
+	// One extra row tells whether another page follows
+	query := fmt.Sprintf(`SELECT `+invoiceColumns+` FROM invoices WHERE %s ORDER BY %s %s, id %s LIMIT ?`,
+		where, column, direction, direction)
+	args = append(args, f.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	invoices, err := r.scanInvoices(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(invoices) <= f.Limit {
+		return invoices, nil, nil
+	}
+	invoices = invoices[:f.Limit]
+	last := &invoices[len(invoices)-1]
+	return invoices, &InvoiceCursor{Value: invoiceSortValue(last, f.Sort), ID: last.ID}, nil
+}
+
+// Count returns the number of invoices matching f, ignoring its cursor.
+func (r *InvoiceRepository) Count(ctx context.Context, f *InvoiceFilter) (int, error) {
+	if f.ProjectIDs != nil && len(f.ProjectIDs) == 0 {
+		return 0, nil
+	}
+	where, args := invoiceFilterClause(f)
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(1) FROM invoices WHERE `+where, args...).Scan(&total)
+	return total, err
+}
+
+func invoiceFilterClause(f *InvoiceFilter) (string, []interface{}) {
+	conds := []string{"1 = 1"}
+	var args []interface{}
+	if len(f.ProjectIDs) > 0 {
+		placeholders, pArgs := buildInClause(f.ProjectIDs)
+		conds = append(conds, fmt.Sprintf("project_id IN (%s)", placeholders))
+		args = append(args, pArgs...)
+	}
+	if f.ProjectID != 0 {
+		conds = append(conds, "project_id = ?")
+		args = append(args, f.ProjectID)
+	}
+	if f.Status != "" {
+		conds = append(conds, "status = ?")
+		args = append(args, f.Status)
+	}
+	if f.PaymentStatus != "" {
+		conds = append(conds, "payment_status = ?")
+		args = append(args, f.PaymentStatus)
+	}
+	if f.InvoiceType != "" {
+		conds = append(conds, "invoice_type = ?")
+		args = append(args, f.InvoiceType)
+	}
+	if f.DateFrom != nil {
+		conds = append(conds, "invoice_date >= ?")
+		args = append(args, f.DateFrom.Format("2006-01-02"))
+	}
+	if f.DateTo != nil {
+		conds = append(conds, "invoice_date <= ?")
+		args = append(args, f.DateTo.Format("2006-01-02"))
+	}
+	if f.DueFrom != nil {
+		conds = append(conds, "due_date >= ?")
+		args = append(args, f.DueFrom.Format("2006-01-02"))
+	}
+	if f.DueTo != nil {
+		conds = append(conds, "due_date <= ?")
+		args = append(args, f.DueTo.Format("2006-01-02"))
+	}
+	if f.Recipient != "" {
+		conds = append(conds, "recipient_name LIKE ?")
+		args = append(args, "%"+escapeLike(f.Recipient)+"%")
+	}
+	if f.Search != "" {
+		conds = append(conds, "(invoice_number LIKE ? OR po_number LIKE ?)")
+		like := "%" + escapeLike(f.Search) + "%"
+		args = append(args, like, like)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// invoiceSortValue returns the value of the sort column for inv in the form
+// MySQL compares against that column.
+func invoiceSortValue(inv *model.Invoice, sort string) string {
+	switch sort {
+	case "invoice_date":
+		return inv.InvoiceDate.Format("2006-01-02")
+	case "due_date":
+		if inv.DueDate != nil {
+			return inv.DueDate.Format("2006-01-02")
+		}
+		return inv.InvoiceDate.Format("2006-01-02")
+	case "amount":
+		return inv.Amount.String()
+	case "invoice_number":
+		return inv.InvoiceNumber
+	default:
+		return inv.CreatedAt.Format("2006-01-02 15:04:05")
+	}
+}
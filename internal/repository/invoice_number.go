@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DraftInvoicePrefix marks invoices that have not been assigned a final number yet.
+	DraftInvoicePrefix = "DRAFT-"
+
+	DefaultInvoiceNumberPattern    = "{SEQ:3}/INV/{COMPANY}/{MM}/{YYYY}"
+	DefaultCreditNoteNumberPattern = "{SEQ:3}/CN/{COMPANY}/{MM}/{YYYY}"
+	DefaultReceiptNumberPattern    = "{SEQ:3}/KWT/{COMPANY}/{MM}/{YYYY}"
+	defaultCompanyCode             = "INV"
+)
+
+// Document types with their own number sequence.
+const (
+	DocTypeInvoice    = "INV"
+	DocTypeCreditNote = "CN"
+	DocTypeReceipt    = "KWT"
+)
+
+// numberPatternColumns maps a document type to its pattern column in company_settings.
+var numberPatternColumns = map[string]struct {
+	column   string
+	fallback string
+}{
+	DocTypeInvoice:    {"invoice_number_pattern", DefaultInvoiceNumberPattern},
+	DocTypeCreditNote: {"credit_note_number_pattern", DefaultCreditNoteNumberPattern},
+	DocTypeReceipt:    {"receipt_number_pattern", DefaultReceiptNumberPattern},
+}
+
+var (
+	seqTokenPattern  = regexp.MustCompile(`\{SEQ(?::(\d))?\}`)
+	romanMonths      = []string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+	yearTokenPattern = regexp.MustCompile(`\{(YYYY|YY)\}`)
+)
+
+// ValidateNumberPattern checks that a document number pattern contains a
+// sequence token and a year token, since sequences reset every year.
+func ValidateNumberPattern(pattern string) error {
+	if !seqTokenPattern.MatchString(pattern) {
+		return fmt.Errorf("number pattern must contain {SEQ} or {SEQ:n}")
+	}
+	if !yearTokenPattern.MatchString(pattern) {
+		return fmt.Errorf("number pattern must contain {YYYY} or {YY}")
+	}
+	return nil
+}
+
+// formatDocumentNumber expands a number pattern. Supported tokens:
+// {SEQ}, {SEQ:n} (zero padded to n digits), {COMPANY}, {YYYY}, {YY}, {MM}, {ROMAN_MM}.
+func formatDocumentNumber(pattern, companyCode string, seq uint64, date time.Time) string {
+	out := seqTokenPattern.ReplaceAllStringFunc(pattern, func(tok string) string {
+		m := seqTokenPattern.FindStringSubmatch(tok)
+		width := 0
+		if m[1] != "" {
+			width, _ = strconv.Atoi(m[1])
+		}
+		return fmt.Sprintf("%0*d", width, seq)
+	})
+	return strings.NewReplacer(
+		"{COMPANY}", companyCode,
+		"{YYYY}", date.Format("2006"),
+		"{YY}", date.Format("06"),
+		"{ROMAN_MM}", romanMonths[date.Month()-1],
+		"{MM}", date.Format("01"),
+	).Replace(out)
+}
+
+// nextDocumentNumber reserves the next number in the sequence for docType and
+// the company code and year of date. It must run inside tx; the sequence row
+// stays locked until the transaction ends, so concurrent callers are serialized
+// and a rollback releases the number without leaving a gap.
+func nextDocumentNumber(ctx context.Context, tx *sql.Tx, docType string, date time.Time) (string, error) {
+	pc, ok := numberPatternColumns[docType]
+	if !ok {
+		return "", fmt.Errorf("unknown document type %q", docType)
+	}
+
+	companyCode := defaultCompanyCode
+	pattern := pc.fallback
+	err := tx.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT company_code, %s FROM company_settings ORDER BY id LIMIT 1`, pc.column),
+	).Scan(&companyCode, &pattern)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("get company settings: %w", err)
+	}
+
+	// A single upsert takes the row lock straight away; a separate insert and
+	// locking read can deadlock when two callers open the same new sequence.
+	year := date.Year()
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO invoice_sequences (doc_type, company_code, year, last_number) VALUES (?, ?, ?, LAST_INSERT_ID(1))
+		ON DUPLICATE KEY UPDATE last_number = LAST_INSERT_ID(last_number + 1)`,
+		docType, companyCode, year,
+	)
+	if err != nil {
+		return "", fmt.Errorf("update %s sequence: %w", docType, err)
+	}
+
+	var next uint64
+	if err := tx.QueryRowContext(ctx, `SELECT LAST_INSERT_ID()`).Scan(&next); err != nil {
+		return "", fmt.Errorf("read %s sequence: %w", docType, err)
+	}
+
+	return formatDocumentNumber(pattern, companyCode, next, date), nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type InvoicePaymentRepository struct {
@@ -16,109 +18,275 @@ func NewInvoicePaymentRepository(db *sql.DB) *InvoicePaymentRepository {
 	return &InvoicePaymentRepository{db: db}
 }
 
-func (r *InvoicePaymentRepository) Create(ctx context.Context, p *model.InvoicePayment) (uint64, error) {
+// Create records a payment. The invoice row is locked while the amount is
+// checked against the outstanding balance.
+func (r *InvoicePaymentRepository) Create(ctx context.Context, p *model.InvoicePayment) (uint64, *BalanceChange, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return 0, fmt.Errorf("begin tx: %w", err)
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
-	result, err := tx.ExecContext(ctx,
-		`INSERT INTO invoice_payments (invoice_id, amount, payment_date, payment_method, proof_url, notes, created_by)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		p.InvoiceID, p.Amount, p.PaymentDate, p.PaymentMethod, p.ProofURL, p.Notes, p.CreatedBy,
-	)
+	id, change, err := recordPayment(ctx, tx, p)
 	if err != nil {
-		return 0, fmt.Errorf("insert payment: %w", err)
+		return 0, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("commit tx: %w", err)
 	}
+	return id, change, nil
+}
 
-	id, err := result.LastInsertId()
+// recordPayment writes a payment entry inside tx after checking it against the
+// locked invoice balance.
+func recordPayment(ctx context.Context, tx *sql.Tx, p *model.InvoicePayment) (uint64, *BalanceChange, error) {
+	before, err := lockInvoiceBalance(ctx, tx, p.InvoiceID)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
+	}
+	if err := checkPaymentAllowed(before, p.Amount); err != nil {
+		return 0, nil, err
+	}
+
+	p.EntryType = model.PaymentEntryPayment
+	return writePaymentEntry(ctx, tx, p, before)
+}
+
+// checkPaymentAllowed reports whether a payment of amount can be recorded
+// against the invoice balance b.
+func checkPaymentAllowed(b *InvoiceBalance, amount money.Money) error {
+	if b.Status != model.InvoiceStatusApproved {
+		return fmt.Errorf("invoice must be approved before recording payments")
+	}
+	if remaining := b.Outstanding(); amount > remaining {
+		return fmt.Errorf("payment amount (%s) exceeds remaining balance (%s)", amount, remaining)
+	}
+	return nil
+}
+
+// Reverse records a reversal of a payment, e.g. a bounced transfer or giro.
+// The original payment is kept; the reversal takes its amount and method.
+// A payment can only be reversed once. A bank transaction matched to the
+// payment is put back to unmatched so it can be matched again.
+func (r *InvoicePaymentRepository) Reverse(ctx context.Context, rev *model.InvoicePayment) (uint64, *BalanceChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockInvoiceBalance(ctx, tx, rev.InvoiceID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	orig, err := scanPayment(tx.QueryRowContext(ctx,
+		`SELECT `+paymentColumns+` FROM invoice_payments ip WHERE ip.id = ? AND ip.invoice_id = ? FOR UPDATE`,
+		*rev.ReversedPaymentID, rev.InvoiceID,
+	))
+	if err != nil {
+		return 0, nil, err
+	}
+	if orig.EntryType != model.PaymentEntryPayment {
+		return 0, nil, fmt.Errorf("only payments can be reversed")
+	}
+	if orig.ReversedByID != nil {
+		return 0, nil, fmt.Errorf("payment is already reversed")
+	}
+	if orig.Amount > before.PaidAmount {
+		return 0, nil, fmt.Errorf("reversal exceeds the net paid amount")
+	}
+
+	rev.EntryType = model.PaymentEntryReversal
+	rev.Amount = orig.Amount
+	rev.PaymentMethod = orig.PaymentMethod
+	id, change, err := writePaymentEntry(ctx, tx, rev, before)
+	if err != nil {
+		return 0, nil, err
 	}
 
-	// Update invoice paid_amount and payment_status
 	_, err = tx.ExecContext(ctx,
-		`UPDATE invoices SET
-			paid_amount = (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?),
-			payment_status = CASE
-				WHEN (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?) >= amount THEN 'PAID'
-				WHEN (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?) > 0 THEN 'PARTIAL_PAID'
-				ELSE 'UNPAID'
-			END
-		WHERE id = ?`,
-		p.InvoiceID, p.InvoiceID, p.InvoiceID, p.InvoiceID,
+		`UPDATE bank_transactions SET status = 'UNMATCHED', invoice_id = NULL, payment_id = NULL, matched_by = NULL, matched_at = NULL
+		WHERE payment_id = ?`,
+		orig.ID,
 	)
 	if err != nil {
-		return 0, fmt.Errorf("update invoice payment status: %w", err)
+		return 0, nil, fmt.Errorf("unmatch bank transaction: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return 0, fmt.Errorf("commit tx: %w", err)
+		return 0, nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return id, change, nil
+}
+
+// Refund records money paid back to the client. Only the overpaid part of an
+// invoice (payments plus credits above the invoice amount) can be refunded.
+func (r *InvoicePaymentRepository) Refund(ctx context.Context, p *model.InvoicePayment) (uint64, *BalanceChange, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	before, err := lockInvoiceBalance(ctx, tx, p.InvoiceID)
+	if err != nil {
+		return 0, nil, err
+	}
+	overpaid := -before.Outstanding()
+	if overpaid > before.PaidAmount {
+		// Credits alone never create a refundable balance
+		overpaid = before.PaidAmount
+	}
+	if overpaid <= 0 {
+		return 0, nil, fmt.Errorf("invoice has no overpaid balance")
+	}
+	if p.Amount > overpaid {
+		return 0, nil, fmt.Errorf("refund amount (%s) exceeds overpaid balance (%s)", p.Amount, overpaid)
 	}
 
-	return uint64(id), nil
+	p.EntryType = model.PaymentEntryRefund
+	id, change, err := writePaymentEntry(ctx, tx, p, before)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return id, change, nil
 }
 
-func (r *InvoicePaymentRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.InvoicePayment, error) {
-	query := `SELECT ip.id, ip.invoice_id, ip.amount, ip.payment_date, ip.payment_method,
-		ip.proof_url, ip.notes, ip.created_by, ip.created_at, ip.updated_at
-	FROM invoice_payments ip
-	WHERE ip.invoice_id = ?
-	ORDER BY ip.payment_date ASC, ip.created_at ASC`
+// writePaymentEntry inserts a payment entry and recalculates the invoice
+// balance. The caller commits tx.
+func writePaymentEntry(ctx context.Context, tx *sql.Tx, p *model.InvoicePayment, before *InvoiceBalance) (uint64, *BalanceChange, error) {
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO invoice_payments (invoice_id, entry_type, reversed_payment_id, amount, payment_date, payment_method,
+			proof_url, reason, notes, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.InvoiceID, p.EntryType, p.ReversedPaymentID, p.Amount, p.PaymentDate, p.PaymentMethod,
+		p.ProofURL, p.Reason, p.Notes, p.CreatedBy,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("insert payment: %w", err)
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	id, err := result.LastInsertId()
 	if err != nil {
-		return nil, err
+		return 0, nil, err
 	}
-	defer rows.Close()
 
-	var payments []model.InvoicePayment
-	for rows.Next() {
-		var p model.InvoicePayment
-		var proofURL, notes sql.NullString
+	// Update invoice paid_amount and payment_status
+	if err := recalcInvoiceBalance(ctx, tx, p.InvoiceID); err != nil {
+		return 0, nil, err
+	}
+	after, err := lockInvoiceBalance(ctx, tx, p.InvoiceID)
+	if err != nil {
+		return 0, nil, err
+	}
 
-		if err := rows.Scan(
-			&p.ID, &p.InvoiceID, &p.Amount, &p.PaymentDate, &p.PaymentMethod,
-			&proofURL, &notes, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
+	return uint64(id), &BalanceChange{Before: *before, After: *after}, nil
+}
+
+const paymentColumns = `ip.id, ip.invoice_id, ip.entry_type, ip.receipt_number, ip.reversed_payment_id,
+	(SELECT rv.id FROM invoice_payments rv WHERE rv.reversed_payment_id = ip.id),
+	ip.amount, ip.payment_date, ip.payment_method, ip.proof_url, ip.reason, ip.notes,
+	ip.created_by, ip.created_at, ip.updated_at`
+
+func scanPayment(row rowScanner) (*model.InvoicePayment, error) {
+	var p model.InvoicePayment
+	var reversedPaymentID, reversedByID sql.NullInt64
+	var receiptNumber, proofURL, reason, notes sql.NullString
 
-		p.ProofURL = proofURL.String
-		p.Notes = notes.String
-		payments = append(payments, p)
+	err := row.Scan(
+		&p.ID, &p.InvoiceID, &p.EntryType, &receiptNumber, &reversedPaymentID, &reversedByID,
+		&p.Amount, &p.PaymentDate, &p.PaymentMethod, &proofURL, &reason, &notes,
+		&p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
-	return payments, rows.Err()
+
+	if reversedPaymentID.Valid {
+		v := uint64(reversedPaymentID.Int64)
+		p.ReversedPaymentID = &v
+	}
+	if reversedByID.Valid {
+		v := uint64(reversedByID.Int64)
+		p.ReversedByID = &v
+	}
+	p.ReceiptNumber = receiptNumber.String
+	p.ProofURL = proofURL.String
+	p.Reason = reason.String
+	p.Notes = notes.String
+	return &p, nil
 }
 
-func (r *InvoicePaymentRepository) Delete(ctx context.Context, id, invoiceID uint64) error {
+// AssignReceiptNumber gives a payment its receipt number from the KWT sequence
+// on first use and returns it. issued reports whether the number was assigned
+// by this call.
+func (r *InvoicePaymentRepository) AssignReceiptNumber(ctx context.Context, paymentID uint64) (number string, issued bool, err error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return "", false, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, `DELETE FROM invoice_payments WHERE id = ?`, id)
+	var entryType model.PaymentEntryType
+	var existing sql.NullString
+	var paymentDate time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT entry_type, receipt_number, payment_date FROM invoice_payments WHERE id = ? FOR UPDATE`, paymentID,
+	).Scan(&entryType, &existing, &paymentDate)
+	if err != nil {
+		return "", false, err
+	}
+	if entryType != model.PaymentEntryPayment {
+		return "", false, fmt.Errorf("receipts are only issued for payments")
+	}
+	if existing.Valid && existing.String != "" {
+		return existing.String, false, nil
+	}
+
+	number, err = nextDocumentNumber(ctx, tx, DocTypeReceipt, paymentDate)
 	if err != nil {
-		return fmt.Errorf("delete payment: %w", err)
+		return "", false, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE invoice_payments SET receipt_number = ? WHERE id = ?`, number, paymentID)
+	if err != nil {
+		return "", false, fmt.Errorf("update receipt number: %w", err)
 	}
 
-	// Recalculate invoice paid_amount and payment_status
-	_, err = tx.ExecContext(ctx,
-		`UPDATE invoices SET
-			paid_amount = (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?),
-			payment_status = CASE
-				WHEN (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?) >= amount THEN 'PAID'
-				WHEN (SELECT COALESCE(SUM(amount), 0) FROM invoice_payments WHERE invoice_id = ?) > 0 THEN 'PARTIAL_PAID'
-				ELSE 'UNPAID'
-			END
-		WHERE id = ?`,
-		invoiceID, invoiceID, invoiceID, invoiceID,
-	)
+	if err := tx.Commit(); err != nil {
+		return "", false, fmt.Errorf("commit tx: %w", err)
+	}
+	return number, true, nil
+}
+
+func (r *InvoicePaymentRepository) FindByID(ctx context.Context, id uint64) (*model.InvoicePayment, error) {
+	return scanPayment(r.db.QueryRowContext(ctx,
+		`SELECT `+paymentColumns+` FROM invoice_payments ip WHERE ip.id = ?`, id,
+	))
+}
+
+func (r *InvoicePaymentRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.InvoicePayment, error) {
+	query := `SELECT ` + paymentColumns + `
+	FROM invoice_payments ip
+	WHERE ip.invoice_id = ?
+	ORDER BY ip.payment_date ASC, ip.created_at ASC, ip.id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, invoiceID)
 	if err != nil {
-		return fmt.Errorf("update invoice payment status: %w", err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	return tx.Commit()
+	var payments []model.InvoicePayment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, *p)
+	}
+	return payments, rows.Err()
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type InvoiceReminderRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceReminderRepository(db *sql.DB) *InvoiceReminderRepository {
+	return &InvoiceReminderRepository{db: db}
+}
+
+// MarkSent records that the reminder stage was sent for the invoice. It returns
+// false when the stage had already been recorded.
+func (r *InvoiceReminderRepository) MarkSent(ctx context.Context, invoiceID uint64, daysOverdue int) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO invoice_reminders (invoice_id, days_overdue) VALUES (?, ?)`,
+		invoiceID, daysOverdue,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert invoice reminder: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
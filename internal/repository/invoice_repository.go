@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
@@ -24,16 +25,22 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 	}
 	defer tx.Rollback()
 
+	if err := checkContractBilling(ctx, tx, inv, 0); err != nil {
+		return 0, err
+	}
+
 	// Insert invoice with temp number
 	tempNumber := fmt.Sprintf("TEMP-%d", time.Now().UnixNano())
 	result, err := tx.ExecContext(ctx,
-		`INSERT INTO invoices (invoice_number, invoice_type, project_id, amount, paid_amount, status, payment_status, file_url,
-			recipient_name, recipient_address, attention, po_number, invoice_date, due_date,
-			dp_percentage, subtotal, ppn_percentage, ppn_amount, pph_percentage, pph_amount, notes, language, created_by)
-		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		tempNumber, inv.InvoiceType, inv.ProjectID, inv.Amount, model.InvoiceStatusPending, inv.PaymentStatus, inv.FileURL,
-		inv.RecipientName, inv.RecipientAddress, inv.Attention, inv.PONumber, inv.InvoiceDate, inv.DueDate,
-		inv.DPPercentage, inv.Subtotal, inv.PPNPercentage, inv.PPNAmount, inv.PPHPercentage, inv.PPHAmount, inv.Notes, inv.Language, inv.CreatedBy,
+		`INSERT INTO invoices (invoice_number, invoice_type, project_id, client_id, billing_term_id, amount, paid_amount, status, payment_status, file_url,
+			recipient_name, recipient_address, recipient_npwp, attention, po_number, invoice_date, due_date,
+			dp_percentage, subtotal, discount_percent, discount_amount, dpp_amount, ppn_mode, ppn_base, ppn_percentage, ppn_amount,
+			pph_percentage, pph_amount, pph21_percentage, pph21_amount, notes, language, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		tempNumber, inv.InvoiceType, inv.ProjectID, inv.ClientID, inv.BillingTermID, inv.Amount, model.InvoiceStatusPending, inv.PaymentStatus, inv.FileURL,
+		inv.RecipientName, inv.RecipientAddress, inv.RecipientNPWP, inv.Attention, inv.PONumber, inv.InvoiceDate, inv.DueDate,
+		inv.DPPercentage, inv.Subtotal, inv.DiscountPercent, inv.DiscountAmount, inv.DPPAmount, inv.PPNMode, inv.PPNBase, inv.PPNPercentage, inv.PPNAmount,
+		inv.PPHPercentage, inv.PPHAmount, inv.PPH21Percentage, inv.PPH21Amount, inv.Notes, inv.Language, inv.CreatedBy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("insert invoice: %w", err)
@@ -44,19 +51,8 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 		return 0, err
 	}
 
-	// Generate invoice number: {seq}/INV/{company_code}/{MM}/{YYYY}
-	var companyCode string
-	err = tx.QueryRowContext(ctx, `SELECT company_code FROM company_settings LIMIT 1`).Scan(&companyCode)
-	if err != nil {
-		companyCode = "INV" // fallback
-	}
-
-	invoiceNumber := fmt.Sprintf("%03d/INV/%s/%s/%s",
-		id,
-		companyCode,
-		inv.InvoiceDate.Format("01"),
-		inv.InvoiceDate.Format("2006"),
-	)
+	// Drafts get a placeholder number; the final number is assigned at approval
+	invoiceNumber := fmt.Sprintf("%s%d", DraftInvoicePrefix, id)
 	_, err = tx.ExecContext(ctx, `UPDATE invoices SET invoice_number = ? WHERE id = ?`, invoiceNumber, id)
 	if err != nil {
 		return 0, fmt.Errorf("update invoice number: %w", err)
@@ -82,9 +78,11 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 			// Insert children for this label
 			for j, child := range item.Children {
 				_, err = tx.ExecContext(ctx,
-					`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price, subtotal, sort_order)
-					VALUES (?, ?, FALSE, ?, ?, ?, ?, ?, ?)`,
-					id, labelID, child.Description, child.Quantity, child.Unit, child.UnitPrice, child.Subtotal, j,
+					`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price,
+						discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order)
+					VALUES (?, ?, FALSE, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					id, labelID, child.Description, child.Quantity, child.Unit, child.UnitPrice,
+					child.DiscountPercent, child.DiscountAmount, child.Subtotal, child.PPNCode, child.PPHCode, j,
 				)
 				if err != nil {
 					return 0, fmt.Errorf("insert invoice item under label: %w", err)
@@ -93,9 +91,11 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 		} else {
 			// Standalone item (no label parent)
 			_, err = tx.ExecContext(ctx,
-				`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price, subtotal, sort_order)
-				VALUES (?, NULL, FALSE, ?, ?, ?, ?, ?, ?)`,
-				id, item.Description, item.Quantity, item.Unit, item.UnitPrice, item.Subtotal, i,
+				`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price,
+					discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order)
+				VALUES (?, NULL, FALSE, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				id, item.Description, item.Quantity, item.Unit, item.UnitPrice,
+				item.DiscountPercent, item.DiscountAmount, item.Subtotal, item.PPNCode, item.PPHCode, i,
 			)
 			if err != nil {
 				return 0, fmt.Errorf("insert invoice item: %w", err)
@@ -103,6 +103,14 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 		}
 	}
 
+	if err := startApprovalRound(ctx, tx, uint64(id)); err != nil {
+		return 0, err
+	}
+
+	if err := writeInvoiceRevision(ctx, tx, uint64(id), model.InvoiceRevisionCreate, inv.CreatedBy); err != nil {
+		return 0, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return 0, fmt.Errorf("commit tx: %w", err)
 	}
@@ -111,56 +119,27 @@ func (r *InvoiceRepository) Create(ctx context.Context, inv *model.Invoice, item
 }
 
 func (r *InvoiceRepository) FindByID(ctx context.Context, id uint64) (*model.Invoice, error) {
-	query := `SELECT id, invoice_number, invoice_type, project_id, amount, paid_amount, status, payment_status, file_url,
-		recipient_name, recipient_address, attention, po_number, invoice_date, due_date,
-		dp_percentage, subtotal, ppn_percentage, ppn_amount, pph_percentage, pph_amount, notes, language,
-		created_by, approved_by, reject_notes, created_at, updated_at
-	FROM invoices WHERE id = ?`
-
-	inv := &model.Invoice{}
-	var fileURL, recipientAddr, attention, poNumber, notes, rejectNotes sql.NullString
-	var dpPercentage sql.NullFloat64
-	var approvedBy sql.NullInt64
-	var dueDate sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&inv.ID, &inv.InvoiceNumber, &inv.InvoiceType, &inv.ProjectID, &inv.Amount, &inv.PaidAmount, &inv.Status, &inv.PaymentStatus, &fileURL,
-		&inv.RecipientName, &recipientAddr, &attention, &poNumber, &inv.InvoiceDate, &dueDate,
-		&dpPercentage, &inv.Subtotal, &inv.PPNPercentage, &inv.PPNAmount, &inv.PPHPercentage, &inv.PPHAmount, &notes, &inv.Language,
-		&inv.CreatedBy, &approvedBy, &rejectNotes, &inv.CreatedAt, &inv.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	inv.FileURL = fileURL.String
-	inv.RecipientAddress = recipientAddr.String
-	inv.Attention = attention.String
-	inv.PONumber = poNumber.String
-	inv.Notes = notes.String
-	inv.RejectNotes = rejectNotes.String
-	if dpPercentage.Valid {
-		inv.DPPercentage = &dpPercentage.Float64
-	}
-	if approvedBy.Valid {
-		v := uint64(approvedBy.Int64)
-		inv.ApprovedBy = &v
-	}
-	if dueDate.Valid {
-		inv.DueDate = &dueDate.Time
-	}
-
-	return inv, nil
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE id = ?`
+	return scanInvoice(r.db.QueryRowContext(ctx, query, id))
 }
 
-func (r *InvoiceRepository) FindAll(ctx context.Context) ([]model.Invoice, error) {
-	query := `SELECT id, invoice_number, invoice_type, project_id, amount, paid_amount, status, payment_status, file_url,
-		recipient_name, recipient_address, attention, po_number, invoice_date, due_date,
-		dp_percentage, subtotal, ppn_percentage, ppn_amount, pph_percentage, pph_amount, notes, language,
-		created_by, approved_by, reject_notes, created_at, updated_at
-	FROM invoices ORDER BY created_at DESC`
+// FindOutstanding returns approved invoices that still have an open balance.
+// When projectIDs is non-nil the result is limited to those projects.
+func (r *InvoiceRepository) FindOutstanding(ctx context.Context, projectIDs []uint64) ([]model.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices
+	WHERE status = 'APPROVED' AND amount - paid_amount - credited_amount > 0`
+	var args []interface{}
+	if projectIDs != nil {
+		if len(projectIDs) == 0 {
+			return nil, nil
+		}
+		placeholders, pArgs := buildInClause(projectIDs)
+		query += fmt.Sprintf(` AND project_id IN (%s)`, placeholders)
+		args = pArgs
+	}
+	query += ` ORDER BY COALESCE(due_date, invoice_date) ASC, id ASC`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -168,18 +147,14 @@ func (r *InvoiceRepository) FindAll(ctx context.Context) ([]model.Invoice, error
 	return r.scanInvoices(rows)
 }
 
-func (r *InvoiceRepository) FindByProjectIDs(ctx context.Context, projectIDs []uint64) ([]model.Invoice, error) {
-	if len(projectIDs) == 0 {
-		return nil, nil
-	}
-	placeholders, args := buildInClause(projectIDs)
-	query := fmt.Sprintf(`SELECT id, invoice_number, invoice_type, project_id, amount, paid_amount, status, payment_status, file_url,
-		recipient_name, recipient_address, attention, po_number, invoice_date, due_date,
-		dp_percentage, subtotal, ppn_percentage, ppn_amount, pph_percentage, pph_amount, notes, language,
-		created_by, approved_by, reject_notes, created_at, updated_at
-	FROM invoices WHERE project_id IN (%s) ORDER BY created_at DESC`, placeholders)
+// FindApprovedByDate returns approved invoices dated from..to inclusive, in
+// date order.
+func (r *InvoiceRepository) FindApprovedByDate(ctx context.Context, from, to time.Time) ([]model.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices
+	WHERE status = 'APPROVED' AND invoice_date BETWEEN ? AND ?
+	ORDER BY invoice_date ASC, id ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"))
 	if err != nil {
 		return nil, err
 	}
@@ -188,10 +163,15 @@ func (r *InvoiceRepository) FindByProjectIDs(ctx context.Context, projectIDs []u
 }
 
 func (r *InvoiceRepository) FindItemsByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.InvoiceItem, error) {
-	query := `SELECT id, invoice_id, parent_id, is_label, description, quantity, unit, unit_price, subtotal, sort_order, created_at
+	return findInvoiceItems(ctx, r.db, invoiceID)
+}
+
+func findInvoiceItems(ctx context.Context, q queryer, invoiceID uint64) ([]model.InvoiceItem, error) {
+	query := `SELECT id, invoice_id, parent_id, is_label, description, quantity, unit, unit_price,
+		discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order, created_at
 	FROM invoice_items WHERE invoice_id = ? ORDER BY sort_order ASC`
 
-	rows, err := r.db.QueryContext(ctx, query, invoiceID)
+	rows, err := q.QueryContext(ctx, query, invoiceID)
 	if err != nil {
 		return nil, err
 	}
@@ -202,7 +182,8 @@ func (r *InvoiceRepository) FindItemsByInvoiceID(ctx context.Context, invoiceID
 		var item model.InvoiceItem
 		var parentID sql.NullInt64
 		if err := rows.Scan(&item.ID, &item.InvoiceID, &parentID, &item.IsLabel, &item.Description, &item.Quantity,
-			&item.Unit, &item.UnitPrice, &item.Subtotal, &item.SortOrder, &item.CreatedAt); err != nil {
+			&item.Unit, &item.UnitPrice, &item.DiscountPercent, &item.DiscountAmount, &item.Subtotal,
+			&item.PPNCode, &item.PPHCode, &item.SortOrder, &item.CreatedAt); err != nil {
 			return nil, err
 		}
 		if parentID.Valid {
@@ -214,21 +195,53 @@ func (r *InvoiceRepository) FindItemsByInvoiceID(ctx context.Context, invoiceID
 	return items, rows.Err()
 }
 
-func (r *InvoiceRepository) Update(ctx context.Context, inv *model.Invoice, items []model.InvoiceItem) error {
+// Update saves the invoice header and, when items is non-nil, replaces its
+// items. Every save is recorded as a new revision by updatedBy. Saving a
+// rejected invoice resubmits it for approval.
+func (r *InvoiceRepository) Update(ctx context.Context, inv *model.Invoice, items []model.InvoiceItem, updatedBy uint64) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
+	var createdBy uint64
+	var status model.InvoiceStatus
+	err = tx.QueryRowContext(ctx, `SELECT created_by, status FROM invoices WHERE id = ? FOR UPDATE`, inv.ID).Scan(&createdBy, &status)
+	if err != nil {
+		return err
+	}
+	if status != model.InvoiceStatusPending && status != model.InvoiceStatusRejected {
+		return fmt.Errorf("only pending or rejected invoices can be updated")
+	}
+
+	// Invoices created before revisions were recorded get their current state
+	// stored first, so the first change can still be diffed
+	hasRevisions, err := hasInvoiceRevisions(ctx, tx, inv.ID)
+	if err != nil {
+		return err
+	}
+	if !hasRevisions {
+		if err := writeInvoiceRevision(ctx, tx, inv.ID, model.InvoiceRevisionBaseline, createdBy); err != nil {
+			return err
+		}
+	}
+
+	if err := checkContractBilling(ctx, tx, inv, inv.ID); err != nil {
+		return err
+	}
+
 	_, err = tx.ExecContext(ctx,
-		`UPDATE invoices SET recipient_name = ?, recipient_address = ?, attention = ?,
-			po_number = ?, invoice_date = ?, dp_percentage = ?, subtotal = ?,
-			ppn_percentage = ?, ppn_amount = ?, pph_percentage = ?, pph_amount = ?, amount = ?, notes = ?, language = ?, file_url = ?
+		`UPDATE invoices SET client_id = ?, recipient_name = ?, recipient_address = ?, recipient_npwp = ?, attention = ?,
+			po_number = ?, invoice_date = ?, due_date = ?, dp_percentage = ?, subtotal = ?,
+			discount_percent = ?, discount_amount = ?, dpp_amount = ?, ppn_mode = ?, ppn_base = ?, ppn_percentage = ?, ppn_amount = ?,
+			pph_percentage = ?, pph_amount = ?, pph21_percentage = ?, pph21_amount = ?, amount = ?, notes = ?, language = ?, file_url = ?,
+			status = 'PENDING', approved_by = NULL, reject_notes = NULL
 		WHERE id = ?`,
-		inv.RecipientName, inv.RecipientAddress, inv.Attention,
-		inv.PONumber, inv.InvoiceDate, inv.DPPercentage, inv.Subtotal,
-		inv.PPNPercentage, inv.PPNAmount, inv.PPHPercentage, inv.PPHAmount, inv.Amount, inv.Notes, inv.Language, inv.FileURL,
+		inv.ClientID, inv.RecipientName, inv.RecipientAddress, inv.RecipientNPWP, inv.Attention,
+		inv.PONumber, inv.InvoiceDate, inv.DueDate, inv.DPPercentage, inv.Subtotal,
+		inv.DiscountPercent, inv.DiscountAmount, inv.DPPAmount, inv.PPNMode, inv.PPNBase, inv.PPNPercentage, inv.PPNAmount,
+		inv.PPHPercentage, inv.PPHAmount, inv.PPH21Percentage, inv.PPH21Amount, inv.Amount, inv.Notes, inv.Language, inv.FileURL,
 		inv.ID,
 	)
 	if err != nil {
@@ -257,9 +270,11 @@ func (r *InvoiceRepository) Update(ctx context.Context, inv *model.Invoice, item
 				}
 				for j, child := range item.Children {
 					_, err = tx.ExecContext(ctx,
-						`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price, subtotal, sort_order)
-						VALUES (?, ?, FALSE, ?, ?, ?, ?, ?, ?)`,
-						inv.ID, labelID, child.Description, child.Quantity, child.Unit, child.UnitPrice, child.Subtotal, j,
+						`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price,
+							discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order)
+						VALUES (?, ?, FALSE, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+						inv.ID, labelID, child.Description, child.Quantity, child.Unit, child.UnitPrice,
+						child.DiscountPercent, child.DiscountAmount, child.Subtotal, child.PPNCode, child.PPHCode, j,
 					)
 					if err != nil {
 						return fmt.Errorf("insert invoice item under label: %w", err)
@@ -267,9 +282,11 @@ func (r *InvoiceRepository) Update(ctx context.Context, inv *model.Invoice, item
 				}
 			} else {
 				_, err = tx.ExecContext(ctx,
-					`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price, subtotal, sort_order)
-					VALUES (?, NULL, FALSE, ?, ?, ?, ?, ?, ?)`,
-					inv.ID, item.Description, item.Quantity, item.Unit, item.UnitPrice, item.Subtotal, i,
+					`INSERT INTO invoice_items (invoice_id, parent_id, is_label, description, quantity, unit, unit_price,
+						discount_percent, discount_amount, subtotal, ppn_code, pph_code, sort_order)
+					VALUES (?, NULL, FALSE, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+					inv.ID, item.Description, item.Quantity, item.Unit, item.UnitPrice,
+					item.DiscountPercent, item.DiscountAmount, item.Subtotal, item.PPNCode, item.PPHCode, i,
 				)
 				if err != nil {
 					return fmt.Errorf("insert invoice item: %w", err)
@@ -278,6 +295,15 @@ func (r *InvoiceRepository) Update(ctx context.Context, inv *model.Invoice, item
 		}
 	}
 
+	// A rejected invoice is resubmitted by saving it
+	if err := startApprovalRound(ctx, tx, inv.ID); err != nil {
+		return err
+	}
+
+	if err := writeInvoiceRevision(ctx, tx, inv.ID, model.InvoiceRevisionUpdate, updatedBy); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -297,7 +323,18 @@ func (r *InvoiceRepository) Delete(ctx context.Context, id uint64) error {
 	return nil
 }
 
-func (r *InvoiceRepository) ApproveInvoice(ctx context.Context, invoiceID, approvedBy uint64, notes string) error {
+func (r *InvoiceRepository) UpdateFileURL(ctx context.Context, id uint64, fileURL string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE invoices SET file_url = ? WHERE id = ?`, fileURL, id)
+	if err != nil {
+		return fmt.Errorf("update invoice file url: %w", err)
+	}
+	return nil
+}
+
+// SetTaxInvoiceNumber records the tax invoice number (NSFP) of an approved
+// invoice, or clears it when number is empty. A number can be used by one
+// invoice only.
+func (r *InvoiceRepository) SetTaxInvoiceNumber(ctx context.Context, id uint64, number string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -305,31 +342,119 @@ func (r *InvoiceRepository) ApproveInvoice(ctx context.Context, invoiceID, appro
 	defer tx.Rollback()
 
 	var status model.InvoiceStatus
-	err = tx.QueryRowContext(ctx,
-		`SELECT status FROM invoices WHERE id = ? FOR UPDATE`, invoiceID,
-	).Scan(&status)
+	err = tx.QueryRowContext(ctx, `SELECT status FROM invoices WHERE id = ? FOR UPDATE`, id).Scan(&status)
 	if err != nil {
 		return err
 	}
+	if status != model.InvoiceStatusApproved {
+		return fmt.Errorf("tax invoice numbers can only be set on approved invoices")
+	}
+
+	var value sql.NullString
+	if number != "" {
+		var exists bool
+		err = tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM invoices WHERE tax_invoice_number = ? AND id <> ?)`, number, id,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return fmt.Errorf("tax invoice number is already used by another invoice")
+		}
+		value = sql.NullString{String: number, Valid: true}
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE invoices SET tax_invoice_number = ? WHERE id = ?`, value, id); err != nil {
+		return fmt.Errorf("update tax invoice number: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ApproveInvoice approves the current step of the invoice's approval chain.
+// The user's role must be one of the step's approver roles, and a user may
+// approve only one step per round. When the final step passes the invoice
+// becomes APPROVED and gets its number.
+func (r *InvoiceRepository) ApproveInvoice(ctx context.Context, invoiceID, approvedBy uint64, role, notes string) (*ApprovalOutcome, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status model.InvoiceStatus
+	var invoiceNumber string
+	var invoiceDate time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT status, invoice_number, invoice_date FROM invoices WHERE id = ? FOR UPDATE`, invoiceID,
+	).Scan(&status, &invoiceNumber, &invoiceDate)
+	if err != nil {
+		return nil, err
+	}
 	if status != model.InvoiceStatusPending {
-		return fmt.Errorf("invoice is not pending")
+		return nil, fmt.Errorf("invoice is not pending")
 	}
 
+	current, pending, err := pendingApprovalSteps(ctx, tx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	step := pending[0]
+	if !step.CanAct(role) {
+		return nil, fmt.Errorf("not authorized to approve this step")
+	}
+	for _, st := range current {
+		if st.Status == model.ApprovalStepApproved && st.ActedBy != nil && *st.ActedBy == approvedBy {
+			return nil, fmt.Errorf("approver already approved an earlier step")
+		}
+	}
+
+	now := time.Now()
 	_, err = tx.ExecContext(ctx,
-		`UPDATE invoices SET status = 'APPROVED', approved_by = ? WHERE id = ?`,
-		approvedBy, invoiceID,
+		`UPDATE invoice_approval_steps SET status = 'APPROVED', acted_by = ?, acted_at = ?, notes = ? WHERE id = ?`,
+		approvedBy, now, notes, step.ID,
 	)
 	if err != nil {
-		return fmt.Errorf("update invoice: %w", err)
+		return nil, fmt.Errorf("update approval step: %w", err)
 	}
+	step.Status, step.ActedBy, step.ActedAt, step.Notes = model.ApprovalStepApproved, &approvedBy, &now, notes
 
-	return tx.Commit()
+	outcome := &ApprovalOutcome{Step: step, TotalSteps: len(current)}
+	if len(pending) > 1 {
+		outcome.Next = pending[1]
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit tx: %w", err)
+		}
+		return outcome, nil
+	}
+
+	if strings.HasPrefix(invoiceNumber, DraftInvoicePrefix) {
+		invoiceNumber, err = nextDocumentNumber(ctx, tx, DocTypeInvoice, invoiceDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE invoices SET status = 'APPROVED', approved_by = ?, invoice_number = ? WHERE id = ?`,
+		approvedBy, invoiceNumber, invoiceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return outcome, nil
 }
 
-func (r *InvoiceRepository) RejectInvoice(ctx context.Context, invoiceID, rejectedBy uint64, notes string) error {
+// RejectInvoice rejects the current approval step and returns the invoice to
+// its creator as REJECTED. The remaining steps of the round are skipped.
+func (r *InvoiceRepository) RejectInvoice(ctx context.Context, invoiceID, rejectedBy uint64, role, notes string) (*model.InvoiceApprovalStep, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("begin tx: %w", err)
+		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback()
 
@@ -338,18 +463,77 @@ func (r *InvoiceRepository) RejectInvoice(ctx context.Context, invoiceID, reject
 		`SELECT status FROM invoices WHERE id = ? FOR UPDATE`, invoiceID,
 	).Scan(&status)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if status != model.InvoiceStatusPending {
-		return fmt.Errorf("invoice is not pending")
+		return nil, fmt.Errorf("invoice is not pending")
+	}
+
+	_, pending, err := pendingApprovalSteps(ctx, tx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	step := pending[0]
+	if !step.CanAct(role) {
+		return nil, fmt.Errorf("not authorized to reject this step")
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx,
+		`UPDATE invoice_approval_steps SET status = 'REJECTED', acted_by = ?, acted_at = ?, notes = ? WHERE id = ?`,
+		rejectedBy, now, notes, step.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update approval step: %w", err)
 	}
+	_, err = tx.ExecContext(ctx,
+		`UPDATE invoice_approval_steps SET status = 'SKIPPED' WHERE invoice_id = ? AND status = 'PENDING'`, invoiceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("skip approval steps: %w", err)
+	}
+	step.Status, step.ActedBy, step.ActedAt, step.Notes = model.ApprovalStepRejected, &rejectedBy, &now, notes
 
 	_, err = tx.ExecContext(ctx,
 		`UPDATE invoices SET status = 'REJECTED', approved_by = ?, reject_notes = ? WHERE id = ?`,
 		rejectedBy, notes, invoiceID,
 	)
 	if err != nil {
-		return fmt.Errorf("update invoice: %w", err)
+		return nil, fmt.Errorf("update invoice: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return step, nil
+}
+
+// VoidInvoice marks an approved invoice without payments as VOID.
+func (r *InvoiceRepository) VoidInvoice(ctx context.Context, invoiceID, voidedBy uint64, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	balance, err := lockInvoiceBalance(ctx, tx, invoiceID)
+	if err != nil {
+		return err
+	}
+	if balance.Status != model.InvoiceStatusApproved {
+		return fmt.Errorf("only approved invoices can be voided")
+	}
+	// Payments that were fully reversed or refunded no longer block voiding
+	if !balance.PaidAmount.IsZero() {
+		return fmt.Errorf("invoice with payments cannot be voided")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE invoices SET status = 'VOID', void_reason = ?, voided_by = ?, voided_at = NOW() WHERE id = ?`,
+		reason, voidedBy, invoiceID,
+	)
+	if err != nil {
+		return fmt.Errorf("void invoice: %w", err)
 	}
 
 	return tx.Commit()
@@ -358,39 +542,73 @@ func (r *InvoiceRepository) RejectInvoice(ctx context.Context, invoiceID, reject
 func (r *InvoiceRepository) scanInvoices(rows *sql.Rows) ([]model.Invoice, error) {
 	var invoices []model.Invoice
 	for rows.Next() {
-		var inv model.Invoice
-		var fileURL, recipientAddr, attention, poNumber, notes, rejectNotes sql.NullString
-		var dpPercentage sql.NullFloat64
-		var approvedBy sql.NullInt64
-		var dueDate sql.NullTime
-
-		if err := rows.Scan(
-			&inv.ID, &inv.InvoiceNumber, &inv.InvoiceType, &inv.ProjectID, &inv.Amount, &inv.PaidAmount, &inv.Status, &inv.PaymentStatus, &fileURL,
-			&inv.RecipientName, &recipientAddr, &attention, &poNumber, &inv.InvoiceDate, &dueDate,
-			&dpPercentage, &inv.Subtotal, &inv.PPNPercentage, &inv.PPNAmount, &inv.PPHPercentage, &inv.PPHAmount, &notes, &inv.Language,
-			&inv.CreatedBy, &approvedBy, &rejectNotes, &inv.CreatedAt, &inv.UpdatedAt,
-		); err != nil {
+		inv, err := scanInvoice(rows)
+		if err != nil {
 			return nil, err
 		}
+		invoices = append(invoices, *inv)
+	}
+	return invoices, rows.Err()
+}
 
-		inv.FileURL = fileURL.String
-		inv.RecipientAddress = recipientAddr.String
-		inv.Attention = attention.String
-		inv.PONumber = poNumber.String
-		inv.Notes = notes.String
-		inv.RejectNotes = rejectNotes.String
-		if dpPercentage.Valid {
-			inv.DPPercentage = &dpPercentage.Float64
-		}
-		if approvedBy.Valid {
-			v := uint64(approvedBy.Int64)
-			inv.ApprovedBy = &v
-		}
-		if dueDate.Valid {
-			inv.DueDate = &dueDate.Time
-		}
+// invoiceColumns is the column list scanned by scanInvoice.
+const invoiceColumns = `id, invoice_number, invoice_type, project_id, client_id, billing_term_id, amount, paid_amount, credited_amount, status, payment_status, file_url,
+		recipient_name, recipient_address, recipient_npwp, tax_invoice_number, attention, po_number, invoice_date, due_date,
+		dp_percentage, subtotal, discount_percent, discount_amount, dpp_amount, ppn_mode, ppn_base, ppn_percentage, ppn_amount,
+		pph_percentage, pph_amount, pph21_percentage, pph21_amount, notes, language,
+		created_by, approved_by, reject_notes, void_reason, voided_by, voided_at, created_at, updated_at`
 
-		invoices = append(invoices, inv)
+func scanInvoice(row rowScanner) (*model.Invoice, error) {
+	inv := &model.Invoice{}
+	var fileURL, recipientAddr, recipientNPWP, taxInvoiceNumber, attention, poNumber, notes, rejectNotes, voidReason sql.NullString
+	var dpPercentage sql.NullFloat64
+	var clientID, billingTermID, approvedBy, voidedBy sql.NullInt64
+	var dueDate, voidedAt sql.NullTime
+
+	err := row.Scan(
+		&inv.ID, &inv.InvoiceNumber, &inv.InvoiceType, &inv.ProjectID, &clientID, &billingTermID, &inv.Amount, &inv.PaidAmount, &inv.CreditedAmount, &inv.Status, &inv.PaymentStatus, &fileURL,
+		&inv.RecipientName, &recipientAddr, &recipientNPWP, &taxInvoiceNumber, &attention, &poNumber, &inv.InvoiceDate, &dueDate,
+		&dpPercentage, &inv.Subtotal, &inv.DiscountPercent, &inv.DiscountAmount, &inv.DPPAmount, &inv.PPNMode, &inv.PPNBase, &inv.PPNPercentage, &inv.PPNAmount,
+		&inv.PPHPercentage, &inv.PPHAmount, &inv.PPH21Percentage, &inv.PPH21Amount, &notes, &inv.Language,
+		&inv.CreatedBy, &approvedBy, &rejectNotes, &voidReason, &voidedBy, &voidedAt, &inv.CreatedAt, &inv.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
-	return invoices, rows.Err()
+
+	inv.FileURL = fileURL.String
+	inv.RecipientAddress = recipientAddr.String
+	inv.RecipientNPWP = recipientNPWP.String
+	inv.TaxInvoiceNumber = taxInvoiceNumber.String
+	inv.Attention = attention.String
+	inv.PONumber = poNumber.String
+	inv.Notes = notes.String
+	inv.RejectNotes = rejectNotes.String
+	inv.VoidReason = voidReason.String
+	if dpPercentage.Valid {
+		inv.DPPercentage = &dpPercentage.Float64
+	}
+	if clientID.Valid {
+		v := uint64(clientID.Int64)
+		inv.ClientID = &v
+	}
+	if billingTermID.Valid {
+		v := uint64(billingTermID.Int64)
+		inv.BillingTermID = &v
+	}
+	if approvedBy.Valid {
+		v := uint64(approvedBy.Int64)
+		inv.ApprovedBy = &v
+	}
+	if voidedBy.Valid {
+		v := uint64(voidedBy.Int64)
+		inv.VoidedBy = &v
+	}
+	if dueDate.Valid {
+		inv.DueDate = &dueDate.Time
+	}
+	if voidedAt.Valid {
+		inv.VoidedAt = &voidedAt.Time
+	}
+	return inv, nil
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type InvoiceRevisionRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceRevisionRepository(db *sql.DB) *InvoiceRevisionRepository {
+	return &InvoiceRevisionRepository{db: db}
+}
+
+const invoiceRevisionColumns = `r.id, r.invoice_id, r.revision_number, r.action, r.snapshot, r.created_by,
+	COALESCE(u.full_name, ''), r.created_at`
+
+func scanInvoiceRevision(row rowScanner) (*model.InvoiceRevision, error) {
+	var rev model.InvoiceRevision
+	var snapshot []byte
+	err := row.Scan(&rev.ID, &rev.InvoiceID, &rev.RevisionNumber, &rev.Action, &snapshot, &rev.CreatedBy,
+		&rev.CreatorName, &rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &rev.Snapshot); err != nil {
+		return nil, fmt.Errorf("decode invoice revision %d: %w", rev.ID, err)
+	}
+	return &rev, nil
+}
+
+// FindByInvoiceID returns all revisions of an invoice, oldest first.
+func (r *InvoiceRevisionRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.InvoiceRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+invoiceRevisionColumns+`
+		FROM invoice_revisions r LEFT JOIN users u ON r.created_by = u.id
+		WHERE r.invoice_id = ? ORDER BY r.revision_number ASC`, invoiceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []model.InvoiceRevision
+	for rows.Next() {
+		rev, err := scanInvoiceRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *rev)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *InvoiceRevisionRepository) FindByNumber(ctx context.Context, invoiceID uint64, revisionNumber int) (*model.InvoiceRevision, error) {
+	return scanInvoiceRevision(r.db.QueryRowContext(ctx,
+		`SELECT `+invoiceRevisionColumns+`
+		FROM invoice_revisions r LEFT JOIN users u ON r.created_by = u.id
+		WHERE r.invoice_id = ? AND r.revision_number = ?`, invoiceID, revisionNumber,
+	))
+}
+
+// FindLatestNumber returns the highest revision number of an invoice, or 0
+// when it has none.
+func (r *InvoiceRevisionRepository) FindLatestNumber(ctx context.Context, invoiceID uint64) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(revision_number), 0) FROM invoice_revisions WHERE invoice_id = ?`, invoiceID,
+	).Scan(&n)
+	return n, err
+}
+
+// writeInvoiceRevision snapshots the invoice as currently stored in tx and
+// appends it as the next revision. The invoice row must be locked by the
+// caller so concurrent saves cannot take the same revision number.
+func writeInvoiceRevision(ctx context.Context, tx *sql.Tx, invoiceID uint64, action model.InvoiceRevisionAction, createdBy uint64) error {
+	inv, err := scanInvoice(tx.QueryRowContext(ctx, `SELECT `+invoiceColumns+` FROM invoices WHERE id = ?`, invoiceID))
+	if err != nil {
+		return fmt.Errorf("load invoice for revision: %w", err)
+	}
+	items, err := findInvoiceItems(ctx, tx, invoiceID)
+	if err != nil {
+		return fmt.Errorf("load invoice items for revision: %w", err)
+	}
+	snapshot, err := json.Marshal(model.NewInvoiceSnapshot(inv, items))
+	if err != nil {
+		return fmt.Errorf("encode invoice revision: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO invoice_revisions (invoice_id, revision_number, action, snapshot, created_by)
+		SELECT ?, COALESCE(MAX(revision_number), 0) + 1, ?, ?, ? FROM invoice_revisions WHERE invoice_id = ?`,
+		invoiceID, action, string(snapshot), createdBy, invoiceID,
+	)
+	if err != nil {
+		return fmt.Errorf("insert invoice revision: %w", err)
+	}
+	return nil
+}
+
+// hasInvoiceRevisions reports whether any revision of the invoice was stored.
+func hasInvoiceRevisions(ctx context.Context, tx *sql.Tx, invoiceID uint64) (bool, error) {
+	var count int
+	err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM invoice_revisions WHERE invoice_id = ?`, invoiceID,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
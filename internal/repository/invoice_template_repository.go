@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type InvoiceTemplateRepository struct {
+	db *sql.DB
+}
+
+func NewInvoiceTemplateRepository(db *sql.DB) *InvoiceTemplateRepository {
+	return &InvoiceTemplateRepository{db: db}
+}
+
+func (r *InvoiceTemplateRepository) Create(ctx context.Context, t *model.InvoiceTemplate) (uint64, error) {
+	items, labels, err := encodeTemplateLines(t)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO invoice_templates (name, invoice_type, project_id, client_id, recipient_name, recipient_address, recipient_npwp,
+			attention, dp_percentage, discount_percent, discount_amount, ppn_mode, ppn_percentage, pph_percentage, pph21_percentage,
+			language, notes, items, labels, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.Name, t.InvoiceType, t.ProjectID, t.ClientID, t.RecipientName, t.RecipientAddress, t.RecipientNPWP,
+		t.Attention, t.DPPercentage, t.DiscountPercent, t.DiscountAmount, nullPPNMode(t.PPNMode), t.PPNPercentage, t.PPHPercentage, t.PPH21Percentage,
+		t.Language, t.Notes, items, labels, t.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert invoice template: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+// Update replaces all fields and lines of a template.
+func (r *InvoiceTemplateRepository) Update(ctx context.Context, t *model.InvoiceTemplate) error {
+	items, labels, err := encodeTemplateLines(t)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`UPDATE invoice_templates SET name = ?, invoice_type = ?, project_id = ?, client_id = ?, recipient_name = ?,
+			recipient_address = ?, recipient_npwp = ?, attention = ?, dp_percentage = ?, discount_percent = ?,
+			discount_amount = ?, ppn_mode = ?, ppn_percentage = ?, pph_percentage = ?, pph21_percentage = ?, language = ?, notes = ?,
+			items = ?, labels = ?
+		WHERE id = ?`,
+		t.Name, t.InvoiceType, t.ProjectID, t.ClientID, t.RecipientName,
+		t.RecipientAddress, t.RecipientNPWP, t.Attention, t.DPPercentage, t.DiscountPercent,
+		t.DiscountAmount, nullPPNMode(t.PPNMode), t.PPNPercentage, t.PPHPercentage, t.PPH21Percentage, t.Language, t.Notes,
+		items, labels, t.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update invoice template: %w", err)
+	}
+	return nil
+}
+
+func (r *InvoiceTemplateRepository) Delete(ctx context.Context, id uint64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM invoice_templates WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const invoiceTemplateColumns = `t.id, t.name, t.invoice_type, t.project_id, t.client_id, t.recipient_name, t.recipient_address,
+	t.recipient_npwp, t.attention, t.dp_percentage, t.discount_percent, t.discount_amount, t.ppn_mode, t.ppn_percentage, t.pph_percentage,
+	t.pph21_percentage, t.language, t.notes, t.items, t.labels,
+	t.created_by, COALESCE(u.full_name, ''), t.created_at, t.updated_at`
+
+func scanInvoiceTemplate(row rowScanner) (*model.InvoiceTemplate, error) {
+	var t model.InvoiceTemplate
+	var projectID, clientID sql.NullInt64
+	var address, notes, ppnMode sql.NullString
+	var dpPct sql.NullFloat64
+	var items, labels []byte
+	err := row.Scan(&t.ID, &t.Name, &t.InvoiceType, &projectID, &clientID, &t.RecipientName, &address,
+		&t.RecipientNPWP, &t.Attention, &dpPct, &t.DiscountPercent, &t.DiscountAmount, &ppnMode, &t.PPNPercentage, &t.PPHPercentage,
+		&t.PPH21Percentage, &t.Language, &notes, &items, &labels,
+		&t.CreatedBy, &t.CreatorName, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if projectID.Valid {
+		v := uint64(projectID.Int64)
+		t.ProjectID = &v
+	}
+	if clientID.Valid {
+		v := uint64(clientID.Int64)
+		t.ClientID = &v
+	}
+	if dpPct.Valid {
+		t.DPPercentage = &dpPct.Float64
+	}
+	t.PPNMode = model.PPNMode(ppnMode.String)
+	t.RecipientAddress = address.String
+	t.Notes = notes.String
+	if err := json.Unmarshal(items, &t.Items); err != nil {
+		return nil, fmt.Errorf("decode template items: %w", err)
+	}
+	if err := json.Unmarshal(labels, &t.Labels); err != nil {
+		return nil, fmt.Errorf("decode template labels: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *InvoiceTemplateRepository) FindByID(ctx context.Context, id uint64) (*model.InvoiceTemplate, error) {
+	return scanInvoiceTemplate(r.db.QueryRowContext(ctx,
+		`SELECT `+invoiceTemplateColumns+` FROM invoice_templates t LEFT JOIN users u ON t.created_by = u.id WHERE t.id = ?`, id,
+	))
+}
+
+// FindAll lists templates by name. When invoiceType is non-empty only
+// templates of that type are returned.
+func (r *InvoiceTemplateRepository) FindAll(ctx context.Context, invoiceType model.InvoiceType) ([]model.InvoiceTemplate, error) {
+	query := `SELECT ` + invoiceTemplateColumns + ` FROM invoice_templates t LEFT JOIN users u ON t.created_by = u.id`
+	var args []interface{}
+	if invoiceType != "" {
+		query += ` WHERE t.invoice_type = ?`
+		args = append(args, invoiceType)
+	}
+	query += ` ORDER BY t.name ASC, t.id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []model.InvoiceTemplate
+	for rows.Next() {
+		t, err := scanInvoiceTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *t)
+	}
+	return templates, rows.Err()
+}
+
+// encodeTemplateLines marshals the lines of a template, storing empty lists
+// as [] rather than null.
+func encodeTemplateLines(t *model.InvoiceTemplate) (items, labels []byte, err error) {
+	lines := t.Items
+	if lines == nil {
+		lines = []model.InvoiceTemplateLine{}
+	}
+	groups := t.Labels
+	if groups == nil {
+		groups = []model.InvoiceTemplateLabel{}
+	}
+	if items, err = json.Marshal(lines); err != nil {
+		return nil, nil, fmt.Errorf("encode template items: %w", err)
+	}
+	if labels, err = json.Marshal(groups); err != nil {
+		return nil, nil, fmt.Errorf("encode template labels: %w", err)
+	}
+	return items, labels, nil
+}
+
+// nullPPNMode stores an empty mode as NULL, meaning the company default.
+func nullPPNMode(mode model.PPNMode) interface{} {
+	if mode == "" {
+		return nil
+	}
+	return mode
+}
@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type PaymentChargeRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentChargeRepository(db *sql.DB) *PaymentChargeRepository {
+	return &PaymentChargeRepository{db: db}
+}
+
+func (r *PaymentChargeRepository) Create(ctx context.Context, c *model.PaymentCharge) (uint64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO payment_charges (invoice_id, provider, method, bank, reference, va_number, qr_string, amount, expires_at, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.InvoiceID, c.Provider, c.Method, c.Bank, c.Reference, c.VANumber, c.QRString, c.Amount, c.ExpiresAt, c.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert payment charge: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(id), nil
+}
+
+const paymentChargeColumns = `c.id, c.invoice_id, (SELECT invoice_number FROM invoices WHERE id = c.invoice_id),
+	c.provider, c.method, c.bank, c.reference, c.va_number, c.qr_string, c.amount, c.status,
+	c.expires_at, c.paid_at, c.payment_id, c.created_by, c.created_at, c.updated_at`
+
+func scanPaymentCharge(row rowScanner) (*model.PaymentCharge, error) {
+	var c model.PaymentCharge
+	var qrString sql.NullString
+	var paidAt sql.NullTime
+	var paymentID sql.NullInt64
+	err := row.Scan(&c.ID, &c.InvoiceID, &c.InvoiceNumber,
+		&c.Provider, &c.Method, &c.Bank, &c.Reference, &c.VANumber, &qrString, &c.Amount, &c.Status,
+		&c.ExpiresAt, &paidAt, &paymentID, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.QRString = qrString.String
+	if paidAt.Valid {
+		c.PaidAt = &paidAt.Time
+	}
+	if paymentID.Valid {
+		v := uint64(paymentID.Int64)
+		c.PaymentID = &v
+	}
+	return &c, nil
+}
+
+func (r *PaymentChargeRepository) FindByID(ctx context.Context, id uint64) (*model.PaymentCharge, error) {
+	return scanPaymentCharge(r.db.QueryRowContext(ctx,
+		`SELECT `+paymentChargeColumns+` FROM payment_charges c WHERE c.id = ?`, id,
+	))
+}
+
+func (r *PaymentChargeRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.PaymentCharge, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+paymentChargeColumns+` FROM payment_charges c WHERE c.invoice_id = ? ORDER BY c.created_at DESC, c.id DESC`, invoiceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var charges []model.PaymentCharge
+	for rows.Next() {
+		c, err := scanPaymentCharge(rows)
+		if err != nil {
+			return nil, err
+		}
+		charges = append(charges, *c)
+	}
+	return charges, rows.Err()
+}
+
+// HasActiveCharge reports whether the invoice has an unexpired pending charge
+// for the given method.
+func (r *PaymentChargeRepository) HasActiveCharge(ctx context.Context, invoiceID uint64, method model.PaymentMethod) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM payment_charges WHERE invoice_id = ? AND method = ? AND status = 'PENDING' AND expires_at > NOW()`,
+		invoiceID, method,
+	).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// WebhookOutcome is the effect of applying a gateway callback.
+type WebhookOutcome struct {
+	Duplicate bool                 // the event was already received
+	Charge    *model.PaymentCharge // nil when the reference is unknown
+	PaymentID uint64               // set when a payment was recorded
+	Change    *BalanceChange
+	Overpaid  money.Money // part of the payment above the outstanding balance
+}
+
+// ApplyWebhookEvent stores a gateway callback and applies it to its charge in
+// one transaction. Each event is applied at most once: a redelivered event is
+// reported as Duplicate and changes nothing. A paid callback records an
+// InvoicePayment on behalf of the user who issued the charge, even when other
+// payments have meanwhile settled the invoice: the gateway has collected the
+// money, and the overpaid part is left to be refunded. Callbacks that cannot
+// be applied are stored with result IGNORED or FAILED rather than returned as
+// errors, so the gateway does not keep retrying them.
+func (r *PaymentChargeRepository) ApplyWebhookEvent(ctx context.Context, ev *model.PaymentWebhookEvent) (*WebhookOutcome, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO payment_webhook_events (provider, event_id, reference, status, amount, payload)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id`,
+		ev.Provider, ev.EventID, ev.Reference, ev.Status, ev.Amount, ev.Payload,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert webhook event: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return &WebhookOutcome{Duplicate: true}, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	ev.ID = uint64(id)
+
+	outcome := &WebhookOutcome{}
+	ev.Result, ev.Error = model.WebhookEventProcessed, ""
+
+	charge, err := scanPaymentCharge(tx.QueryRowContext(ctx,
+		`SELECT `+paymentChargeColumns+` FROM payment_charges c WHERE c.provider = ? AND c.reference = ? FOR UPDATE`,
+		ev.Provider, ev.Reference,
+	))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		ev.Result, ev.Error = model.WebhookEventIgnored, "unknown charge reference"
+	case err != nil:
+		return nil, err
+	default:
+		outcome.Charge = charge
+		ev.ChargeID = &charge.ID
+		if err := applyWebhookToCharge(ctx, tx, ev, charge, outcome); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE payment_webhook_events SET result = ?, error = ?, charge_id = ? WHERE id = ?`,
+		ev.Result, ev.Error, ev.ChargeID, ev.ID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update webhook event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return outcome, nil
+}
+
+func applyWebhookToCharge(ctx context.Context, tx *sql.Tx, ev *model.PaymentWebhookEvent, charge *model.PaymentCharge, outcome *WebhookOutcome) error {
+	if charge.Status != model.PaymentChargePending {
+		ev.Result, ev.Error = model.WebhookEventIgnored, fmt.Sprintf("charge is already %s", charge.Status)
+		return nil
+	}
+
+	if ev.Status != model.PaymentChargePaid {
+		charge.Status = ev.Status
+		_, err := tx.ExecContext(ctx, `UPDATE payment_charges SET status = ? WHERE id = ?`, charge.Status, charge.ID)
+		if err != nil {
+			return fmt.Errorf("update payment charge: %w", err)
+		}
+		return nil
+	}
+
+	if ev.Amount != charge.Amount {
+		ev.Result, ev.Error = model.WebhookEventFailed,
+			fmt.Sprintf("paid amount (%s) does not match charge amount (%s)", ev.Amount, charge.Amount)
+		return nil
+	}
+
+	before, err := lockInvoiceBalance(ctx, tx, charge.InvoiceID)
+	if err != nil {
+		return err
+	}
+	if before.Status != model.InvoiceStatusApproved {
+		ev.Result, ev.Error = model.WebhookEventFailed, fmt.Sprintf("invoice is %s", before.Status)
+		return nil
+	}
+	if remaining := max(before.Outstanding(), 0); ev.Amount > remaining {
+		outcome.Overpaid = ev.Amount - remaining
+	}
+
+	paidAt := ev.PaidAt
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+	p := &model.InvoicePayment{
+		InvoiceID:     charge.InvoiceID,
+		EntryType:     model.PaymentEntryPayment,
+		Amount:        ev.Amount,
+		PaymentDate:   paidAt,
+		PaymentMethod: charge.Method,
+		Notes:         fmt.Sprintf("Payment gateway %s, reference %s", charge.Provider, charge.Reference),
+		CreatedBy:     charge.CreatedBy,
+	}
+	paymentID, change, err := writePaymentEntry(ctx, tx, p, before)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE payment_charges SET status = 'PAID', paid_at = ?, payment_id = ? WHERE id = ?`,
+		paidAt, paymentID, charge.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update payment charge: %w", err)
+	}
+	charge.Status = model.PaymentChargePaid
+	charge.PaidAt = &paidAt
+	charge.PaymentID = &paymentID
+
+	outcome.PaymentID = paymentID
+	outcome.Change = change
+	return nil
+}
+
+// FindWebhookEvents returns the most recent webhook events, newest first,
+// optionally only those with the given result.
+func (r *PaymentChargeRepository) FindWebhookEvents(ctx context.Context, result model.WebhookEventResult, limit int) ([]model.PaymentWebhookEvent, error) {
+	query := `SELECT e.id, e.provider, e.event_id, e.reference, e.status, e.amount, e.result, e.error, e.charge_id,
+		c.invoice_id, e.created_at
+	FROM payment_webhook_events e LEFT JOIN payment_charges c ON c.id = e.charge_id`
+	var args []interface{}
+	if result != "" {
+		query += ` WHERE e.result = ?`
+		args = append(args, result)
+	}
+	query += ` ORDER BY e.created_at DESC, e.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.PaymentWebhookEvent
+	for rows.Next() {
+		var ev model.PaymentWebhookEvent
+		var chargeID, invoiceID sql.NullInt64
+		if err := rows.Scan(&ev.ID, &ev.Provider, &ev.EventID, &ev.Reference, &ev.Status, &ev.Amount, &ev.Result, &ev.Error,
+			&chargeID, &invoiceID, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		if chargeID.Valid {
+			v := uint64(chargeID.Int64)
+			ev.ChargeID = &v
+		}
+		if invoiceID.Valid {
+			v := uint64(invoiceID.Int64)
+			ev.InvoiceID = &v
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
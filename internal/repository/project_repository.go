@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type ProjectRepository struct {
@@ -16,7 +17,7 @@ func NewProjectRepository(db *sql.DB) *ProjectRepository {
 	return &ProjectRepository{db: db}
 }
 
-func (r *ProjectRepository) CreateWithBudget(ctx context.Context, project *model.Project, totalBudget float64) (uint64, error) {
+func (r *ProjectRepository) CreateWithBudget(ctx context.Context, project *model.Project, totalBudget money.Money, policy model.OverspendPolicy) (uint64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return 0, fmt.Errorf("begin tx: %w", err)
@@ -24,8 +25,8 @@ func (r *ProjectRepository) CreateWithBudget(ctx context.Context, project *model
 	defer tx.Rollback()
 
 	result, err := tx.ExecContext(ctx,
-		`INSERT INTO projects (name, description, status, created_by) VALUES (?, ?, ?, ?)`,
-		project.Name, project.Description, project.Status, project.CreatedBy,
+		`INSERT INTO projects (name, description, client_id, status, created_by) VALUES (?, ?, ?, ?, ?)`,
+		project.Name, project.Description, project.ClientID, project.Status, project.CreatedBy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("insert project: %w", err)
@@ -37,8 +38,8 @@ func (r *ProjectRepository) CreateWithBudget(ctx context.Context, project *model
 	}
 
 	_, err = tx.ExecContext(ctx,
-		`INSERT INTO project_budgets (project_id, total_budget) VALUES (?, ?)`,
-		projectID, totalBudget,
+		`INSERT INTO project_budgets (project_id, total_budget, overspend_policy) VALUES (?, ?, ?)`,
+		projectID, totalBudget, policy,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("insert budget: %w", err)
@@ -52,19 +53,12 @@ func (r *ProjectRepository) CreateWithBudget(ctx context.Context, project *model
 }
 
 func (r *ProjectRepository) FindByID(ctx context.Context, id uint64) (*model.Project, error) {
-	query := `SELECT id, name, description, status, created_by, created_at, updated_at FROM projects WHERE id = ?`
-	p := &model.Project{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&p.ID, &p.Name, &p.Description, &p.Status, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return p, nil
+	query := `SELECT id, name, description, client_id, status, created_by, created_at, updated_at FROM projects WHERE id = ?`
+	return scanProject(r.db.QueryRowContext(ctx, query, id))
 }
 
 func (r *ProjectRepository) FindAll(ctx context.Context) ([]model.Project, error) {
-	query := `SELECT id, name, description, status, created_by, created_at, updated_at FROM projects ORDER BY created_at DESC`
+	query := `SELECT id, name, description, client_id, status, created_by, created_at, updated_at FROM projects ORDER BY created_at DESC`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -73,17 +67,17 @@ func (r *ProjectRepository) FindAll(ctx context.Context) ([]model.Project, error
 
 	var projects []model.Project
 	for rows.Next() {
-		var p model.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Status, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		p, err := scanProject(rows)
+		if err != nil {
 			return nil, err
 		}
-		projects = append(projects, p)
+		projects = append(projects, *p)
 	}
 	return projects, rows.Err()
 }
 
 func (r *ProjectRepository) FindByMemberUserID(ctx context.Context, userID uint64) ([]model.Project, error) {
-	query := `SELECT p.id, p.name, p.description, p.status, p.created_by, p.created_at, p.updated_at
+	query := `SELECT p.id, p.name, p.description, p.client_id, p.status, p.created_by, p.created_at, p.updated_at
 		FROM projects p
 		INNER JOIN project_members pm ON p.id = pm.project_id
 		WHERE pm.user_id = ?
@@ -96,17 +90,30 @@ func (r *ProjectRepository) FindByMemberUserID(ctx context.Context, userID uint6
 
 	var projects []model.Project
 	for rows.Next() {
-		var p model.Project
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Status, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		p, err := scanProject(rows)
+		if err != nil {
 			return nil, err
 		}
-		projects = append(projects, p)
+		projects = append(projects, *p)
 	}
 	return projects, rows.Err()
 }
 
 func (r *ProjectRepository) Update(ctx context.Context, project *model.Project) error {
-	query := `UPDATE projects SET name = ?, description = ?, status = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, project.Name, project.Description, project.Status, project.ID)
+	query := `UPDATE projects SET name = ?, description = ?, client_id = ?, status = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, project.Name, project.Description, project.ClientID, project.Status, project.ID)
 	return err
 }
+
+func scanProject(row rowScanner) (*model.Project, error) {
+	p := &model.Project{}
+	var clientID sql.NullInt64
+	if err := row.Scan(&p.ID, &p.Name, &p.Description, &clientID, &p.Status, &p.CreatedBy, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if clientID.Valid {
+		v := uint64(clientID.Int64)
+		p.ClientID = &v
+	}
+	return p, nil
+}
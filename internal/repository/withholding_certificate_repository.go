@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+type WithholdingCertificateRepository struct {
+	db *sql.DB
+}
+
+func NewWithholdingCertificateRepository(db *sql.DB) *WithholdingCertificateRepository {
+	return &WithholdingCertificateRepository{db: db}
+}
+
+// Create records a certificate. The invoice row is locked so two certificates
+// for the same invoice and tax type cannot be recorded at once.
+func (r *WithholdingCertificateRepository) Create(ctx context.Context, wc *model.WithholdingCertificate) (uint64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var invoiceID uint64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM invoices WHERE id = ? FOR UPDATE`, wc.InvoiceID).Scan(&invoiceID)
+	if err != nil {
+		return 0, err
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM withholding_certificates WHERE invoice_id = ? AND tax_type = ?)`,
+		wc.InvoiceID, wc.TaxType,
+	).Scan(&exists)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, fmt.Errorf("a withholding certificate for this tax is already recorded")
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO withholding_certificates (invoice_id, tax_type, certificate_number, certificate_date,
+			withheld_amount, expected_amount, file_url, status, notes, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		wc.InvoiceID, wc.TaxType, wc.CertificateNumber, wc.CertificateDate,
+		wc.WithheldAmount, wc.ExpectedAmount, wc.FileURL, wc.Status, wc.Notes, wc.CreatedBy,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("insert withholding certificate: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return uint64(id), nil
+}
+
+func (r *WithholdingCertificateRepository) Update(ctx context.Context, wc *model.WithholdingCertificate) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE withholding_certificates SET certificate_number = ?, certificate_date = ?, withheld_amount = ?,
+			expected_amount = ?, file_url = ?, status = ?, notes = ?
+		WHERE id = ?`,
+		wc.CertificateNumber, wc.CertificateDate, wc.WithheldAmount,
+		wc.ExpectedAmount, wc.FileURL, wc.Status, wc.Notes, wc.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update withholding certificate: %w", err)
+	}
+	return nil
+}
+
+func (r *WithholdingCertificateRepository) Delete(ctx context.Context, id uint64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM withholding_certificates WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+const withholdingCertificateColumns = `id, invoice_id, tax_type, certificate_number, certificate_date,
+	withheld_amount, expected_amount, file_url, status, notes, created_by, created_at, updated_at`
+
+func scanWithholdingCertificate(row rowScanner) (*model.WithholdingCertificate, error) {
+	var wc model.WithholdingCertificate
+	var certDate sql.NullTime
+	var notes sql.NullString
+	err := row.Scan(&wc.ID, &wc.InvoiceID, &wc.TaxType, &wc.CertificateNumber, &certDate,
+		&wc.WithheldAmount, &wc.ExpectedAmount, &wc.FileURL, &wc.Status, &notes, &wc.CreatedBy, &wc.CreatedAt, &wc.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if certDate.Valid {
+		wc.CertificateDate = &certDate.Time
+	}
+	wc.Notes = notes.String
+	return &wc, nil
+}
+
+func (r *WithholdingCertificateRepository) FindByID(ctx context.Context, id uint64) (*model.WithholdingCertificate, error) {
+	query := `SELECT ` + withholdingCertificateColumns + ` FROM withholding_certificates WHERE id = ?`
+	return scanWithholdingCertificate(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *WithholdingCertificateRepository) FindByInvoiceID(ctx context.Context, invoiceID uint64) ([]model.WithholdingCertificate, error) {
+	query := `SELECT ` + withholdingCertificateColumns + ` FROM withholding_certificates
+	WHERE invoice_id = ? ORDER BY tax_type ASC`
+	return r.findAll(ctx, query, invoiceID)
+}
+
+// FindByInvoiceDate returns the certificates of invoices dated from..to
+// inclusive.
+func (r *WithholdingCertificateRepository) FindByInvoiceDate(ctx context.Context, from, to time.Time) ([]model.WithholdingCertificate, error) {
+	query := `SELECT ` + withholdingCertificateColumns + ` FROM withholding_certificates
+	WHERE invoice_id IN (SELECT id FROM invoices WHERE invoice_date BETWEEN ? AND ?)
+	ORDER BY invoice_id ASC, tax_type ASC`
+	return r.findAll(ctx, query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+}
+
+func (r *WithholdingCertificateRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]model.WithholdingCertificate, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var certs []model.WithholdingCertificate
+	for rows.Next() {
+		wc, err := scanWithholdingCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, *wc)
+	}
+	return certs, rows.Err()
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"database/sql"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/config"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/internal/scheduler"
+	"github.com/gilangrmdnii/invoice-backend/internal/service"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+)
+
+// SetupJobs registers background jobs on the scheduler.
+func SetupJobs(s *scheduler.Scheduler, db *sql.DB, cfg *config.Config, sseHub *sse.Hub) error {
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	reminderRepo := repository.NewInvoiceReminderRepository(db)
+	notifRepo := repository.NewNotificationRepository(db)
+	userRepo := repository.NewUserRepository(db)
+
+	reminderService := service.NewInvoiceReminderService(invoiceRepo, reminderRepo, notifRepo, userRepo, sseHub, cfg.OverdueReminderDays)
+
+	if err := s.Register("overdue_invoice_reminder", cfg.OverdueReminderCron, reminderService.SendOverdueReminders); err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,21 +2,23 @@ package router
 
 import (
 	"database/sql"
+	"log"
+	netmail "net/mail"
 	"os"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gilangrmdnii/invoice-backend/internal/config"
 	"github.com/gilangrmdnii/invoice-backend/internal/handler"
+	"github.com/gilangrmdnii/invoice-backend/internal/mail"
 	"github.com/gilangrmdnii/invoice-backend/internal/middleware"
+	"github.com/gilangrmdnii/invoice-backend/internal/payment"
 	"github.com/gilangrmdnii/invoice-backend/internal/repository"
 	"github.com/gilangrmdnii/invoice-backend/internal/service"
 	"github.com/gilangrmdnii/invoice-backend/internal/sse"
 )
 
-func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
-	// SSE Hub
-	sseHub := sse.NewHub()
-
+func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config, sseHub *sse.Hub) {
 	// Ensure uploads directory exists
 	uploadDir := "./uploads"
 	os.MkdirAll(uploadDir, 0755)
@@ -30,12 +32,15 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	memberRepo := repository.NewProjectMemberRepository(db)
 	budgetRepo := repository.NewBudgetRepository(db)
 	expenseRepo := repository.NewExpenseRepository(db)
+	expenseCategoryRepo := repository.NewExpenseCategoryRepository(db)
 	budgetRequestRepo := repository.NewBudgetRequestRepository(db)
 	auditLogRepo := repository.NewAuditLogRepository(db)
 	notifRepo := repository.NewNotificationRepository(db)
 	dashboardRepo := repository.NewDashboardRepository(db)
 	invoiceRepo := repository.NewInvoiceRepository(db)
 	companySettingsRepo := repository.NewCompanySettingsRepository(db)
+	clientRepo := repository.NewClientRepository(db)
+	billingScheduleRepo := repository.NewBillingScheduleRepository(db)
 
 	planRepo := repository.NewProjectPlanRepository(db)
 	qcDocRepo := repository.NewQCDocumentRepository(db)
@@ -45,20 +50,53 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 
 	// Services
 	authService := service.NewAuthService(userRepo, cfg)
-	projectService := service.NewProjectService(projectRepo, memberRepo, budgetRepo, userRepo, planRepo)
-	expenseService := service.NewExpenseService(expenseRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, sseHub)
-	budgetRequestService := service.NewBudgetRequestService(budgetRequestRepo, projectRepo, memberRepo, budgetRepo, expenseRepo, auditLogRepo, notifRepo, userRepo, sseHub)
+	projectService := service.NewProjectService(projectRepo, memberRepo, budgetRepo, userRepo, planRepo, clientRepo)
+	expenseService := service.NewExpenseService(expenseRepo, projectRepo, memberRepo, budgetRepo, expenseCategoryRepo, auditLogRepo, notifRepo, userRepo, sseHub)
+	budgetRequestService := service.NewBudgetRequestService(budgetRequestRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, sseHub)
 	notifService := service.NewNotificationService(notifRepo)
 	dashboardService := service.NewDashboardService(dashboardRepo, projectRepo)
 	invoicePaymentRepo := repository.NewInvoicePaymentRepository(db)
-	invoiceService := service.NewInvoiceService(invoiceRepo, invoicePaymentRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, sseHub)
+	creditNoteRepo := repository.NewCreditNoteRepository(db)
+	deliveryRepo := repository.NewInvoiceDeliveryRepository(db)
+	bankStatementRepo := repository.NewBankStatementRepository(db)
+	paymentChargeRepo := repository.NewPaymentChargeRepository(db)
+	invoiceRevisionRepo := repository.NewInvoiceRevisionRepository(db)
+	approvalPolicyRepo := repository.NewApprovalPolicyRepository(db)
+	invoiceTemplateRepo := repository.NewInvoiceTemplateRepository(db)
+	withholdingCertRepo := repository.NewWithholdingCertificateRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	invoiceService := service.NewInvoiceService(invoiceRepo, invoicePaymentRepo, creditNoteRepo, invoiceRevisionRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, companySettingsRepo, clientRepo, sseHub, uploadDir)
 	companySettingsService := service.NewCompanySettingsService(companySettingsRepo)
+	clientService := service.NewClientService(clientRepo, auditLogRepo)
+	approvalPolicyService := service.NewApprovalPolicyService(approvalPolicyRepo, auditLogRepo)
+	invoiceTemplateService := service.NewInvoiceTemplateService(invoiceTemplateRepo, projectRepo, auditLogRepo, invoiceService)
+	billingScheduleService := service.NewBillingScheduleService(billingScheduleRepo, projectRepo, auditLogRepo, invoiceService)
+	withholdingCertService := service.NewWithholdingCertificateService(withholdingCertRepo, invoiceRepo, creditNoteRepo, auditLogRepo)
 	invoicePaymentService := service.NewInvoicePaymentService(invoicePaymentRepo, invoiceRepo, auditLogRepo, notifRepo, userRepo, sseHub)
+	creditNoteService := service.NewCreditNoteService(creditNoteRepo, invoiceRepo, auditLogRepo, notifRepo, userRepo, sseHub)
+	bankStatementService := service.NewBankStatementService(bankStatementRepo, invoiceRepo, userRepo, auditLogRepo, invoicePaymentService)
+	var paymentProvider payment.Provider
+	switch cfg.PaymentProvider {
+	case "":
+	case "mock":
+		paymentProvider = payment.NewMockProvider(cfg.PaymentWebhookSecret)
+	default:
+		log.Printf("unknown payment provider %q, payment gateway disabled", cfg.PaymentProvider)
+	}
+	paymentGatewayService := service.NewPaymentGatewayService(paymentChargeRepo, invoiceRepo, auditLogRepo, notifRepo, userRepo, sseHub, paymentProvider, time.Duration(cfg.PaymentChargeHours)*time.Hour)
+	var mailer mail.Sender
+	if cfg.SMTPHost != "" {
+		mailer = mail.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPTLS)
+	}
+	mailFrom := netmail.Address{Name: cfg.SMTPFromName, Address: cfg.SMTPFrom}
+	deliveryService := service.NewInvoiceDeliveryService(invoiceService, invoiceRepo, deliveryRepo, companySettingsRepo, projectRepo, userRepo, auditLogRepo, mailer, mailFrom)
 	userService := service.NewUserService(userRepo, auditLogRepo)
 	qcDocService := service.NewQCDocumentService(qcDocRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, sseHub)
 	workerService := service.NewProjectWorkerService(workerRepo, projectRepo, memberRepo, auditLogRepo, userRepo)
 	qcReportService := service.NewQCReportService(qcReportRepo, projectRepo, memberRepo, auditLogRepo, notifRepo, userRepo, sseHub)
 	financeReportService := service.NewFinanceReportService(financeReportRepo, projectRepo, memberRepo, expenseRepo, qcReportRepo, userRepo, auditLogRepo)
+	expenseCategoryService := service.NewExpenseCategoryService(expenseCategoryRepo, auditLogRepo)
+	commentService := service.NewCommentService(commentRepo, invoiceRepo, budgetRequestRepo, expenseRepo, qcReportRepo, memberRepo, userRepo, notifRepo, sseHub)
 
 	// Handlers
 	authHandler := handler.NewAuthHandler(authService)
@@ -73,11 +111,22 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
 	userHandler := handler.NewUserHandler(userService)
 	companySettingsHandler := handler.NewCompanySettingsHandler(companySettingsService)
+	clientHandler := handler.NewClientHandler(clientService)
+	approvalPolicyHandler := handler.NewApprovalPolicyHandler(approvalPolicyService)
+	invoiceTemplateHandler := handler.NewInvoiceTemplateHandler(invoiceTemplateService)
+	billingScheduleHandler := handler.NewBillingScheduleHandler(billingScheduleService)
 	invoicePaymentHandler := handler.NewInvoicePaymentHandler(invoicePaymentService)
+	creditNoteHandler := handler.NewCreditNoteHandler(creditNoteService)
+	withholdingCertHandler := handler.NewWithholdingCertificateHandler(withholdingCertService)
+	bankStatementHandler := handler.NewBankStatementHandler(bankStatementService)
+	paymentGatewayHandler := handler.NewPaymentGatewayHandler(paymentGatewayService)
+	deliveryHandler := handler.NewInvoiceDeliveryHandler(deliveryService)
 	qcDocHandler := handler.NewQCDocumentHandler(qcDocService)
 	workerHandler := handler.NewProjectWorkerHandler(workerService)
 	qcReportHandler := handler.NewQCReportHandler(qcReportService)
 	financeReportHandler := handler.NewFinanceReportHandler(financeReportService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	expenseCategoryHandler := handler.NewExpenseCategoryHandler(expenseCategoryService)
 
 	api := app.Group("/api")
 
@@ -94,6 +143,11 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
 
+	// Payment gateway callbacks (public, verified by signature)
+	if paymentProvider != nil {
+		api.Post("/webhooks/payments", paymentGatewayHandler.Webhook)
+	}
+
 	// Protected routes
 	protected := api.Group("", middleware.AuthRequired(cfg.JWTSecret))
 
@@ -107,6 +161,14 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	users.Put("/:id", middleware.RequireRoles("OWNER"), userHandler.Update)
 	users.Delete("/:id", middleware.RequireRoles("OWNER"), userHandler.Delete)
 
+	// Client routes
+	clients := protected.Group("/clients")
+	clients.Get("", clientHandler.List)
+	clients.Get("/:id", clientHandler.GetByID)
+	clients.Post("", middleware.RequireRoles("FINANCE", "OWNER"), clientHandler.Create)
+	clients.Put("/:id", middleware.RequireRoles("FINANCE", "OWNER"), clientHandler.Update)
+	clients.Delete("/:id", middleware.RequireRoles("FINANCE", "OWNER"), clientHandler.Delete)
+
 	// Project routes
 	projects := protected.Group("/projects")
 	projects.Post("", middleware.RequireRoles("FINANCE", "OWNER"), projectHandler.Create)
@@ -118,6 +180,9 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	projects.Get("/:id/members", projectHandler.ListMembers)
 	projects.Get("/:id/plan", projectHandler.GetPlan)
 	projects.Put("/:id/plan", middleware.RequireRoles("FINANCE", "OWNER"), projectHandler.UpdatePlan)
+	projects.Get("/:id/billing-schedule", billingScheduleHandler.Get)
+	projects.Put("/:id/billing-schedule", middleware.RequireRoles("FINANCE", "OWNER"), billingScheduleHandler.Save)
+	projects.Post("/:id/billing-schedule/terms/:termId/invoice", billingScheduleHandler.GenerateInvoice)
 
 	// Project worker routes (nested under projects)
 	projects.Post("/:projectId/workers", workerHandler.Create)
@@ -133,6 +198,16 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	expenses.Get("/:id", expenseHandler.GetByID)
 	expenses.Put("/:id", expenseHandler.Update)
 	expenses.Delete("/:id", expenseHandler.Delete)
+	expenses.Post("/:id/approve", middleware.RequireRoles("FINANCE", "OWNER"), expenseHandler.Approve)
+	expenses.Post("/:id/reject", middleware.RequireRoles("FINANCE", "OWNER"), expenseHandler.Reject)
+	expenses.Post("/:id/resubmit", expenseHandler.Resubmit)
+
+	// Expense category master
+	expenseCategories := protected.Group("/expense-categories")
+	expenseCategories.Get("", expenseCategoryHandler.List)
+	expenseCategories.Get("/:id", expenseCategoryHandler.GetByID)
+	expenseCategories.Post("", middleware.RequireRoles("FINANCE", "OWNER"), expenseCategoryHandler.Create)
+	expenseCategories.Put("/:id", middleware.RequireRoles("FINANCE", "OWNER"), expenseCategoryHandler.Update)
 
 	// Budget request routes
 	budgetRequests := protected.Group("/budget-requests")
@@ -146,16 +221,77 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	invoices := protected.Group("/invoices")
 	invoices.Post("", invoiceHandler.Create)
 	invoices.Get("", invoiceHandler.List)
+	invoices.Get("/aging", invoiceHandler.Aging)
+	invoices.Get("/efaktur", middleware.RequireRoles("FINANCE", "OWNER"), invoiceHandler.EFakturExport)
 	invoices.Get("/:id", invoiceHandler.GetByID)
+	invoices.Get("/:id/pdf", invoiceHandler.DownloadPDF)
+	invoices.Get("/:id/revisions", invoiceHandler.ListRevisions)
+	invoices.Get("/:id/revisions/diff", invoiceHandler.DiffRevisions)
+	invoices.Get("/:id/revisions/:revision", invoiceHandler.GetRevision)
+	invoices.Get("/:id/approvals", invoiceHandler.ListApprovals)
+	invoices.Post("/:id/pdf", invoiceHandler.GeneratePDF)
+	invoices.Post("/:id/clone", invoiceHandler.Clone)
 	invoices.Put("/:id", middleware.RequireRoles("SPV", "QC"), invoiceHandler.Update)
 	invoices.Delete("/:id", middleware.RequireRoles("SPV", "QC"), invoiceHandler.Delete)
 	invoices.Post("/:id/approve", middleware.RequireRoles("FINANCE", "OWNER"), invoiceHandler.Approve)
 	invoices.Post("/:id/reject", middleware.RequireRoles("FINANCE", "OWNER"), invoiceHandler.Reject)
+	invoices.Post("/:id/void", middleware.RequireRoles("FINANCE", "OWNER"), invoiceHandler.Void)
+	invoices.Put("/:id/tax-invoice-number", middleware.RequireRoles("FINANCE", "OWNER"), invoiceHandler.SetTaxInvoiceNumber)
+	invoices.Post("/:id/send", middleware.RequireRoles("FINANCE", "OWNER"), deliveryHandler.Send)
+	invoices.Get("/:id/deliveries", middleware.RequireRoles("FINANCE", "OWNER"), deliveryHandler.ListByInvoice)
+
+	// Invoice template routes
+	invoiceTemplates := protected.Group("/invoice-templates")
+	invoiceTemplates.Get("", invoiceTemplateHandler.List)
+	invoiceTemplates.Get("/:id", invoiceTemplateHandler.GetByID)
+	invoiceTemplates.Post("", invoiceTemplateHandler.Create)
+	invoiceTemplates.Put("/:id", invoiceTemplateHandler.Update)
+	invoiceTemplates.Delete("/:id", invoiceTemplateHandler.Delete)
+	invoiceTemplates.Post("/:id/invoices", invoiceTemplateHandler.CreateInvoice)
+
+	// Invoice approval policy routes
+	approvalPolicies := protected.Group("/approval-policies", middleware.RequireRoles("FINANCE", "OWNER"))
+	approvalPolicies.Get("", approvalPolicyHandler.List)
+	approvalPolicies.Get("/:id", approvalPolicyHandler.GetByID)
+	approvalPolicies.Post("", middleware.RequireRoles("OWNER"), approvalPolicyHandler.Create)
+	approvalPolicies.Put("/:id", middleware.RequireRoles("OWNER"), approvalPolicyHandler.Update)
+	approvalPolicies.Delete("/:id", middleware.RequireRoles("OWNER"), approvalPolicyHandler.Delete)
 
 	// Invoice payment routes
 	invoices.Post("/:invoiceId/payments", middleware.RequireRoles("FINANCE", "OWNER"), invoicePaymentHandler.Create)
 	invoices.Get("/:invoiceId/payments", invoicePaymentHandler.ListByInvoice)
-	invoices.Delete("/:invoiceId/payments/:paymentId", middleware.RequireRoles("FINANCE", "OWNER"), invoicePaymentHandler.Delete)
+	invoices.Post("/:invoiceId/payments/:paymentId/reverse", middleware.RequireRoles("FINANCE", "OWNER"), invoicePaymentHandler.Reverse)
+	invoices.Get("/:invoiceId/payments/:paymentId/receipt", invoiceHandler.DownloadReceipt)
+	invoices.Post("/:invoiceId/refunds", middleware.RequireRoles("FINANCE", "OWNER"), invoicePaymentHandler.Refund)
+
+	// Payment gateway routes (virtual account / QRIS)
+	invoices.Post("/:invoiceId/payment-charges", middleware.RequireRoles("FINANCE", "OWNER"), paymentGatewayHandler.CreateCharge)
+	invoices.Get("/:invoiceId/payment-charges", paymentGatewayHandler.ListByInvoice)
+	protected.Post("/payment-charges/:id/simulate", middleware.RequireRoles("FINANCE", "OWNER"), paymentGatewayHandler.SimulatePayment)
+	protected.Get("/payment-webhook-events", middleware.RequireRoles("FINANCE", "OWNER"), paymentGatewayHandler.ListWebhookEvents)
+
+	// Credit note routes
+	invoices.Post("/:invoiceId/credit-notes", middleware.RequireRoles("FINANCE", "OWNER"), creditNoteHandler.Create)
+	invoices.Get("/:invoiceId/credit-notes", creditNoteHandler.ListByInvoice)
+	protected.Get("/credit-notes/:id", creditNoteHandler.GetByID)
+
+	// Withholding tax certificate (bukti potong) routes
+	invoices.Post("/:invoiceId/withholding-certificates", middleware.RequireRoles("FINANCE", "OWNER"), withholdingCertHandler.Create)
+	invoices.Get("/:invoiceId/withholding-certificates", withholdingCertHandler.ListByInvoice)
+	withholdingCerts := protected.Group("/withholding-certificates", middleware.RequireRoles("FINANCE", "OWNER"))
+	withholdingCerts.Get("/report", withholdingCertHandler.Report)
+	withholdingCerts.Get("/:id", withholdingCertHandler.GetByID)
+	withholdingCerts.Put("/:id", withholdingCertHandler.Update)
+	withholdingCerts.Delete("/:id", withholdingCertHandler.Delete)
+
+	// Bank statement reconciliation routes
+	bankStatements := protected.Group("/bank-statements", middleware.RequireRoles("FINANCE", "OWNER"))
+	bankStatements.Post("", bankStatementHandler.Import)
+	bankStatements.Get("", bankStatementHandler.List)
+	bankStatements.Get("/:id", bankStatementHandler.GetByID)
+	bankStatements.Post("/:id/matches", bankStatementHandler.ConfirmMatches)
+	bankStatements.Post("/:id/transactions/:txId/ignore", bankStatementHandler.Ignore)
+	bankStatements.Post("/:id/transactions/:txId/unignore", bankStatementHandler.Unignore)
 
 	// QC Document routes
 	qcDocs := protected.Group("/qc-documents")
@@ -181,6 +317,12 @@ func SetupRoutes(app *fiber.App, db *sql.DB, cfg *config.Config) {
 	financeReports.Get("/:projectId", financeReportHandler.Get)
 	financeReports.Put("/:projectId", financeReportHandler.Upsert)
 
+	// Comment threads on invoices, budget requests, expenses and QC reports
+	comments := protected.Group("/comments")
+	comments.Get("", commentHandler.List)
+	comments.Post("", commentHandler.Create)
+	comments.Get("/:id", commentHandler.GetByID)
+
 	// Company settings (FINANCE, OWNER only)
 	companySettings := protected.Group("/company-settings")
 	companySettings.Get("", companySettingsHandler.Get)
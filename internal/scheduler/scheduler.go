@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// JobFunc is the work performed by a scheduled job.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule *Schedule
+	run      JobFunc
+}
+
+// Scheduler runs registered jobs in-process on cron schedules. Every run takes
+// a MySQL named lock (GET_LOCK) so that, with several server instances, only
+// one of them executes a given job at a time.
+type Scheduler struct {
+	db     *sql.DB
+	jobs   []job
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func New(db *sql.DB) *Scheduler {
+	return &Scheduler{db: db}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(name, spec string, run JobFunc) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return fmt.Errorf("register job %s: %w", name, err)
+	}
+	s.jobs = append(s.jobs, job{name: name, schedule: schedule, run: run})
+	return nil
+}
+
+// Start launches one goroutine per registered job.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+	log.Printf("[scheduler] started %d job(s)", len(s.jobs))
+}
+
+// Stop cancels pending runs and waits for running jobs to finish, or for ctx to expire.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("[scheduler] stopped")
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("scheduler stop: %w", ctx.Err())
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("[scheduler] job %s has no upcoming run, disabled", j.name)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.runLocked(ctx, j)
+	}
+}
+
+func (s *Scheduler) runLocked(ctx context.Context, j job) {
+	// Named locks belong to a session, so hold a dedicated connection for the run
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		log.Printf("[scheduler] job %s: get connection: %v", j.name, err)
+		return
+	}
+	defer conn.Close()
+
+	lockName := "invoice_backend.job." + j.name
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, 0)`, lockName).Scan(&acquired); err != nil {
+		log.Printf("[scheduler] job %s: acquire lock: %v", j.name, err)
+		return
+	}
+	if acquired.Int64 != 1 {
+		log.Printf("[scheduler] job %s: already running on another instance, skipped", j.name)
+		return
+	}
+	defer func() {
+		// Release even if the job context was cancelled during shutdown
+		if _, err := conn.ExecContext(context.Background(), `SELECT RELEASE_LOCK(?)`, lockName); err != nil {
+			log.Printf("[scheduler] job %s: release lock: %v", j.name, err)
+		}
+	}()
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[scheduler] job %s panicked: %v", j.name, r)
+		}
+	}()
+
+	if err := j.run(ctx); err != nil {
+		log.Printf("[scheduler] job %s failed after %s: %v", j.name, time.Since(start).Round(time.Millisecond), err)
+		return
+	}
+	log.Printf("[scheduler] job %s finished in %s", j.name, time.Since(start).Round(time.Millisecond))
+}
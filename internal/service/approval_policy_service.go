@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+type ApprovalPolicyService struct {
+	policyRepo *repository.ApprovalPolicyRepository
+	auditRepo  *repository.AuditLogRepository
+}
+
+func NewApprovalPolicyService(policyRepo *repository.ApprovalPolicyRepository, auditRepo *repository.AuditLogRepository) *ApprovalPolicyService {
+	return &ApprovalPolicyService{
+		policyRepo: policyRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+func (s *ApprovalPolicyService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "approval_policy",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *ApprovalPolicyService) Create(ctx context.Context, req *request.CreateApprovalPolicyRequest, userID uint64) (*response.ApprovalPolicyResponse, error) {
+	policy := &model.ApprovalPolicy{
+		Name:      strings.TrimSpace(req.Name),
+		MinAmount: req.MinAmount,
+		IsActive:  true,
+		Steps:     buildApprovalPolicySteps(req.Steps),
+		CreatedBy: userID,
+	}
+	if req.InvoiceType != "" {
+		t := model.InvoiceType(req.InvoiceType)
+		policy.InvoiceType = &t
+	}
+
+	if err := s.checkUnique(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	id, err := s.policyRepo.Create(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf("create approval policy: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", id, policyDetails(policy))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *ApprovalPolicyService) List(ctx context.Context) ([]response.ApprovalPolicyResponse, error) {
+	policies, err := s.policyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.ApprovalPolicyResponse, 0, len(policies))
+	for i := range policies {
+		result = append(result, toApprovalPolicyResponse(&policies[i]))
+	}
+	return result, nil
+}
+
+func (s *ApprovalPolicyService) GetByID(ctx context.Context, id uint64) (*response.ApprovalPolicyResponse, error) {
+	policy, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("approval policy not found")
+		}
+		return nil, err
+	}
+
+	resp := toApprovalPolicyResponse(policy)
+	return &resp, nil
+}
+
+// Update changes a policy. Invoices already in review keep the steps they
+// were submitted with.
+func (s *ApprovalPolicyService) Update(ctx context.Context, id uint64, req *request.UpdateApprovalPolicyRequest, userID uint64) (*response.ApprovalPolicyResponse, error) {
+	policy, err := s.policyRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("approval policy not found")
+		}
+		return nil, err
+	}
+
+	if req.Name != "" {
+		policy.Name = strings.TrimSpace(req.Name)
+	}
+	if req.InvoiceType != nil {
+		policy.InvoiceType = nil
+		if *req.InvoiceType != "" {
+			t := model.InvoiceType(*req.InvoiceType)
+			policy.InvoiceType = &t
+		}
+	}
+	if req.MinAmount != nil {
+		policy.MinAmount = *req.MinAmount
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	var steps []model.ApprovalPolicyStep
+	if len(req.Steps) > 0 {
+		steps = buildApprovalPolicySteps(req.Steps)
+		policy.Steps = steps
+	}
+
+	if err := s.checkUnique(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	if err := s.policyRepo.Update(ctx, policy, steps); err != nil {
+		return nil, fmt.Errorf("update approval policy: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, policyDetails(policy))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *ApprovalPolicyService) Delete(ctx context.Context, id uint64, userID uint64) error {
+	if err := s.policyRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("approval policy not found")
+		}
+		return err
+	}
+
+	s.logAudit(ctx, userID, "DELETE", id, "")
+	return nil
+}
+
+// checkUnique rejects a second active policy for the same invoice type and
+// threshold, since only one of them could ever match.
+func (s *ApprovalPolicyService) checkUnique(ctx context.Context, p *model.ApprovalPolicy) error {
+	if !p.IsActive {
+		return nil
+	}
+	exists, err := s.policyRepo.ExistsActive(ctx, p.InvoiceType, p.MinAmount, p.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("an active policy with the same invoice type and minimum amount already exists")
+	}
+	return nil
+}
+
+// buildApprovalPolicySteps converts request steps, dropping duplicate roles
+// within a step.
+func buildApprovalPolicySteps(reqs []request.ApprovalPolicyStepRequest) []model.ApprovalPolicyStep {
+	steps := make([]model.ApprovalPolicyStep, 0, len(reqs))
+	for i, r := range reqs {
+		var roles []string
+		seen := make(map[string]bool)
+		for _, role := range r.ApproverRoles {
+			if !seen[role] {
+				seen[role] = true
+				roles = append(roles, role)
+			}
+		}
+		steps = append(steps, model.ApprovalPolicyStep{StepOrder: i + 1, ApproverRoles: roles})
+	}
+	return steps
+}
+
+func policyDetails(p *model.ApprovalPolicy) string {
+	invoiceType := "ALL"
+	if p.InvoiceType != nil {
+		invoiceType = string(*p.InvoiceType)
+	}
+	steps := make([]string, len(p.Steps))
+	for i, st := range p.Steps {
+		steps[i] = strings.Join(st.ApproverRoles, "/")
+	}
+	return fmt.Sprintf("name=%s, invoice_type=%s, min_amount=%s, active=%t, steps=%s",
+		p.Name, invoiceType, p.MinAmount, p.IsActive, strings.Join(steps, " > "))
+}
+
+func toApprovalPolicyResponse(p *model.ApprovalPolicy) response.ApprovalPolicyResponse {
+	resp := response.ApprovalPolicyResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		MinAmount: p.MinAmount,
+		IsActive:  p.IsActive,
+		Steps:     make([]response.ApprovalPolicyStepResponse, len(p.Steps)),
+		CreatedBy: p.CreatedBy,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+	if p.InvoiceType != nil {
+		resp.InvoiceType = string(*p.InvoiceType)
+	}
+	for i, st := range p.Steps {
+		resp.Steps[i] = response.ApprovalPolicyStepResponse{
+			StepOrder:     st.StepOrder,
+			ApproverRoles: st.ApproverRoles,
+		}
+	}
+	return resp
+}
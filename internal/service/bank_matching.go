@@ -0,0 +1,120 @@
+package service
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// Match scoring. An invoice is suggested when its number appears in the
+// transfer description or the amount matches its outstanding balance exactly;
+// the date window only adds confidence.
+const (
+	matchScoreInvoiceNumber = 60
+	matchScoreExactAmount   = 30
+	matchScorePartialAmount = 5
+	matchScoreDateWindow    = 10
+	matchMinScore           = 30
+	matchMaxSuggestions     = 3
+
+	// Transfers are expected between the invoice date and this many days
+	// after the due date
+	matchDateWindowDays = 30
+)
+
+// suggestMatches ranks outstanding invoices for an incoming transaction.
+// Invoices with a smaller outstanding balance than the transfer are skipped,
+// since the payment would exceed it.
+func suggestMatches(t *model.BankTransaction, invoices []model.Invoice) []response.BankMatchSuggestion {
+	text := normalizeMatchText(t.Description + " " + t.Reference)
+
+	var suggestions []response.BankMatchSuggestion
+	for i := range invoices {
+		inv := &invoices[i]
+		outstanding := inv.Amount - inv.PaidAmount - inv.CreditedAmount
+		if t.Amount > outstanding {
+			continue
+		}
+
+		score := 0
+		var reasons []string
+		if number := normalizeMatchText(inv.InvoiceNumber); len(number) >= 4 && strings.Contains(text, number) {
+			score += matchScoreInvoiceNumber
+			reasons = append(reasons, "invoice_number")
+		}
+		if t.Amount == outstanding {
+			score += matchScoreExactAmount
+			reasons = append(reasons, "exact_amount")
+		} else {
+			score += matchScorePartialAmount
+			reasons = append(reasons, "partial_amount")
+		}
+		if inDateWindow(t.TransactionDate, inv) {
+			score += matchScoreDateWindow
+			reasons = append(reasons, "date_window")
+		}
+		if score < matchMinScore {
+			continue
+		}
+
+		s := response.BankMatchSuggestion{
+			InvoiceID:     inv.ID,
+			InvoiceNumber: inv.InvoiceNumber,
+			RecipientName: inv.RecipientName,
+			Outstanding:   outstanding,
+			InvoiceDate:   inv.InvoiceDate.Format("2006-01-02"),
+			Score:         score,
+			Confidence:    matchConfidence(score),
+			Reasons:       reasons,
+		}
+		if inv.DueDate != nil {
+			s.DueDate = inv.DueDate.Format("2006-01-02")
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	if len(suggestions) > matchMaxSuggestions {
+		suggestions = suggestions[:matchMaxSuggestions]
+	}
+	return suggestions
+}
+
+func inDateWindow(date time.Time, inv *model.Invoice) bool {
+	due := inv.InvoiceDate
+	if inv.DueDate != nil {
+		due = *inv.DueDate
+	}
+	start := truncateDate(inv.InvoiceDate)
+	end := truncateDate(due).AddDate(0, 0, matchDateWindowDays)
+	d := truncateDate(date)
+	return !d.Before(start) && !d.After(end)
+}
+
+func matchConfidence(score int) string {
+	switch {
+	case score >= 90:
+		return "HIGH"
+	case score >= 60:
+		return "MEDIUM"
+	}
+	return "LOW"
+}
+
+// normalizeMatchText uppercases s and drops everything but letters and digits,
+// so "001/INV/ABC/03/2026" matches "001INVABC032026" in a bank description.
+func normalizeMatchText(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
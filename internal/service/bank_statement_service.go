@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/bankstatement"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+type BankStatementService struct {
+	statementRepo  *repository.BankStatementRepository
+	invoiceRepo    *repository.InvoiceRepository
+	userRepo       *repository.UserRepository
+	auditRepo      *repository.AuditLogRepository
+	paymentService *InvoicePaymentService
+}
+
+func NewBankStatementService(
+	statementRepo *repository.BankStatementRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditLogRepository,
+	paymentService *InvoicePaymentService,
+) *BankStatementService {
+	return &BankStatementService{
+		statementRepo:  statementRepo,
+		invoiceRepo:    invoiceRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		paymentService: paymentService,
+	}
+}
+
+func (s *BankStatementService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "bank_statement",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+// Import parses an uploaded statement file and stores its transactions.
+// accountNumber overrides the account found in the file, if any.
+func (s *BankStatementService) Import(ctx context.Context, fileName string, data []byte, accountNumber string, userID uint64) (*response.BankStatementResponse, error) {
+	parsed, err := bankstatement.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statement file: %v", err)
+	}
+	if accountNumber == "" {
+		accountNumber = parsed.AccountNumber
+	}
+
+	st := &model.BankStatement{
+		Format:        parsed.Format,
+		AccountNumber: accountNumber,
+		FileName:      fileName,
+		PeriodStart:   parsed.PeriodStart,
+		PeriodEnd:     parsed.PeriodEnd,
+		UploadedBy:    userID,
+	}
+
+	txns := make([]model.BankTransaction, 0, len(parsed.Transactions))
+	seen := make(map[string]int)
+	for _, t := range parsed.Transactions {
+		bt := model.BankTransaction{
+			TransactionDate: t.Date,
+			Description:     truncate(t.Description, 500),
+			Reference:       truncate(t.Reference, 100),
+			Amount:          t.Amount,
+			Direction:       model.BankTransactionDebit,
+		}
+		if t.Credit {
+			bt.Direction = model.BankTransactionCredit
+		}
+		key := transactionKey(accountNumber, &bt)
+		// Identical lines within one file are distinct transactions
+		bt.Fingerprint = fingerprint(fmt.Sprintf("%s|%d", key, seen[key]))
+		seen[key]++
+		txns = append(txns, bt)
+	}
+
+	id, err := s.statementRepo.Create(ctx, st, txns)
+	if err != nil {
+		return nil, fmt.Errorf("import bank statement: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "IMPORT", id,
+		fmt.Sprintf("file=%s, format=%s, account=%s, transactions=%d, duplicates=%d", fileName, st.Format, accountNumber, st.TransactionCount, st.DuplicateCount))
+
+	return s.Get(ctx, id, "")
+}
+
+func (s *BankStatementService) List(ctx context.Context) ([]response.BankStatementResponse, error) {
+	statements, err := s.statementRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.BankStatementResponse, 0, len(statements))
+	for i := range statements {
+		result = append(result, s.toStatementResponse(ctx, &statements[i]))
+	}
+	return result, nil
+}
+
+// Get returns a statement with its transactions for reconciliation. Unmatched
+// incoming transactions carry suggested invoices.
+func (s *BankStatementService) Get(ctx context.Context, id uint64, status string) (*response.BankStatementResponse, error) {
+	st, err := s.statementRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("bank statement not found")
+		}
+		return nil, err
+	}
+
+	txns, err := s.statementRepo.FindTransactions(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+
+	outstanding, err := s.invoiceRepo.FindOutstanding(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.toStatementResponse(ctx, st)
+	resp.Transactions = make([]response.BankTransactionResponse, 0, len(txns))
+	for i := range txns {
+		t := &txns[i]
+		tr := toBankTransactionResponse(t)
+		if t.Status == model.BankTransactionUnmatched && t.Direction == model.BankTransactionCredit {
+			tr.Suggestions = suggestMatches(t, outstanding)
+		}
+		resp.Transactions = append(resp.Transactions, tr)
+	}
+	return &resp, nil
+}
+
+// ConfirmMatches records a TRANSFER payment for each confirmed match. Matches
+// are applied one by one; a failed match does not undo the others.
+func (s *BankStatementService) ConfirmMatches(ctx context.Context, statementID uint64, req *request.ConfirmBankMatchesRequest, userID uint64) ([]response.BankMatchResultResponse, error) {
+	if _, err := s.statementRepo.FindByID(ctx, statementID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("bank statement not found")
+		}
+		return nil, err
+	}
+
+	results := make([]response.BankMatchResultResponse, 0, len(req.Matches))
+	for _, m := range req.Matches {
+		result := response.BankMatchResultResponse{
+			TransactionID: m.TransactionID,
+			InvoiceID:     m.InvoiceID,
+			Status:        string(model.BankTransactionMatched),
+		}
+		paymentID, err := s.match(ctx, statementID, &m, userID)
+		if err != nil {
+			result.Status = "FAILED"
+			result.Error = err.Error()
+		} else {
+			result.PaymentID = &paymentID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *BankStatementService) match(ctx context.Context, statementID uint64, m *request.BankMatchRequest, userID uint64) (uint64, error) {
+	t, err := s.statementRepo.FindTransactionByID(ctx, m.TransactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("bank transaction not found")
+		}
+		return 0, err
+	}
+	if t.StatementID != statementID {
+		return 0, fmt.Errorf("bank transaction not found")
+	}
+
+	inv, err := s.invoiceRepo.FindByID(ctx, m.InvoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("invoice not found")
+		}
+		return 0, err
+	}
+
+	notes := fmt.Sprintf("Bank statement %d: %s", statementID, t.Description)
+	if m.Notes != "" {
+		notes = m.Notes
+	}
+	payment := &model.InvoicePayment{
+		InvoiceID:     inv.ID,
+		PaymentMethod: model.PaymentMethodTransfer,
+		Notes:         truncate(notes, 2000),
+		CreatedBy:     userID,
+	}
+
+	paymentID, change, err := s.statementRepo.MatchTransaction(ctx, t.ID, payment, userID)
+	if err != nil {
+		switch {
+		case isPaymentRuleError(err),
+			err.Error() == "only incoming transactions can be matched",
+			err.Error() == "bank transaction is already reconciled":
+			return 0, err
+		}
+		log.Printf("match bank transaction %d: %v", t.ID, err)
+		return 0, fmt.Errorf("failed to record payment")
+	}
+
+	s.logAudit(ctx, userID, "MATCH", statementID,
+		fmt.Sprintf("transaction=%d, invoice=%s, payment=%d, amount=%s, %s", t.ID, inv.InvoiceNumber, paymentID, payment.Amount, balanceDetails(change)))
+	s.paymentService.notifyPaymentRecorded(ctx, inv, payment.Amount, change)
+
+	return paymentID, nil
+}
+
+// SetIgnored excludes a transaction from reconciliation, e.g. an internal
+// transfer, or brings it back.
+func (s *BankStatementService) SetIgnored(ctx context.Context, statementID, transactionID uint64, ignored bool, userID uint64) error {
+	t, err := s.statementRepo.FindTransactionByID(ctx, transactionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("bank transaction not found")
+		}
+		return err
+	}
+	if t.StatementID != statementID {
+		return fmt.Errorf("bank transaction not found")
+	}
+
+	if err := s.statementRepo.SetTransactionIgnored(ctx, transactionID, ignored); err != nil {
+		return err
+	}
+
+	action := "IGNORE"
+	if !ignored {
+		action = "UNIGNORE"
+	}
+	s.logAudit(ctx, userID, action, statementID, fmt.Sprintf("transaction=%d", transactionID))
+	return nil
+}
+
+func (s *BankStatementService) toStatementResponse(ctx context.Context, st *model.BankStatement) response.BankStatementResponse {
+	resp := response.BankStatementResponse{
+		ID:               st.ID,
+		Format:           st.Format,
+		AccountNumber:    st.AccountNumber,
+		FileName:         st.FileName,
+		TransactionCount: st.TransactionCount,
+		DuplicateCount:   st.DuplicateCount,
+		MatchedCount:     st.MatchedCount,
+		UnmatchedCount:   st.UnmatchedCount,
+		UploadedBy:       st.UploadedBy,
+		CreatedAt:        st.CreatedAt,
+	}
+	if st.PeriodStart != nil {
+		resp.PeriodStart = st.PeriodStart.Format("2006-01-02")
+	}
+	if st.PeriodEnd != nil {
+		resp.PeriodEnd = st.PeriodEnd.Format("2006-01-02")
+	}
+	if u, err := s.userRepo.FindByID(ctx, st.UploadedBy); err == nil {
+		resp.UploaderName = u.FullName
+	}
+	return resp
+}
+
+func toBankTransactionResponse(t *model.BankTransaction) response.BankTransactionResponse {
+	return response.BankTransactionResponse{
+		ID:              t.ID,
+		TransactionDate: t.TransactionDate.Format("2006-01-02"),
+		Description:     t.Description,
+		Reference:       t.Reference,
+		Amount:          t.Amount,
+		Direction:       string(t.Direction),
+		Status:          string(t.Status),
+		InvoiceID:       t.InvoiceID,
+		InvoiceNumber:   t.InvoiceNumber,
+		PaymentID:       t.PaymentID,
+		MatchedBy:       t.MatchedBy,
+		MatchedAt:       t.MatchedAt,
+	}
+}
+
+// transactionKey identifies a statement line independently of the file it was
+// imported from.
+func transactionKey(account string, t *model.BankTransaction) string {
+	return strings.Join([]string{
+		account,
+		t.TransactionDate.Format("2006-01-02"),
+		string(t.Direction),
+		t.Amount.String(),
+		strings.ToUpper(t.Description),
+		t.Reference,
+	}, "|")
+}
+
+func fingerprint(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
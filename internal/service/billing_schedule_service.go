@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+// billingTermLabels are the default term descriptions per invoice type.
+var billingTermLabels = map[model.InvoiceType]string{
+	model.InvoiceTypeDP:           "Uang Muka",
+	model.InvoiceTypeTOP1:         "Termin 1",
+	model.InvoiceTypeTOP2:         "Termin 2",
+	model.InvoiceTypeTOP3:         "Termin 3",
+	model.InvoiceTypeFinalPayment: "Pelunasan",
+}
+
+type BillingScheduleService struct {
+	scheduleRepo   *repository.BillingScheduleRepository
+	projectRepo    *repository.ProjectRepository
+	auditRepo      *repository.AuditLogRepository
+	invoiceService *InvoiceService
+}
+
+func NewBillingScheduleService(
+	scheduleRepo *repository.BillingScheduleRepository,
+	projectRepo *repository.ProjectRepository,
+	auditRepo *repository.AuditLogRepository,
+	invoiceService *InvoiceService,
+) *BillingScheduleService {
+	return &BillingScheduleService{
+		scheduleRepo:   scheduleRepo,
+		projectRepo:    projectRepo,
+		auditRepo:      auditRepo,
+		invoiceService: invoiceService,
+	}
+}
+
+func (s *BillingScheduleService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "billing_schedule",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *BillingScheduleService) Get(ctx context.Context, projectID uint64) (*response.BillingScheduleResponse, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+
+	schedule, err := s.scheduleRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("billing schedule not found")
+		}
+		return nil, err
+	}
+
+	invoiced, err := s.scheduleRepo.InvoicedTotal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.BillingScheduleResponse{
+		ProjectID:      projectID,
+		ProjectName:    project.Name,
+		ContractValue:  schedule.ContractValue,
+		PPNPercentage:  schedule.PPNPercentage,
+		PPHPercentage:  schedule.PPHPercentage,
+		Notes:          schedule.Notes,
+		InvoicedAmount: invoiced,
+		UnbilledAmount: schedule.ContractValue - invoiced,
+		Terms:          make([]response.BillingTermResponse, 0, len(schedule.Terms)),
+		UpdatedBy:      schedule.UpdatedBy,
+		UpdatedAt:      schedule.UpdatedAt,
+	}
+
+	for _, t := range schedule.Terms {
+		resp.ScheduledPercentage += t.Percentage
+		resp.ScheduledAmount += t.Amount
+
+		term := response.BillingTermResponse{
+			ID:          t.ID,
+			InvoiceType: string(t.InvoiceType),
+			Description: t.Description,
+			Percentage:  t.Percentage,
+			Amount:      t.Amount,
+			Status:      "UNBILLED",
+		}
+		if t.ExpectedDate != nil {
+			term.ExpectedDate = t.ExpectedDate.Format("2006-01-02")
+		}
+		if t.InvoiceID != nil {
+			term.Status = "INVOICED"
+			term.InvoiceID = t.InvoiceID
+			term.InvoiceNumber = t.InvoiceNumber
+			term.InvoiceStatus = string(t.InvoiceStatus)
+		}
+		resp.Terms = append(resp.Terms, term)
+	}
+	resp.ScheduledPercentage = roundPercent(resp.ScheduledPercentage)
+	resp.UnscheduledAmount = schedule.ContractValue - resp.ScheduledAmount
+
+	return resp, nil
+}
+
+// Save creates or replaces the billing schedule of a project. Term amounts are
+// derived from the contract value; the repository settles the remainder and
+// the contract cap once the amounts of invoiced terms are known.
+func (s *BillingScheduleService) Save(ctx context.Context, projectID uint64, req *request.SaveBillingScheduleRequest, userID uint64) (*response.BillingScheduleResponse, error) {
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+
+	schedule := &model.BillingSchedule{
+		ProjectID:     projectID,
+		ContractValue: req.ContractValue,
+		PPNPercentage: req.PPNPercentage,
+		PPHPercentage: req.PPHPercentage,
+		Notes:         req.Notes,
+		UpdatedBy:     userID,
+	}
+
+	seen := make(map[model.InvoiceType]bool)
+	var totalPct float64
+	for _, t := range req.Terms {
+		invType := model.InvoiceType(t.InvoiceType)
+		if seen[invType] {
+			return nil, fmt.Errorf("duplicate billing term type")
+		}
+		seen[invType] = true
+
+		term := model.BillingTerm{
+			ID:          t.ID,
+			ProjectID:   projectID,
+			InvoiceType: invType,
+			Description: t.Description,
+			Percentage:  t.Percentage,
+			Amount:      schedule.ContractValue.MulPercent(t.Percentage),
+		}
+		if term.Description == "" {
+			term.Description = fmt.Sprintf("%s (%s%%)", billingTermLabels[invType], strconv.FormatFloat(t.Percentage, 'f', -1, 64))
+		}
+		if t.ExpectedDate != "" {
+			date, err := time.Parse("2006-01-02", t.ExpectedDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid expected date format, use YYYY-MM-DD")
+			}
+			term.ExpectedDate = &date
+		}
+
+		totalPct += t.Percentage
+		schedule.Terms = append(schedule.Terms, term)
+	}
+
+	totalPct = roundPercent(totalPct)
+	if totalPct > 100 {
+		return nil, fmt.Errorf("billing terms exceed the contract value")
+	}
+
+	if err := s.scheduleRepo.Save(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", projectID,
+		fmt.Sprintf("contract_value=%s, terms=%d, scheduled_pct=%.2f", schedule.ContractValue, len(schedule.Terms), totalPct))
+
+	return s.Get(ctx, projectID)
+}
+
+// GenerateInvoice creates a pending invoice for a billing term, billed at the
+// term amount with the schedule's tax rates.
+func (s *BillingScheduleService) GenerateInvoice(ctx context.Context, projectID, termID uint64, req *request.GenerateTermInvoiceRequest, userID uint64, role string) (*response.InvoiceResponse, error) {
+	schedule, err := s.scheduleRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("billing schedule not found")
+		}
+		return nil, err
+	}
+
+	term, err := s.scheduleRepo.FindTermByID(ctx, termID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("billing term not found")
+		}
+		return nil, err
+	}
+	if term.ProjectID != projectID {
+		return nil, fmt.Errorf("billing term not found")
+	}
+	if term.InvoiceID != nil {
+		return nil, fmt.Errorf("billing term already invoiced")
+	}
+
+	invoiceDate := req.InvoiceDate
+	if invoiceDate == "" {
+		invoiceDate = time.Now().Format("2006-01-02")
+	}
+	language := req.Language
+	if language == "" {
+		language = "ID"
+	}
+
+	invReq := &request.CreateInvoiceRequest{
+		ProjectID:        projectID,
+		InvoiceType:      string(term.InvoiceType),
+		ClientID:         req.ClientID,
+		RecipientName:    req.RecipientName,
+		RecipientAddress: req.RecipientAddress,
+		Attention:        req.Attention,
+		PONumber:         req.PONumber,
+		InvoiceDate:      invoiceDate,
+		DueDate:          req.DueDate,
+		PPNPercentage:    &schedule.PPNPercentage,
+		PPHPercentage:    schedule.PPHPercentage,
+		Notes:            req.Notes,
+		Language:         language,
+		Items: []request.InvoiceItemRequest{{
+			Description: term.Description,
+			Quantity:    1,
+			Unit:        "lot",
+			UnitPrice:   term.Amount,
+		}},
+	}
+	if term.InvoiceType == model.InvoiceTypeDP {
+		pct := term.Percentage
+		invReq.DPPercentage = &pct
+	}
+
+	result, err := s.invoiceService.create(ctx, invReq, &term.ID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "GENERATE_INVOICE", projectID,
+		fmt.Sprintf("term=%d, type=%s, invoice=%d, amount=%s", term.ID, term.InvoiceType, result.ID, term.Amount))
+
+	return result, nil
+}
+
+// roundPercent rounds a sum of percentages to the two decimals they are stored
+// with, so that e.g. 33.33 + 33.33 + 33.34 compares equal to 100.
+func roundPercent(v float64) float64 {
+	return math.Round(v*100) / 100
+}
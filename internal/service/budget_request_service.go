@@ -18,8 +18,6 @@ type BudgetRequestService struct {
 	budgetRequestRepo *repository.BudgetRequestRepository
 	projectRepo       *repository.ProjectRepository
 	memberRepo        *repository.ProjectMemberRepository
-	budgetRepo        *repository.BudgetRepository
-	expenseRepo       *repository.ExpenseRepository
 	auditRepo         *repository.AuditLogRepository
 	notifRepo         *repository.NotificationRepository
 	userRepo          *repository.UserRepository
@@ -30,8 +28,6 @@ func NewBudgetRequestService(
 	budgetRequestRepo *repository.BudgetRequestRepository,
 	projectRepo *repository.ProjectRepository,
 	memberRepo *repository.ProjectMemberRepository,
-	budgetRepo *repository.BudgetRepository,
-	expenseRepo *repository.ExpenseRepository,
 	auditRepo *repository.AuditLogRepository,
 	notifRepo *repository.NotificationRepository,
 	userRepo *repository.UserRepository,
@@ -41,8 +37,6 @@ func NewBudgetRequestService(
 		budgetRequestRepo: budgetRequestRepo,
 		projectRepo:       projectRepo,
 		memberRepo:        memberRepo,
-		budgetRepo:        budgetRepo,
-		expenseRepo:       expenseRepo,
 		auditRepo:         auditRepo,
 		notifRepo:         notifRepo,
 		userRepo:          userRepo,
@@ -129,9 +123,9 @@ func (s *BudgetRequestService) Create(ctx context.Context, req *request.CreateBu
 	}
 
 	// Audit + Notification
-	s.logAudit(ctx, userID, "CREATE", "budget_request", id, fmt.Sprintf("amount=%.2f, reason=%s", br.Amount, br.Reason))
+	s.logAudit(ctx, userID, "CREATE", "budget_request", id, fmt.Sprintf("amount=%s, reason=%s", br.Amount, br.Reason))
 	s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Permintaan Budget Baru",
-		fmt.Sprintf("Permintaan budget sebesar Rp %.0f telah diajukan", br.Amount),
+		fmt.Sprintf("Permintaan budget sebesar Rp %.0f telah diajukan", br.Amount.Float64()),
 		model.NotifBudgetRequest, id)
 
 	return &response.BudgetRequestResponse{
@@ -199,7 +193,10 @@ func (s *BudgetRequestService) Approve(ctx context.Context, id, approvedBy uint6
 		return nil, err
 	}
 
-	if err := s.budgetRequestRepo.ApproveBudgetRequest(ctx, id, approvedBy, notes, proofURL); err != nil {
+	// Approval also raises the project budget and records the amount as an
+	// approved expense
+	expenseID, err := s.budgetRequestRepo.ApproveBudgetRequest(ctx, id, approvedBy, notes, proofURL)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("budget request not found")
 		}
@@ -209,39 +206,17 @@ func (s *BudgetRequestService) Approve(ctx context.Context, id, approvedBy uint6
 		return nil, fmt.Errorf("approve budget request: %w", err)
 	}
 
-	// Increase project total budget by approved amount
-	if err := s.budgetRepo.IncreaseTotalBudget(ctx, br.ProjectID, br.Amount); err != nil {
-		log.Printf("failed to increase budget for project %d: %v", br.ProjectID, err)
-	}
-
-	// Auto-create expense from approved budget request
-	receiptURL := ""
-	if br.ProofURL != nil {
-		receiptURL = *br.ProofURL
-	}
-	expenseID, err := s.expenseRepo.Create(ctx, &model.Expense{
-		ProjectID:   br.ProjectID,
-		Description: fmt.Sprintf("Budget Request: %s", br.Reason),
-		Amount:      br.Amount,
-		Category:    "Budget Request",
-		ReceiptURL:  receiptURL,
-		CreatedBy:   br.RequestedBy,
+	s.logAudit(ctx, approvedBy, "CREATE", "expense", expenseID, fmt.Sprintf("auto-created from budget request %d, amount=%s", id, br.Amount))
+	// Notify requester about auto-created expense via SSE
+	s.sseHub.Publish(br.RequestedBy, sse.Event{
+		Type: "expense_update",
+		Data: map[string]interface{}{"id": expenseID, "action": "created"},
 	})
-	if err != nil {
-		log.Printf("failed to auto-create expense from budget request %d: %v", id, err)
-	} else {
-		s.logAudit(ctx, approvedBy, "CREATE", "expense", expenseID, fmt.Sprintf("auto-created from budget request %d, amount=%.2f", id, br.Amount))
-		// Notify requester about auto-created expense via SSE
-		s.sseHub.Publish(br.RequestedBy, sse.Event{
-			Type: "expense_update",
-			Data: map[string]interface{}{"id": expenseID, "action": "created"},
-		})
-	}
 
 	// Audit + Notification
-	s.logAudit(ctx, approvedBy, "APPROVE", "budget_request", id, fmt.Sprintf("amount=%.2f added to project budget", br.Amount))
+	s.logAudit(ctx, approvedBy, "APPROVE", "budget_request", id, fmt.Sprintf("amount=%s added to project budget", br.Amount))
 	s.notifyUser(ctx, br.RequestedBy, "Permintaan Budget Disetujui",
-		fmt.Sprintf("Permintaan budget Rp %.0f telah disetujui", br.Amount),
+		fmt.Sprintf("Permintaan budget Rp %.0f telah disetujui", br.Amount.Float64()),
 		model.NotifBudgetApproved, id)
 
 	updated, err := s.budgetRequestRepo.FindByID(ctx, id)
@@ -275,7 +250,7 @@ func (s *BudgetRequestService) Reject(ctx context.Context, id, approvedBy uint64
 	// Audit + Notification
 	s.logAudit(ctx, approvedBy, "REJECT", "budget_request", id, "")
 	s.notifyUser(ctx, br.RequestedBy, "Permintaan Budget Ditolak",
-		fmt.Sprintf("Permintaan budget Rp %.0f ditolak", br.Amount),
+		fmt.Sprintf("Permintaan budget Rp %.0f ditolak", br.Amount.Float64()),
 		model.NotifBudgetRejected, id)
 
 	updated, err := s.budgetRequestRepo.FindByID(ctx, id)
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+const defaultPaymentTermsDays = 30
+
+type ClientService struct {
+	clientRepo *repository.ClientRepository
+	auditRepo  *repository.AuditLogRepository
+}
+
+func NewClientService(clientRepo *repository.ClientRepository, auditRepo *repository.AuditLogRepository) *ClientService {
+	return &ClientService{
+		clientRepo: clientRepo,
+		auditRepo:  auditRepo,
+	}
+}
+
+func (s *ClientService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "client",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *ClientService) Create(ctx context.Context, req *request.CreateClientRequest, userID uint64) (*response.ClientResponse, error) {
+	npwp, err := normalizeNPWP(req.NPWP)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &model.Client{
+		Name:             strings.TrimSpace(req.Name),
+		NPWP:             npwp,
+		BillingAddress:   req.BillingAddress,
+		Email:            req.Email,
+		Phone:            req.Phone,
+		PaymentTermsDays: defaultPaymentTermsDays,
+		Notes:            req.Notes,
+		IsActive:         true,
+		CreatedBy:        userID,
+		Contacts:         buildClientContacts(req.Contacts),
+	}
+	if req.PaymentTermsDays != nil {
+		client.PaymentTermsDays = *req.PaymentTermsDays
+	}
+
+	id, err := s.clientRepo.Create(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("name=%s, npwp=%s", client.Name, client.NPWP))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *ClientService) GetByID(ctx context.Context, id uint64) (*response.ClientResponse, error) {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client not found")
+		}
+		return nil, err
+	}
+
+	resp := toClientResponse(client)
+	return &resp, nil
+}
+
+func (s *ClientService) List(ctx context.Context, search string, includeInactive bool) ([]response.ClientResponse, error) {
+	clients, err := s.clientRepo.FindAll(ctx, strings.TrimSpace(search), includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.ClientResponse, 0, len(clients))
+	for _, c := range clients {
+		result = append(result, toClientResponse(&c))
+	}
+	return result, nil
+}
+
+func (s *ClientService) Update(ctx context.Context, id uint64, req *request.UpdateClientRequest, userID uint64) (*response.ClientResponse, error) {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client not found")
+		}
+		return nil, err
+	}
+
+	if req.Name != "" {
+		client.Name = strings.TrimSpace(req.Name)
+	}
+	if req.NPWP != nil {
+		npwp, err := normalizeNPWP(*req.NPWP)
+		if err != nil {
+			return nil, err
+		}
+		client.NPWP = npwp
+	}
+	if req.BillingAddress != nil {
+		client.BillingAddress = *req.BillingAddress
+	}
+	if req.Email != nil {
+		client.Email = *req.Email
+	}
+	if req.Phone != nil {
+		client.Phone = *req.Phone
+	}
+	if req.PaymentTermsDays != nil {
+		client.PaymentTermsDays = *req.PaymentTermsDays
+	}
+	if req.Notes != nil {
+		client.Notes = *req.Notes
+	}
+	if req.IsActive != nil {
+		client.IsActive = *req.IsActive
+	}
+
+	var contacts []model.ClientContact
+	if req.Contacts != nil {
+		contacts = buildClientContacts(req.Contacts)
+		if contacts == nil {
+			contacts = []model.ClientContact{}
+		}
+	}
+
+	if err := s.clientRepo.Update(ctx, client, contacts); err != nil {
+		return nil, fmt.Errorf("update client: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, fmt.Sprintf("name=%s", client.Name))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *ClientService) Delete(ctx context.Context, id uint64, userID uint64) error {
+	if err := s.clientRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("client not found")
+		}
+		return err
+	}
+
+	s.logAudit(ctx, userID, "DELETE", id, "")
+	return nil
+}
+
+// normalizeNPWP strips separators from an NPWP and checks it has 15 digits
+// (legacy format) or 16 digits (NIK-based format). An empty value is allowed.
+func normalizeNPWP(npwp string) (string, error) {
+	var digits strings.Builder
+	for _, r := range npwp {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '.' || r == '-' || r == ' ':
+		default:
+			return "", fmt.Errorf("invalid npwp, must be 15 or 16 digits")
+		}
+	}
+	if n := digits.Len(); n != 0 && n != 15 && n != 16 {
+		return "", fmt.Errorf("invalid npwp, must be 15 or 16 digits")
+	}
+	return digits.String(), nil
+}
+
+// buildClientContacts converts request contacts into model contacts, keeping
+// at most one primary contact.
+func buildClientContacts(reqs []request.ClientContactRequest) []model.ClientContact {
+	var contacts []model.ClientContact
+	hasPrimary := false
+	for _, c := range reqs {
+		primary := c.IsPrimary && !hasPrimary
+		if primary {
+			hasPrimary = true
+		}
+		contacts = append(contacts, model.ClientContact{
+			Name:      strings.TrimSpace(c.Name),
+			Position:  c.Position,
+			Email:     c.Email,
+			Phone:     c.Phone,
+			IsPrimary: primary,
+		})
+	}
+	return contacts
+}
+
+func toClientResponse(c *model.Client) response.ClientResponse {
+	resp := response.ClientResponse{
+		ID:               c.ID,
+		Name:             c.Name,
+		NPWP:             c.NPWP,
+		BillingAddress:   c.BillingAddress,
+		Email:            c.Email,
+		Phone:            c.Phone,
+		PaymentTermsDays: c.PaymentTermsDays,
+		Notes:            c.Notes,
+		IsActive:         c.IsActive,
+		CreatedBy:        c.CreatedBy,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+	}
+	for _, ct := range c.Contacts {
+		resp.Contacts = append(resp.Contacts, response.ClientContactResponse{
+			ID:        ct.ID,
+			Name:      ct.Name,
+			Position:  ct.Position,
+			Email:     ct.Email,
+			Phone:     ct.Phone,
+			IsPrimary: ct.IsPrimary,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+)
+
+type CommentService struct {
+	commentRepo       *repository.CommentRepository
+	invoiceRepo       *repository.InvoiceRepository
+	budgetRequestRepo *repository.BudgetRequestRepository
+	expenseRepo       *repository.ExpenseRepository
+	qcReportRepo      *repository.QCReportRepository
+	memberRepo        *repository.ProjectMemberRepository
+	userRepo          *repository.UserRepository
+	notifRepo         *repository.NotificationRepository
+	sseHub            *sse.Hub
+}
+
+func NewCommentService(
+	commentRepo *repository.CommentRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	budgetRequestRepo *repository.BudgetRequestRepository,
+	expenseRepo *repository.ExpenseRepository,
+	qcReportRepo *repository.QCReportRepository,
+	memberRepo *repository.ProjectMemberRepository,
+	userRepo *repository.UserRepository,
+	notifRepo *repository.NotificationRepository,
+	sseHub *sse.Hub,
+) *CommentService {
+	return &CommentService{
+		commentRepo:       commentRepo,
+		invoiceRepo:       invoiceRepo,
+		budgetRequestRepo: budgetRequestRepo,
+		expenseRepo:       expenseRepo,
+		qcReportRepo:      qcReportRepo,
+		memberRepo:        memberRepo,
+		userRepo:          userRepo,
+		notifRepo:         notifRepo,
+		sseHub:            sseHub,
+	}
+}
+
+// commentTarget is the record a comment thread is attached to.
+type commentTarget struct {
+	projectID uint64
+	ownerID   uint64 // creator of the record
+	label     string // how the record is named in notifications
+	// restricted reports whether a role only sees the records of projects it
+	// is a member of
+	restricted func(role string) bool
+}
+
+// findTarget loads the record a comment is attached to. Invoices follow the
+// invoice rule (all roles but FINANCE and OWNER need to be members); budget
+// requests, expenses and QC reports follow the field role rule.
+func (s *CommentService) findTarget(ctx context.Context, entityType model.CommentEntityType, entityID uint64) (*commentTarget, error) {
+	var target *commentTarget
+	var err error
+	switch entityType {
+	case model.CommentOnInvoice:
+		var inv *model.Invoice
+		if inv, err = s.invoiceRepo.FindByID(ctx, entityID); err == nil {
+			target = &commentTarget{
+				projectID: inv.ProjectID,
+				ownerID:   inv.CreatedBy,
+				label:     "invoice " + inv.InvoiceNumber,
+				restricted: func(role string) bool {
+					return role != string(model.RoleFinance) && role != string(model.RoleOwner)
+				},
+			}
+		}
+	case model.CommentOnBudgetRequest:
+		var br *model.BudgetRequest
+		if br, err = s.budgetRequestRepo.FindByID(ctx, entityID); err == nil {
+			target = &commentTarget{projectID: br.ProjectID, ownerID: br.RequestedBy,
+				label: fmt.Sprintf("pengajuan anggaran #%d", br.ID), restricted: model.IsFieldRole}
+		}
+	case model.CommentOnExpense:
+		var exp *model.Expense
+		if exp, err = s.expenseRepo.FindByID(ctx, entityID); err == nil {
+			target = &commentTarget{projectID: exp.ProjectID, ownerID: exp.CreatedBy,
+				label: fmt.Sprintf("pengeluaran #%d", exp.ID), restricted: model.IsFieldRole}
+		}
+	case model.CommentOnQCReport:
+		var rep *model.QCReport
+		if rep, err = s.qcReportRepo.FindByID(ctx, entityID); err == nil {
+			target = &commentTarget{projectID: rep.ProjectID, ownerID: rep.CreatedBy,
+				label: fmt.Sprintf("laporan QC #%d", rep.ID), restricted: model.IsFieldRole}
+		}
+	default:
+		return nil, fmt.Errorf("invalid entity type")
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("commented record not found")
+		}
+		return nil, err
+	}
+	return target, nil
+}
+
+// canView reports whether a user can see the record and its comments.
+func (s *CommentService) canView(ctx context.Context, target *commentTarget, userID uint64, role string) (bool, error) {
+	if !target.restricted(role) || target.ownerID == userID {
+		return true, nil
+	}
+	return s.memberRepo.Exists(ctx, target.projectID, userID)
+}
+
+func (s *CommentService) Create(ctx context.Context, req *request.CreateCommentRequest, userID uint64, role string) (*response.CommentResponse, error) {
+	entityType := model.CommentEntityType(req.EntityType)
+	target, err := s.findTarget(ctx, entityType, req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := s.canView(ctx, target, userID, role); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("not a member of this project")
+	}
+
+	comment := &model.Comment{
+		EntityType:  entityType,
+		EntityID:    req.EntityID,
+		ProjectID:   target.projectID,
+		Body:        req.Body,
+		CreatedBy:   userID,
+		Attachments: req.Attachments,
+	}
+
+	// Replies to a reply join the thread of its parent
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.FindByID(ctx, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("parent comment not found")
+			}
+			return nil, err
+		}
+		if parent.EntityType != entityType || parent.EntityID != req.EntityID {
+			return nil, fmt.Errorf("parent comment not found")
+		}
+		threadID := parent.ID
+		if parent.ParentID != nil {
+			threadID = *parent.ParentID
+		}
+		comment.ParentID = &threadID
+	}
+
+	// Mentioned users must be able to see the record
+	seen := make(map[uint64]bool)
+	for _, id := range req.MentionIDs {
+		if id == userID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		user, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("mentioned user not found")
+			}
+			return nil, err
+		}
+		ok, err := s.canView(ctx, target, user.ID, string(user.Role))
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("mentioned user cannot access this record")
+		}
+		comment.MentionIDs = append(comment.MentionIDs, user.ID)
+	}
+
+	id, err := s.commentRepo.Create(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	author := "Seseorang"
+	if u, err := s.userRepo.FindByID(ctx, userID); err == nil {
+		author = u.FullName
+	}
+	for _, mentioned := range comment.MentionIDs {
+		s.notifyMention(ctx, mentioned, "Anda Disebut dalam Komentar",
+			fmt.Sprintf("%s menyebut Anda dalam komentar pada %s", author, target.label),
+			id, comment)
+	}
+
+	return s.GetByID(ctx, id, userID, role)
+}
+
+func (s *CommentService) GetByID(ctx context.Context, id uint64, userID uint64, role string) (*response.CommentResponse, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, err
+	}
+	target, err := s.findTarget(ctx, comment.EntityType, comment.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := s.canView(ctx, target, userID, role); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("not a member of this project")
+	}
+
+	names := make(map[uint64]string)
+	resp := s.toCommentResponse(ctx, comment, names)
+	return &resp, nil
+}
+
+// List returns the comment threads of a record, oldest first, each with its
+// replies.
+func (s *CommentService) List(ctx context.Context, req *request.ListCommentsRequest, userID uint64, role string) ([]response.CommentResponse, error) {
+	entityType := model.CommentEntityType(req.EntityType)
+	target, err := s.findTarget(ctx, entityType, req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := s.canView(ctx, target, userID, role); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("not a member of this project")
+	}
+
+	comments, err := s.commentRepo.FindByEntity(ctx, entityType, req.EntityID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[uint64]string)
+	threads := make([]response.CommentResponse, 0, len(comments))
+	index := make(map[uint64]int)
+	for i := range comments {
+		c := &comments[i]
+		resp := s.toCommentResponse(ctx, c, names)
+		if c.ParentID == nil {
+			index[c.ID] = len(threads)
+			threads = append(threads, resp)
+			continue
+		}
+		if t, ok := index[*c.ParentID]; ok {
+			threads[t].Replies = append(threads[t].Replies, resp)
+		}
+	}
+	return threads, nil
+}
+
+func (s *CommentService) notifyMention(ctx context.Context, userID uint64, title, message string, commentID uint64, c *model.Comment) {
+	id, err := s.notifRepo.Create(ctx, &model.Notification{
+		UserID:      userID,
+		Title:       title,
+		Message:     message,
+		Type:        model.NotifCommentMention,
+		ReferenceID: &commentID,
+	})
+	if err != nil {
+		log.Printf("notification error: %v", err)
+		return
+	}
+	s.sseHub.Publish(userID, sse.Event{
+		Type: string(model.NotifCommentMention),
+		Data: map[string]interface{}{
+			"id": id, "title": title, "message": message, "reference_id": commentID,
+			"entity_type": c.EntityType, "entity_id": c.EntityID,
+		},
+	})
+}
+
+// toCommentResponse converts a comment, looking up the names of mentioned
+// users through names, which caches them across comments.
+func (s *CommentService) toCommentResponse(ctx context.Context, c *model.Comment, names map[uint64]string) response.CommentResponse {
+	resp := response.CommentResponse{
+		ID:          c.ID,
+		EntityType:  string(c.EntityType),
+		EntityID:    c.EntityID,
+		ParentID:    c.ParentID,
+		Body:        c.Body,
+		CreatedBy:   c.CreatedBy,
+		AuthorName:  c.AuthorName,
+		Attachments: c.Attachments,
+		Mentions:    make([]response.CommentMentionResponse, 0, len(c.MentionIDs)),
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+	if resp.Attachments == nil {
+		resp.Attachments = []string{}
+	}
+	for _, id := range c.MentionIDs {
+		name, ok := names[id]
+		if !ok {
+			if u, err := s.userRepo.FindByID(ctx, id); err == nil {
+				name = u.FullName
+			}
+			names[id] = name
+		}
+		resp.Mentions = append(resp.Mentions, response.CommentMentionResponse{UserID: id, FullName: name})
+	}
+	return resp
+}
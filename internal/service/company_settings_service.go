@@ -32,20 +32,56 @@ func (s *CompanySettingsService) Get(ctx context.Context) (*response.CompanySett
 }
 
 func (s *CompanySettingsService) Upsert(ctx context.Context, req *request.UpsertCompanySettingsRequest) (*response.CompanySettingsResponse, error) {
+	pattern := req.InvoiceNumberPattern
+	if pattern == "" {
+		pattern = repository.DefaultInvoiceNumberPattern
+	}
+	if err := repository.ValidateNumberPattern(pattern); err != nil {
+		return nil, fmt.Errorf("invalid invoice number pattern")
+	}
+	cnPattern := req.CreditNoteNumberPattern
+	if cnPattern == "" {
+		cnPattern = repository.DefaultCreditNoteNumberPattern
+	}
+	if err := repository.ValidateNumberPattern(cnPattern); err != nil {
+		return nil, fmt.Errorf("invalid credit note number pattern")
+	}
+	receiptPattern := req.ReceiptNumberPattern
+	if receiptPattern == "" {
+		receiptPattern = repository.DefaultReceiptNumberPattern
+	}
+	if err := repository.ValidateNumberPattern(receiptPattern); err != nil {
+		return nil, fmt.Errorf("invalid receipt number pattern")
+	}
+	stampDutyThreshold := defaultStampDutyThreshold
+	if req.StampDutyThreshold != nil {
+		stampDutyThreshold = *req.StampDutyThreshold
+	}
+	ppnMode := model.PPNMode(req.DefaultPPNMode)
+	if ppnMode == "" {
+		ppnMode = model.PPNModeStandard
+	}
+
 	cs := &model.CompanySettings{
-		CompanyName:       req.CompanyName,
-		CompanyCode:       req.CompanyCode,
-		Address:           req.Address,
-		Phone:             req.Phone,
-		Email:             req.Email,
-		NPWP:              req.NPWP,
-		BankName:          req.BankName,
-		BankAccountNumber: req.BankAccountNumber,
-		BankAccountName:   req.BankAccountName,
-		BankBranch:        req.BankBranch,
-		LogoURL:           req.LogoURL,
-		SignatoryName:     req.SignatoryName,
-		SignatoryTitle:    req.SignatoryTitle,
+		CompanyName:             req.CompanyName,
+		CompanyCode:             req.CompanyCode,
+		Address:                 req.Address,
+		Phone:                   req.Phone,
+		Email:                   req.Email,
+		NPWP:                    req.NPWP,
+		BankName:                req.BankName,
+		BankAccountNumber:       req.BankAccountNumber,
+		BankAccountName:         req.BankAccountName,
+		BankBranch:              req.BankBranch,
+		LogoURL:                 req.LogoURL,
+		SignatoryName:           req.SignatoryName,
+		SignatoryTitle:          req.SignatoryTitle,
+		InvoiceNumberPattern:    pattern,
+		CreditNoteNumberPattern: cnPattern,
+		ReceiptNumberPattern:    receiptPattern,
+		StampDutyThreshold:      stampDutyThreshold,
+		DefaultPPNMode:          ppnMode,
+		DefaultPPNPercentage:    req.DefaultPPNPercentage,
 	}
 
 	_, err := s.repo.Upsert(ctx, cs)
@@ -58,21 +94,27 @@ func (s *CompanySettingsService) Upsert(ctx context.Context, req *request.Upsert
 
 func toCompanySettingsResponse(cs *model.CompanySettings) *response.CompanySettingsResponse {
 	return &response.CompanySettingsResponse{
-		ID:                cs.ID,
-		CompanyName:       cs.CompanyName,
-		CompanyCode:       cs.CompanyCode,
-		Address:           cs.Address,
-		Phone:             cs.Phone,
-		Email:             cs.Email,
-		NPWP:              cs.NPWP,
-		BankName:          cs.BankName,
-		BankAccountNumber: cs.BankAccountNumber,
-		BankAccountName:   cs.BankAccountName,
-		BankBranch:        cs.BankBranch,
-		LogoURL:           cs.LogoURL,
-		SignatoryName:     cs.SignatoryName,
-		SignatoryTitle:    cs.SignatoryTitle,
-		CreatedAt:         cs.CreatedAt,
-		UpdatedAt:         cs.UpdatedAt,
+		ID:                      cs.ID,
+		CompanyName:             cs.CompanyName,
+		CompanyCode:             cs.CompanyCode,
+		Address:                 cs.Address,
+		Phone:                   cs.Phone,
+		Email:                   cs.Email,
+		NPWP:                    cs.NPWP,
+		BankName:                cs.BankName,
+		BankAccountNumber:       cs.BankAccountNumber,
+		BankAccountName:         cs.BankAccountName,
+		BankBranch:              cs.BankBranch,
+		LogoURL:                 cs.LogoURL,
+		SignatoryName:           cs.SignatoryName,
+		SignatoryTitle:          cs.SignatoryTitle,
+		InvoiceNumberPattern:    cs.InvoiceNumberPattern,
+		CreditNoteNumberPattern: cs.CreditNoteNumberPattern,
+		ReceiptNumberPattern:    cs.ReceiptNumberPattern,
+		StampDutyThreshold:      cs.StampDutyThreshold,
+		DefaultPPNMode:          string(cs.DefaultPPNMode),
+		DefaultPPNPercentage:    cs.DefaultPPNPercentage,
+		CreatedAt:               cs.CreatedAt,
+		UpdatedAt:               cs.UpdatedAt,
 	}
 }
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type CreditNoteService struct {
+	creditNoteRepo *repository.CreditNoteRepository
+	invoiceRepo    *repository.InvoiceRepository
+	auditRepo      *repository.AuditLogRepository
+	notifRepo      *repository.NotificationRepository
+	userRepo       *repository.UserRepository
+	sseHub         *sse.Hub
+}
+
+func NewCreditNoteService(
+	creditNoteRepo *repository.CreditNoteRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	auditRepo *repository.AuditLogRepository,
+	notifRepo *repository.NotificationRepository,
+	userRepo *repository.UserRepository,
+	sseHub *sse.Hub,
+) *CreditNoteService {
+	return &CreditNoteService{
+		creditNoteRepo: creditNoteRepo,
+		invoiceRepo:    invoiceRepo,
+		auditRepo:      auditRepo,
+		notifRepo:      notifRepo,
+		userRepo:       userRepo,
+		sseHub:         sseHub,
+	}
+}
+
+func (s *CreditNoteService) Create(ctx context.Context, invoiceID uint64, req *request.CreateCreditNoteRequest, userID uint64) (*response.CreditNoteResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+
+	if inv.Status != model.InvoiceStatusApproved {
+		return nil, fmt.Errorf("credit notes can only be issued for approved invoices")
+	}
+
+	creditDate, err := time.Parse("2006-01-02", req.CreditDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credit date format, use YYYY-MM-DD")
+	}
+
+	var subtotal money.Money
+	lines := make([]model.InvoiceItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		line, err := buildInvoiceLine(item)
+		if err != nil {
+			return nil, err
+		}
+		subtotal += line.Subtotal - lineDiscount(&line)
+		lines = append(lines, line)
+	}
+
+	// Credit at the PPN mode, tax rates and discount of the credited invoice.
+	// A fixed invoice discount is credited in proportion to the credited amount.
+	credit := &model.Invoice{
+		DiscountPercent: inv.DiscountPercent,
+		PPNMode:         inv.PPNMode,
+		PPNPercentage:   inv.PPNPercentage,
+		PPHPercentage:   inv.PPHPercentage,
+		PPH21Percentage: inv.PPH21Percentage,
+	}
+	if inv.DiscountPercent == 0 && inv.Subtotal > 0 {
+		credit.DiscountAmount = subtotal.MulRatio(int64(inv.DiscountAmount), int64(inv.Subtotal))
+	}
+	lineTotals, err := computeInvoiceTotals(credit, lines)
+	if err != nil {
+		return nil, err
+	}
+	if err := reconcileInvoice(credit, lineTotals); err != nil {
+		return nil, err
+	}
+
+	items := make([]model.CreditNoteItem, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, model.CreditNoteItem{
+			Description:     line.Description,
+			Quantity:        line.Quantity,
+			Unit:            line.Unit,
+			UnitPrice:       line.UnitPrice,
+			DiscountPercent: line.DiscountPercent,
+			DiscountAmount:  line.DiscountAmount,
+			Subtotal:        line.Subtotal,
+			PPNCode:         line.PPNCode,
+			PPHCode:         line.PPHCode,
+		})
+	}
+
+	cn := &model.CreditNote{
+		InvoiceID:       inv.ID,
+		CreditDate:      creditDate,
+		Reason:          req.Reason,
+		Subtotal:        credit.Subtotal,
+		DiscountAmount:  credit.DiscountAmount,
+		DPPAmount:       credit.DPPAmount,
+		PPNMode:         credit.PPNMode,
+		PPNBase:         credit.PPNBase,
+		PPNPercentage:   credit.PPNPercentage,
+		PPNAmount:       credit.PPNAmount,
+		PPHPercentage:   credit.PPHPercentage,
+		PPHAmount:       credit.PPHAmount,
+		PPH21Percentage: credit.PPH21Percentage,
+		PPH21Amount:     credit.PPH21Amount,
+		Amount:          credit.Amount,
+		CreatedBy:       userID,
+	}
+
+	id, err := s.creditNoteRepo.Create(ctx, cn, items)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		if err.Error() == "credit notes can only be issued for approved invoices" ||
+			strings.HasPrefix(err.Error(), "credit note amount") {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create credit note: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", "credit_note", id,
+		fmt.Sprintf("invoice=%s, number=%s, amount=%s, reason=%s", inv.InvoiceNumber, cn.CreditNoteNumber, cn.Amount, cn.Reason))
+	s.notifyUser(ctx, inv.CreatedBy, "Nota Kredit Diterbitkan",
+		fmt.Sprintf("Nota kredit %s senilai Rp %.0f diterbitkan untuk invoice %s", cn.CreditNoteNumber, cn.Amount.Float64(), inv.InvoiceNumber),
+		model.NotifCreditNoteCreated, inv.ID)
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *CreditNoteService) GetByID(ctx context.Context, id uint64) (*response.CreditNoteResponse, error) {
+	cn, err := s.creditNoteRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("credit note not found")
+		}
+		return nil, err
+	}
+
+	items, err := s.creditNoteRepo.FindItemsByCreditNoteID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get credit note items: %w", err)
+	}
+	cn.Items = items
+
+	resp := toCreditNoteResponse(cn)
+	if inv, err := s.invoiceRepo.FindByID(ctx, cn.InvoiceID); err == nil {
+		resp.InvoiceNumber = inv.InvoiceNumber
+	}
+	if creator, err := s.userRepo.FindByID(ctx, cn.CreatedBy); err == nil {
+		resp.CreatorName = creator.FullName
+	}
+	return &resp, nil
+}
+
+func (s *CreditNoteService) ListByInvoice(ctx context.Context, invoiceID uint64) ([]response.CreditNoteResponse, error) {
+	notes, err := s.creditNoteRepo.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.CreditNoteResponse, 0, len(notes))
+	for _, cn := range notes {
+		items, err := s.creditNoteRepo.FindItemsByCreditNoteID(ctx, cn.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get credit note items: %w", err)
+		}
+		cn.Items = items
+		result = append(result, toCreditNoteResponse(&cn))
+	}
+	return result, nil
+}
+
+func (s *CreditNoteService) logAudit(ctx context.Context, userID uint64, action, entityType string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *CreditNoteService) notifyUser(ctx context.Context, userID uint64, title, message string, notifType model.NotificationType, refID uint64) {
+	id, err := s.notifRepo.Create(ctx, &model.Notification{
+		UserID:      userID,
+		Title:       title,
+		Message:     message,
+		Type:        notifType,
+		ReferenceID: &refID,
+	})
+	if err != nil {
+		log.Printf("notification error: %v", err)
+		return
+	}
+	s.sseHub.Publish(userID, sse.Event{
+		Type: string(notifType),
+		Data: map[string]interface{}{"id": id, "title": title, "message": message, "reference_id": refID},
+	})
+}
+
+func toCreditNoteResponse(cn *model.CreditNote) response.CreditNoteResponse {
+	resp := response.CreditNoteResponse{
+		ID:               cn.ID,
+		CreditNoteNumber: cn.CreditNoteNumber,
+		InvoiceID:        cn.InvoiceID,
+		CreditDate:       cn.CreditDate.Format("2006-01-02"),
+		Reason:           cn.Reason,
+		Subtotal:         cn.Subtotal,
+		DiscountAmount:   cn.DiscountAmount,
+		DPPAmount:        cn.DPPAmount,
+		PPNMode:          string(cn.PPNMode),
+		PPNBase:          cn.PPNBase,
+		PPNPercentage:    cn.PPNPercentage,
+		PPNAmount:        cn.PPNAmount,
+		PPHPercentage:    cn.PPHPercentage,
+		PPHAmount:        cn.PPHAmount,
+		PPH21Percentage:  cn.PPH21Percentage,
+		PPH21Amount:      cn.PPH21Amount,
+		Amount:           cn.Amount,
+		CreatedBy:        cn.CreatedBy,
+		CreatedAt:        cn.CreatedAt,
+	}
+	for _, item := range cn.Items {
+		resp.Items = append(resp.Items, response.CreditNoteItemResponse{
+			ID:              item.ID,
+			Description:     item.Description,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice,
+			DiscountPercent: item.DiscountPercent,
+			DiscountAmount:  item.DiscountAmount,
+			Subtotal:        item.Subtotal,
+			PPNCode:         string(item.PPNCode),
+			PPHCode:         string(item.PPHCode),
+			SortOrder:       item.SortOrder,
+		})
+	}
+	return resp
+}
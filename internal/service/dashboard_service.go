@@ -86,8 +86,9 @@ func (s *DashboardService) GetDashboard(ctx context.Context, userID uint64, role
 			Remaining:       budgetSummary.Remaining,
 		},
 		Expenses: response.ExpenseSummary{
-			TotalExpenses: expenseSummary.TotalExpenses,
-			TotalAmount:   expenseSummary.TotalAmount,
+			TotalExpenses:   expenseSummary.TotalExpenses,
+			TotalAmount:     expenseSummary.TotalAmount,
+			PendingExpenses: expenseSummary.PendingExpenses,
 		},
 		BudgetRequests: response.BudgetRequestSummary{
 			TotalRequests:    budgetRequestSummary.TotalRequests,
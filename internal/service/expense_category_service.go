@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+type ExpenseCategoryService struct {
+	categoryRepo *repository.ExpenseCategoryRepository
+	auditRepo    *repository.AuditLogRepository
+}
+
+func NewExpenseCategoryService(categoryRepo *repository.ExpenseCategoryRepository, auditRepo *repository.AuditLogRepository) *ExpenseCategoryService {
+	return &ExpenseCategoryService{
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+func (s *ExpenseCategoryService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "expense_category",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+// normalizeCategoryCode turns a code such as "uang makan" into UANG_MAKAN.
+// Migration 000036 normalizes the legacy categories by the same rule.
+func normalizeCategoryCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), "_"))
+}
+
+func (s *ExpenseCategoryService) Create(ctx context.Context, req *request.CreateExpenseCategoryRequest, userID uint64) (*response.ExpenseCategoryResponse, error) {
+	category := &model.ExpenseCategory{
+		Code:           normalizeCategoryCode(req.Code),
+		LabelID:        strings.TrimSpace(req.LabelID),
+		LabelEN:        strings.TrimSpace(req.LabelEN),
+		ReportCategory: req.ReportCategory,
+		IsActive:       true,
+	}
+	if category.Code == "" {
+		return nil, fmt.Errorf("code is required")
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	id, err := s.categoryRepo.Create(ctx, category)
+	if err != nil {
+		if err.Error() == "expense category code already exists" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create expense category: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("code=%s, report_category=%s", category.Code, category.ReportCategory))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *ExpenseCategoryService) GetByID(ctx context.Context, id uint64) (*response.ExpenseCategoryResponse, error) {
+	category, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense category not found")
+		}
+		return nil, err
+	}
+	resp := toExpenseCategoryResponse(category)
+	return &resp, nil
+}
+
+func (s *ExpenseCategoryService) List(ctx context.Context, includeInactive bool) ([]response.ExpenseCategoryResponse, error) {
+	categories, err := s.categoryRepo.FindAll(ctx, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.ExpenseCategoryResponse, 0, len(categories))
+	for i := range categories {
+		result = append(result, toExpenseCategoryResponse(&categories[i]))
+	}
+	return result, nil
+}
+
+func (s *ExpenseCategoryService) Update(ctx context.Context, id uint64, req *request.UpdateExpenseCategoryRequest, userID uint64) (*response.ExpenseCategoryResponse, error) {
+	category, err := s.categoryRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense category not found")
+		}
+		return nil, err
+	}
+
+	if label := strings.TrimSpace(req.LabelID); label != "" {
+		category.LabelID = label
+	}
+	if label := strings.TrimSpace(req.LabelEN); label != "" {
+		category.LabelEN = label
+	}
+	if req.ReportCategory != "" {
+		category.ReportCategory = req.ReportCategory
+	}
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("update expense category: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, fmt.Sprintf("code=%s, report_category=%s, active=%t", category.Code, category.ReportCategory, category.IsActive))
+
+	return s.GetByID(ctx, id)
+}
+
+func toExpenseCategoryResponse(c *model.ExpenseCategory) response.ExpenseCategoryResponse {
+	return response.ExpenseCategoryResponse{
+		ID:             c.ID,
+		Code:           c.Code,
+		LabelID:        c.LabelID,
+		LabelEN:        c.LabelEN,
+		ReportCategory: c.ReportCategory,
+		IsActive:       c.IsActive,
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
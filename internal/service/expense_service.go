@@ -12,35 +12,42 @@ import (
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
 	"github.com/gilangrmdnii/invoice-backend/internal/repository"
 	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type ExpenseService struct {
-	expenseRepo *repository.ExpenseRepository
-	projectRepo *repository.ProjectRepository
-	memberRepo  *repository.ProjectMemberRepository
-	auditRepo   *repository.AuditLogRepository
-	notifRepo   *repository.NotificationRepository
-	userRepo    *repository.UserRepository
-	sseHub      *sse.Hub
+	expenseRepo  *repository.ExpenseRepository
+	projectRepo  *repository.ProjectRepository
+	memberRepo   *repository.ProjectMemberRepository
+	budgetRepo   *repository.BudgetRepository
+	categoryRepo *repository.ExpenseCategoryRepository
+	auditRepo    *repository.AuditLogRepository
+	notifRepo    *repository.NotificationRepository
+	userRepo     *repository.UserRepository
+	sseHub       *sse.Hub
 }
 
 func NewExpenseService(
 	expenseRepo *repository.ExpenseRepository,
 	projectRepo *repository.ProjectRepository,
 	memberRepo *repository.ProjectMemberRepository,
+	budgetRepo *repository.BudgetRepository,
+	categoryRepo *repository.ExpenseCategoryRepository,
 	auditRepo *repository.AuditLogRepository,
 	notifRepo *repository.NotificationRepository,
 	userRepo *repository.UserRepository,
 	sseHub *sse.Hub,
 ) *ExpenseService {
 	return &ExpenseService{
-		expenseRepo: expenseRepo,
-		projectRepo: projectRepo,
-		memberRepo:  memberRepo,
-		auditRepo:   auditRepo,
-		notifRepo:   notifRepo,
-		userRepo:    userRepo,
-		sseHub:      sseHub,
+		expenseRepo:  expenseRepo,
+		projectRepo:  projectRepo,
+		memberRepo:   memberRepo,
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+		notifRepo:    notifRepo,
+		userRepo:     userRepo,
+		sseHub:       sseHub,
 	}
 }
 
@@ -107,12 +114,24 @@ func (s *ExpenseService) Create(ctx context.Context, req *request.CreateExpenseR
 		}
 	}
 
+	category, err := s.resolveCategory(ctx, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checked again, under lock, when the expense is approved
+	warning, err := s.checkBudget(ctx, req.ProjectID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
 	expense := &model.Expense{
 		ProjectID:   req.ProjectID,
 		Description: req.Description,
 		Amount:      req.Amount,
-		Category:    req.Category,
+		Category:    category,
 		ReceiptURL:  req.ReceiptURL,
+		Status:      model.ExpenseStatusPending,
 		CreatedBy:   userID,
 	}
 
@@ -122,19 +141,21 @@ func (s *ExpenseService) Create(ctx context.Context, req *request.CreateExpenseR
 	}
 
 	// Audit + Notification (fire-and-forget)
-	s.logAudit(ctx, userID, "CREATE", "expense", id, fmt.Sprintf("amount=%.2f, category=%s", expense.Amount, expense.Category))
+	s.logAudit(ctx, userID, "CREATE", "expense", id, fmt.Sprintf("amount=%s, category=%s", expense.Amount, expense.Category))
 	s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Pengeluaran Baru",
-		fmt.Sprintf("Pengeluaran sebesar Rp %.0f telah dicatat", expense.Amount),
+		fmt.Sprintf("Pengeluaran sebesar Rp %.0f telah dicatat dan menunggu persetujuan", expense.Amount.Float64()),
 		model.NotifExpenseCreated, id)
 
 	return &response.ExpenseResponse{
-		ID:          id,
-		ProjectID:   expense.ProjectID,
-		Description: expense.Description,
-		Amount:      expense.Amount,
-		Category:    expense.Category,
-		ReceiptURL:  expense.ReceiptURL,
-		CreatedBy:   userID,
+		ID:            id,
+		ProjectID:     expense.ProjectID,
+		Description:   expense.Description,
+		Amount:        expense.Amount,
+		Category:      expense.Category,
+		ReceiptURL:    expense.ReceiptURL,
+		Status:        string(expense.Status),
+		CreatedBy:     userID,
+		BudgetWarning: warning,
 	}, nil
 }
 
@@ -179,6 +200,22 @@ func (s *ExpenseService) GetByID(ctx context.Context, id uint64) (*response.Expe
 		return nil, err
 	}
 	resp := toExpenseResponse(expense)
+
+	approvals, err := s.expenseRepo.FindApprovals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range approvals {
+		resp.Approvals = append(resp.Approvals, response.ExpenseApprovalResponse{
+			ID:           a.ID,
+			ApprovedBy:   a.ApprovedBy,
+			ApproverName: a.ApproverName,
+			Status:       string(a.Status),
+			Notes:        a.Notes,
+			ProofURL:     a.ProofURL,
+			CreatedAt:    a.CreatedAt,
+		})
+	}
 	return &resp, nil
 }
 
@@ -196,32 +233,40 @@ func (s *ExpenseService) Update(ctx context.Context, id uint64, req *request.Upd
 		return nil, fmt.Errorf("not authorized to update this expense")
 	}
 
+	// Approved expenses are counted in the project budget, only FINANCE and
+	// OWNER can correct them
+	if model.IsFieldRole(role) && expense.Status == model.ExpenseStatusApproved {
+		return nil, fmt.Errorf("approved expenses cannot be changed")
+	}
+
 	if req.Description != "" {
 		expense.Description = req.Description
 	}
 	if req.Amount > 0 {
 		expense.Amount = req.Amount
 	}
-	if req.Category != "" {
-		expense.Category = req.Category
+	if req.Category != "" && normalizeCategoryCode(req.Category) != expense.Category {
+		category, err := s.resolveCategory(ctx, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		expense.Category = category
 	}
 	if req.ReceiptURL != "" {
 		expense.ReceiptURL = req.ReceiptURL
 	}
 
 	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		if err.Error() == "expense exceeds project budget" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("update expense: %w", err)
 	}
 
 	// Audit
 	s.logAudit(ctx, userID, "UPDATE", "expense", id, "")
 
-	updated, err := s.expenseRepo.FindByID(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	resp := toExpenseResponse(updated)
-	return &resp, nil
+	return s.withBudgetWarning(ctx, id)
 }
 
 func (s *ExpenseService) Delete(ctx context.Context, id uint64, userID uint64, role string) error {
@@ -237,6 +282,9 @@ func (s *ExpenseService) Delete(ctx context.Context, id uint64, userID uint64, r
 	if model.IsFieldRole(role) && expense.CreatedBy != userID {
 		return fmt.Errorf("not authorized to delete this expense")
 	}
+	if model.IsFieldRole(role) && expense.Status == model.ExpenseStatusApproved {
+		return fmt.Errorf("approved expenses cannot be deleted")
+	}
 
 	if err := s.expenseRepo.Delete(ctx, id); err != nil {
 		return err
@@ -248,6 +296,146 @@ func (s *ExpenseService) Delete(ctx context.Context, id uint64, userID uint64, r
 	return nil
 }
 
+func (s *ExpenseService) Approve(ctx context.Context, id, approvedBy uint64, notes, proofURL string) (*response.ExpenseResponse, error) {
+	// Get before approve to know creator
+	expense, err := s.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		return nil, err
+	}
+
+	if err := s.expenseRepo.ApproveExpense(ctx, id, approvedBy, notes, proofURL); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		if err.Error() == "expense is not pending" || err.Error() == "expense exceeds project budget" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("approve expense: %w", err)
+	}
+
+	// Audit + Notification
+	s.logAudit(ctx, approvedBy, "APPROVE", "expense", id, fmt.Sprintf("amount=%s", expense.Amount))
+	s.notifyUser(ctx, expense.CreatedBy, "Pengeluaran Disetujui",
+		fmt.Sprintf("Pengeluaran Rp %.0f telah disetujui", expense.Amount.Float64()),
+		model.NotifExpenseApproved, id)
+
+	return s.withBudgetWarning(ctx, id)
+}
+
+func (s *ExpenseService) Reject(ctx context.Context, id, approvedBy uint64, notes, proofURL string) (*response.ExpenseResponse, error) {
+	// Get before reject to know creator
+	expense, err := s.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		return nil, err
+	}
+
+	if err := s.expenseRepo.RejectExpense(ctx, id, approvedBy, notes, proofURL); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		if err.Error() == "expense is not pending" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reject expense: %w", err)
+	}
+
+	// Audit + Notification
+	s.logAudit(ctx, approvedBy, "REJECT", "expense", id, fmt.Sprintf("notes=%s", notes))
+	s.notifyUser(ctx, expense.CreatedBy, "Pengeluaran Ditolak",
+		fmt.Sprintf("Pengeluaran Rp %.0f ditolak: %s", expense.Amount.Float64(), notes),
+		model.NotifExpenseRejected, id)
+
+	return s.GetByID(ctx, id)
+}
+
+// Resubmit sends a rejected expense, usually after it was corrected, back for
+// review. Only its creator can resubmit it.
+func (s *ExpenseService) Resubmit(ctx context.Context, id, userID uint64) (*response.ExpenseResponse, error) {
+	expense, err := s.expenseRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("expense not found")
+		}
+		return nil, err
+	}
+	if expense.CreatedBy != userID {
+		return nil, fmt.Errorf("not authorized to resubmit this expense")
+	}
+
+	if err := s.expenseRepo.Resubmit(ctx, id); err != nil {
+		if err.Error() == "expense is not rejected" {
+			return nil, err
+		}
+		return nil, fmt.Errorf("resubmit expense: %w", err)
+	}
+
+	// Audit + Notification
+	s.logAudit(ctx, userID, "RESUBMIT", "expense", id, "")
+	s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Pengeluaran Diajukan Ulang",
+		fmt.Sprintf("Pengeluaran sebesar Rp %.0f telah diajukan ulang dan menunggu persetujuan", expense.Amount.Float64()),
+		model.NotifExpenseCreated, id)
+
+	return s.GetByID(ctx, id)
+}
+
+// resolveCategory returns the code of the active expense category code
+// refers to.
+func (s *ExpenseService) resolveCategory(ctx context.Context, code string) (string, error) {
+	category, err := s.categoryRepo.FindByCode(ctx, normalizeCategoryCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("invalid expense category")
+		}
+		return "", err
+	}
+	if !category.IsActive {
+		return "", fmt.Errorf("expense category is inactive")
+	}
+	return category.Code, nil
+}
+
+// checkBudget looks at whether adding amount to the spending of a project
+// passes its total budget. Under the BLOCK policy that is an error, under
+// WARN it returns a warning.
+func (s *ExpenseService) checkBudget(ctx context.Context, projectID uint64, amount money.Money) (string, error) {
+	budget, err := s.budgetRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	spent := budget.SpentAmount + amount
+	if spent <= budget.TotalBudget {
+		return "", nil
+	}
+	if budget.OverspendPolicy == model.OverspendBlock && amount > 0 {
+		return "", fmt.Errorf("expense exceeds project budget")
+	}
+	return fmt.Sprintf("project spending of %s exceeds its total budget of %s", spent, budget.TotalBudget), nil
+}
+
+// withBudgetWarning returns an expense, with a warning when it is approved
+// and its project is over budget.
+func (s *ExpenseService) withBudgetWarning(ctx context.Context, id uint64) (*response.ExpenseResponse, error) {
+	resp, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status == string(model.ExpenseStatusApproved) {
+		if resp.BudgetWarning, err = s.checkBudget(ctx, resp.ProjectID, 0); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
 func toExpenseResponse(e *model.Expense) response.ExpenseResponse {
 	return response.ExpenseResponse{
 		ID:          e.ID,
@@ -256,6 +444,7 @@ func toExpenseResponse(e *model.Expense) response.ExpenseResponse {
 		Amount:      e.Amount,
 		Category:    e.Category,
 		ReceiptURL:  e.ReceiptURL,
+		Status:      string(e.Status),
 		CreatedBy:   e.CreatedBy,
 		CreatedAt:   e.CreatedAt,
 		UpdatedAt:   e.UpdatedAt,
@@ -135,8 +135,8 @@ func (s *FinanceReportService) Get(ctx context.Context, projectID uint64) (*resp
 				Categories: make(map[string]float64),
 			}
 		}
-		cat := normalizeCategory(a.Category)
-		memberBreakdown[a.UserID].Categories[cat] += a.Amount
+		// Already mapped through the expense category master
+		memberBreakdown[a.UserID].Categories[a.Category] += a.Amount
 		memberBreakdown[a.UserID].Total += a.Amount
 	}
 	// Add manual expenses to breakdown
@@ -351,7 +351,7 @@ func (s *FinanceReportService) buildDailyExpenses(ctx context.Context, projectID
 		SELECT DATE(e.created_at) as tgl, e.created_by, u.full_name, SUM(e.amount)
 		FROM expenses e
 		LEFT JOIN users u ON u.id = e.created_by
-		WHERE e.project_id = ?
+		WHERE e.project_id = ? AND e.status = 'APPROVED'
 		GROUP BY DATE(e.created_at), e.created_by, u.full_name
 		ORDER BY tgl ASC`, projectID)
 	if err != nil {
@@ -382,7 +382,8 @@ func (s *FinanceReportService) buildDailyExpenses(ctx context.Context, projectID
 	return out, rows.Err()
 }
 
-// normalizeCategory maps arbitrary category strings to normalized enum values
+// normalizeCategory maps the free-text categories of manual expenses to
+// normalized enum values
 func normalizeCategory(raw string) string {
 	up := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(raw), " ", "_"))
 	switch up {
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+const (
+	AgingBucketCurrent = "CURRENT"
+	AgingBucket1To30   = "1-30"
+	AgingBucket31To60  = "31-60"
+	AgingBucket61To90  = "61-90"
+	AgingBucketOver90  = "90+"
+)
+
+// Aging buckets outstanding approved invoices by days past due as of asOf.
+// Invoices without a due date are treated as due on the invoice date.
+// SPV/QC users only see invoices of projects they are a member of.
+func (s *InvoiceService) Aging(ctx context.Context, userID uint64, role string, asOf time.Time) (*response.AgingReportResponse, error) {
+	var projectIDs []uint64
+	if model.IsFieldRole(role) {
+		projects, err := s.projectRepo.FindByMemberUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		projectIDs = make([]uint64, len(projects))
+		for i, p := range projects {
+			projectIDs[i] = p.ID
+		}
+	}
+
+	invoices, err := s.invoiceRepo.FindOutstanding(ctx, projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get outstanding invoices: %w", err)
+	}
+
+	asOf = truncateDate(asOf)
+	report := &response.AgingReportResponse{
+		AsOf:        asOf.Format("2006-01-02"),
+		ByRecipient: []response.AgingGroupResponse{},
+		ByProject:   []response.AgingGroupResponse{},
+		Invoices:    make([]response.AgingInvoiceResponse, 0, len(invoices)),
+	}
+
+	recipientIdx := make(map[string]int)
+	projectIdx := make(map[uint64]int)
+	projectNames := make(map[uint64]string)
+
+	for _, inv := range invoices {
+		due := inv.InvoiceDate
+		if inv.DueDate != nil {
+			due = *inv.DueDate
+		}
+		days := int(asOf.Sub(truncateDate(due)).Hours() / 24)
+		bucket := agingBucket(days)
+		outstanding := inv.Amount - inv.PaidAmount - inv.CreditedAmount
+
+		name, ok := projectNames[inv.ProjectID]
+		if !ok {
+			if project, err := s.projectRepo.FindByID(ctx, inv.ProjectID); err == nil {
+				name = project.Name
+			}
+			projectNames[inv.ProjectID] = name
+		}
+
+		report.Invoices = append(report.Invoices, response.AgingInvoiceResponse{
+			ID:            inv.ID,
+			InvoiceNumber: inv.InvoiceNumber,
+			RecipientName: inv.RecipientName,
+			ProjectID:     inv.ProjectID,
+			ProjectName:   name,
+			InvoiceDate:   inv.InvoiceDate.Format("2006-01-02"),
+			DueDate:       due.Format("2006-01-02"),
+			DaysPastDue:   max(days, 0),
+			Bucket:        bucket,
+			Amount:        inv.Amount,
+			Outstanding:   outstanding,
+		})
+		addToBucket(&report.Totals, bucket, outstanding)
+
+		// Invoices linked to a client group by client; older ones by recipient name
+		key := strings.ToUpper(strings.TrimSpace(inv.RecipientName))
+		if inv.ClientID != nil {
+			key = fmt.Sprintf("client:%d", *inv.ClientID)
+		}
+		i, ok := recipientIdx[key]
+		if !ok {
+			i = len(report.ByRecipient)
+			recipientIdx[key] = i
+			report.ByRecipient = append(report.ByRecipient, response.AgingGroupResponse{Name: inv.RecipientName})
+		}
+		report.ByRecipient[i].InvoiceCount++
+		addToBucket(&report.ByRecipient[i].Buckets, bucket, outstanding)
+
+		j, ok := projectIdx[inv.ProjectID]
+		if !ok {
+			j = len(report.ByProject)
+			projectIdx[inv.ProjectID] = j
+			report.ByProject = append(report.ByProject, response.AgingGroupResponse{ProjectID: inv.ProjectID, Name: name})
+		}
+		report.ByProject[j].InvoiceCount++
+		addToBucket(&report.ByProject[j].Buckets, bucket, outstanding)
+	}
+
+	// Largest exposure first
+	sortGroups := func(groups []response.AgingGroupResponse) {
+		sort.SliceStable(groups, func(a, b int) bool {
+			return groups[a].Buckets.Total > groups[b].Buckets.Total
+		})
+	}
+	sortGroups(report.ByRecipient)
+	sortGroups(report.ByProject)
+
+	return report, nil
+}
+
+// AgingCSV renders the aging report as CSV grouped by "recipient" or "project",
+// followed by a grand total row.
+func AgingCSV(report *response.AgingReportResponse, groupBy string) ([]byte, error) {
+	groups := report.ByRecipient
+	label := "Recipient"
+	if groupBy == "project" {
+		groups = report.ByProject
+		label = "Project"
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{label, "Invoices", "Current", "1-30", "31-60", "61-90", "90+", "Total"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	row := func(name string, count int, b response.AgingBuckets) []string {
+		return []string{
+			name,
+			fmt.Sprintf("%d", count),
+			b.Current.String(),
+			b.Days1To30.String(),
+			b.Days31To60.String(),
+			b.Days61To90.String(),
+			b.Over90.String(),
+			b.Total.String(),
+		}
+	}
+	for _, g := range groups {
+		if err := w.Write(row(g.Name, g.InvoiceCount, g.Buckets)); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Write(row("TOTAL", len(report.Invoices), report.Totals)); err != nil {
+		return nil, err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func agingBucket(daysPastDue int) string {
+	switch {
+	case daysPastDue <= 0:
+		return AgingBucketCurrent
+	case daysPastDue <= 30:
+		return AgingBucket1To30
+	case daysPastDue <= 60:
+		return AgingBucket31To60
+	case daysPastDue <= 90:
+		return AgingBucket61To90
+	default:
+		return AgingBucketOver90
+	}
+}
+
+func addToBucket(b *response.AgingBuckets, bucket string, amount money.Money) {
+	switch bucket {
+	case AgingBucketCurrent:
+		b.Current += amount
+	case AgingBucket1To30:
+		b.Days1To30 += amount
+	case AgingBucket31To60:
+		b.Days31To60 += amount
+	case AgingBucket61To90:
+		b.Days61To90 += amount
+	default:
+		b.Over90 += amount
+	}
+	b.Total += amount
+}
+
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
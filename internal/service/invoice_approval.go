@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// ListApprovals returns the approval steps of every round of an invoice, so
+// earlier rejections stay visible after the invoice is resubmitted.
+func (s *InvoiceService) ListApprovals(ctx context.Context, invoiceID uint64, userID uint64, role string) ([]response.InvoiceApprovalStepResponse, error) {
+	if err := s.checkRevisionAccess(ctx, invoiceID, userID, role); err != nil {
+		return nil, err
+	}
+
+	steps, err := s.invoiceRepo.FindApprovalSteps(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.InvoiceApprovalStepResponse, 0, len(steps))
+	for i := range steps {
+		result = append(result, toInvoiceApprovalStepResponse(&steps[i]))
+	}
+	return result, nil
+}
+
+func toInvoiceApprovalStepResponse(st *model.InvoiceApprovalStep) response.InvoiceApprovalStepResponse {
+	return response.InvoiceApprovalStepResponse{
+		ID:            st.ID,
+		Round:         st.Round,
+		StepOrder:     st.StepOrder,
+		PolicyID:      st.PolicyID,
+		ApproverRoles: st.ApproverRoles,
+		Status:        string(st.Status),
+		ActedBy:       st.ActedBy,
+		ActorName:     st.ActorName,
+		ActedAt:       st.ActedAt,
+		Notes:         st.Notes,
+	}
+}
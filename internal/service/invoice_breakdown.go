@@ -0,0 +1,106 @@
+package service
+
+import (
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// invoiceItemsBreakdown returns the responses of the stored items of an
+// invoice, in stored order, with their computed discount, DPP, tax and net
+// amounts, and the breakdown of those amounts per label and per tax. The
+// breakdown is nil when the stored figures can no longer be computed.
+func invoiceItemsBreakdown(inv *model.Invoice, items []model.InvoiceItem) ([]response.InvoiceItemResponse, *response.InvoiceBreakdownResponse) {
+	result := make([]response.InvoiceItemResponse, len(items))
+	for i, item := range items {
+		result[i] = response.InvoiceItemResponse{
+			ID:              item.ID,
+			InvoiceID:       item.InvoiceID,
+			ParentID:        item.ParentID,
+			IsLabel:         item.IsLabel,
+			Description:     item.Description,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice,
+			DiscountPercent: item.DiscountPercent,
+			DiscountAmount:  item.DiscountAmount,
+			Subtotal:        item.Subtotal,
+			SortOrder:       item.SortOrder,
+		}
+		if !item.IsLabel {
+			result[i].PPNCode = string(item.PPNCode)
+			result[i].PPHCode = string(item.PPHCode)
+		}
+	}
+
+	calc := *inv
+	lines, err := computeInvoiceTotals(&calc, model.NestInvoiceItems(items))
+	if err != nil {
+		return result, nil
+	}
+
+	index := make(map[uint64]int, len(items))
+	for i, item := range items {
+		index[item.ID] = i
+	}
+	add := func(r *response.InvoiceItemResponse, l *invoiceLineTotals) {
+		r.InvoiceDiscount += l.invoiceDiscount
+		r.DPPAmount += l.dpp
+		r.PPNAmount += l.ppn
+		r.PPHAmount += l.pph
+		r.NetAmount += l.net()
+	}
+
+	breakdown := &response.InvoiceBreakdownResponse{
+		Groups: []response.InvoiceGroupTotalsResponse{},
+		Taxes:  []response.InvoiceTaxTotalsResponse{},
+	}
+	groups := make(map[uint64]int) // label id, or 0 for lines outside a label
+	for i := range lines {
+		l := &lines[i]
+		line := &result[index[l.item.ID]]
+		line.DiscountAmount = l.item.DiscountAmount
+		add(line, l)
+
+		var key uint64
+		if l.label != nil {
+			key = l.label.ID
+			r := &result[index[key]]
+			r.DiscountAmount += l.item.DiscountAmount
+			add(r, l)
+		}
+		g, ok := groups[key]
+		if !ok {
+			group := response.InvoiceGroupTotalsResponse{}
+			if l.label != nil {
+				id := l.label.ID
+				group.LabelID = &id
+				group.Description = l.label.Description
+			}
+			breakdown.Groups = append(breakdown.Groups, group)
+			g = len(breakdown.Groups) - 1
+			groups[key] = g
+		}
+		group := &breakdown.Groups[g]
+		group.Subtotal += l.item.Subtotal
+		group.LineDiscount += l.item.DiscountAmount
+		group.InvoiceDiscount += l.invoiceDiscount
+		group.DPPAmount += l.dpp
+		group.PPNAmount += l.ppn
+		group.PPHAmount += l.pph
+		group.NetAmount += l.net()
+	}
+
+	pph23Base, _ := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPHCode == model.PPHCode23 })
+	pph21Base, _ := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPHCode == model.PPHCode21 })
+	taxes := []response.InvoiceTaxTotalsResponse{
+		{Code: "PPN", Mode: string(calc.PPNMode), Base: calc.PPNBase, Percentage: calc.PPNPercentage, Amount: calc.PPNAmount},
+		{Code: "PPH23", Base: pph23Base, Percentage: calc.PPHPercentage, Amount: calc.PPHAmount},
+		{Code: "PPH21", Base: pph21Base, Percentage: calc.PPH21Percentage, Amount: calc.PPH21Amount},
+	}
+	for _, t := range taxes {
+		if t.Percentage != 0 {
+			breakdown.Taxes = append(breakdown.Taxes, t)
+		}
+	}
+	return result, breakdown
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// findBillableClient loads a client that invoices can be issued to.
+func (s *InvoiceService) findBillableClient(ctx context.Context, id uint64) (*model.Client, error) {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("client not found")
+		}
+		return nil, err
+	}
+	if !client.IsActive {
+		return nil, fmt.Errorf("client is inactive")
+	}
+	return client, nil
+}
+
+// applyClientSnapshot copies the client's billing identity onto the invoice.
+// The invoice keeps these values even if the client is edited later. Attention
+// defaults to the client's primary contact.
+func applyClientSnapshot(inv *model.Invoice, client *model.Client) {
+	inv.ClientID = &client.ID
+	inv.RecipientName = client.Name
+	inv.RecipientAddress = client.BillingAddress
+	inv.RecipientNPWP = client.NPWP
+	inv.Attention = ""
+	if contact := client.PrimaryContact(); contact != nil {
+		inv.Attention = contact.Name
+	}
+}
+
+// clientDueDate returns the invoice date plus the client's payment terms.
+func clientDueDate(inv *model.Invoice, client *model.Client) *time.Time {
+	due := inv.InvoiceDate.AddDate(0, 0, client.PaymentTermsDays)
+	return &due
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// Clone copies an invoice of any status into a new PENDING draft with a
+// fresh date and draft number. Payments, credit notes, the attached file and
+// the billing term link are not copied. Unless a due date is given the copy
+// keeps the original payment term.
+func (s *InvoiceService) Clone(ctx context.Context, id uint64, req *request.CloneInvoiceRequest, userID uint64, role string) (*response.InvoiceResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+	if err := s.checkInvoiceAccess(ctx, inv, userID, role); err != nil {
+		return nil, err
+	}
+
+	items, err := s.invoiceRepo.FindItemsByInvoiceID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get invoice items: %w", err)
+	}
+
+	invoiceDate := truncateDate(time.Now())
+	if req.InvoiceDate != "" {
+		invoiceDate, err = time.Parse("2006-01-02", req.InvoiceDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid invoice date format, use YYYY-MM-DD")
+		}
+	}
+	dueDate := req.DueDate
+	if dueDate == "" && inv.DueDate != nil {
+		term := inv.DueDate.Sub(inv.InvoiceDate)
+		dueDate = invoiceDate.Add(term).Format("2006-01-02")
+	}
+
+	invReq := &request.CreateInvoiceRequest{
+		ProjectID:        inv.ProjectID,
+		InvoiceType:      string(inv.InvoiceType),
+		ClientID:         inv.ClientID,
+		RecipientName:    inv.RecipientName,
+		RecipientAddress: inv.RecipientAddress,
+		RecipientNPWP:    inv.RecipientNPWP,
+		Attention:        inv.Attention,
+		PONumber:         inv.PONumber,
+		InvoiceDate:      invoiceDate.Format("2006-01-02"),
+		DueDate:          dueDate,
+		DPPercentage:     inv.DPPercentage,
+		DiscountPercent:  inv.DiscountPercent,
+		PPNMode:          string(inv.PPNMode),
+		PPNPercentage:    &inv.PPNPercentage,
+		PPHPercentage:    inv.PPHPercentage,
+		PPH21Percentage:  inv.PPH21Percentage,
+		Notes:            inv.Notes,
+		Language:         inv.Language,
+	}
+	if inv.DiscountPercent == 0 {
+		invReq.DiscountAmount = inv.DiscountAmount
+	}
+	if invReq.ClientID == nil {
+		// Keep the copy addressed like the original rather than falling back
+		// to the project's client
+		var none uint64
+		invReq.ClientID = &none
+	}
+
+	// Reuse the snapshot nesting so labels keep their children in order
+	snapshot := model.NewInvoiceSnapshot(inv, items)
+	for _, it := range snapshot.Items {
+		if !it.IsLabel {
+			invReq.Items = append(invReq.Items, snapshotItemRequest(it))
+			continue
+		}
+		label := request.InvoiceLabelRequest{Description: it.Description}
+		for _, child := range it.Children {
+			label.Items = append(label.Items, snapshotItemRequest(child))
+		}
+		invReq.Labels = append(invReq.Labels, label)
+	}
+
+	result, err := s.create(ctx, invReq, nil, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "CLONE", result.ID, fmt.Sprintf("source=%d, number=%s", id, inv.InvoiceNumber))
+
+	return result, nil
+}
+
+func snapshotItemRequest(it model.InvoiceSnapshotItem) request.InvoiceItemRequest {
+	req := request.InvoiceItemRequest{
+		Description:     it.Description,
+		Quantity:        it.Quantity,
+		Unit:            it.Unit,
+		UnitPrice:       it.UnitPrice,
+		DiscountPercent: it.DiscountPercent,
+		PPNCode:         string(it.PPNCode),
+		PPHCode:         string(it.PPHCode),
+	}
+	// A percentage discount is recomputed rather than copied as an amount
+	if it.DiscountPercent == 0 {
+		req.DiscountAmount = it.DiscountAmount
+	}
+	return req
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	netmail "net/mail"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/mail"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/pdf"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+type InvoiceDeliveryService struct {
+	invoiceService *InvoiceService
+	invoiceRepo    *repository.InvoiceRepository
+	deliveryRepo   *repository.InvoiceDeliveryRepository
+	companyRepo    *repository.CompanySettingsRepository
+	projectRepo    *repository.ProjectRepository
+	userRepo       *repository.UserRepository
+	auditRepo      *repository.AuditLogRepository
+	mailer         mail.Sender
+	from           netmail.Address
+}
+
+// NewInvoiceDeliveryService creates the delivery service. mailer may be nil
+// when SMTP is not configured; sending then fails with a clear error.
+func NewInvoiceDeliveryService(
+	invoiceService *InvoiceService,
+	invoiceRepo *repository.InvoiceRepository,
+	deliveryRepo *repository.InvoiceDeliveryRepository,
+	companyRepo *repository.CompanySettingsRepository,
+	projectRepo *repository.ProjectRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AuditLogRepository,
+	mailer mail.Sender,
+	from netmail.Address,
+) *InvoiceDeliveryService {
+	return &InvoiceDeliveryService{
+		invoiceService: invoiceService,
+		invoiceRepo:    invoiceRepo,
+		deliveryRepo:   deliveryRepo,
+		companyRepo:    companyRepo,
+		projectRepo:    projectRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		mailer:         mailer,
+		from:           from,
+	}
+}
+
+// Send emails the invoice PDF to the given recipients and records the attempt.
+// A failed SMTP delivery is still recorded with status FAILED.
+func (s *InvoiceDeliveryService) Send(ctx context.Context, invoiceID uint64, req *request.SendInvoiceRequest, userID uint64, role string) (*response.InvoiceDeliveryResponse, error) {
+	if s.mailer == nil {
+		return nil, fmt.Errorf("email delivery is not configured")
+	}
+
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+	if inv.Status != model.InvoiceStatusApproved {
+		return nil, fmt.Errorf("only approved invoices can be sent")
+	}
+
+	content, filename, err := s.invoiceService.RenderPDF(ctx, invoiceID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	data := mail.InvoiceEmailData{
+		RecipientName: inv.RecipientName,
+		Attention:     inv.Attention,
+		InvoiceNumber: inv.InvoiceNumber,
+		InvoiceDate:   pdf.FormatDate(inv.InvoiceDate, inv.Language),
+		Amount:        pdf.FormatAmount(inv.Amount-inv.PaidAmount-inv.CreditedAmount, inv.Language),
+		Message:       req.Message,
+	}
+	if inv.DueDate != nil {
+		data.DueDate = pdf.FormatDate(*inv.DueDate, inv.Language)
+	}
+	if project, err := s.projectRepo.FindByID(ctx, inv.ProjectID); err == nil {
+		data.ProjectName = project.Name
+	}
+	from := s.from
+	if cs, err := s.companyRepo.Get(ctx); err == nil {
+		data.CompanyName = cs.CompanyName
+		data.BankName = cs.BankName
+		data.BankAccountNumber = cs.BankAccountNumber
+		data.BankAccountName = cs.BankAccountName
+		if from.Name == "" {
+			from.Name = cs.CompanyName
+		}
+	}
+
+	subject, text, html, err := mail.RenderInvoiceEmail(inv.Language, data)
+	if err != nil {
+		return nil, err
+	}
+	if req.Subject != "" {
+		subject = req.Subject
+	}
+
+	delivery := &model.InvoiceDelivery{
+		InvoiceID:  invoiceID,
+		Channel:    "EMAIL",
+		Recipients: req.To,
+		Cc:         req.Cc,
+		Subject:    subject,
+		Status:     model.DeliveryStatusPending,
+		SentBy:     userID,
+	}
+	id, err := s.deliveryRepo.Create(ctx, delivery)
+	if err != nil {
+		return nil, err
+	}
+
+	sendErr := s.mailer.Send(ctx, &mail.Message{
+		From:    from,
+		To:      req.To,
+		Cc:      req.Cc,
+		Subject: subject,
+		Text:    text,
+		HTML:    html,
+		Attachments: []mail.Attachment{
+			{Filename: filename, ContentType: "application/pdf", Data: content},
+		},
+	})
+
+	status, errMsg := model.DeliveryStatusSent, ""
+	if sendErr != nil {
+		status, errMsg = model.DeliveryStatusFailed, sendErr.Error()
+		log.Printf("invoice %d delivery %d failed: %v", invoiceID, id, sendErr)
+	}
+	if err := s.deliveryRepo.UpdateStatus(ctx, id, status, errMsg); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "SEND", invoiceID,
+		fmt.Sprintf("delivery=%d, status=%s, to=%v, cc=%v", id, status, req.To, req.Cc))
+
+	result, err := s.getDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sendErr != nil {
+		return result, fmt.Errorf("email delivery failed")
+	}
+	return result, nil
+}
+
+func (s *InvoiceDeliveryService) ListByInvoice(ctx context.Context, invoiceID uint64) ([]response.InvoiceDeliveryResponse, error) {
+	deliveries, err := s.deliveryRepo.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.InvoiceDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, s.toDeliveryResponse(ctx, &d))
+	}
+	return result, nil
+}
+
+func (s *InvoiceDeliveryService) getDelivery(ctx context.Context, id uint64) (*response.InvoiceDeliveryResponse, error) {
+	d, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := s.toDeliveryResponse(ctx, d)
+	return &resp, nil
+}
+
+func (s *InvoiceDeliveryService) toDeliveryResponse(ctx context.Context, d *model.InvoiceDelivery) response.InvoiceDeliveryResponse {
+	resp := response.InvoiceDeliveryResponse{
+		ID:           d.ID,
+		InvoiceID:    d.InvoiceID,
+		Channel:      d.Channel,
+		Recipients:   d.Recipients,
+		Cc:           d.Cc,
+		Subject:      d.Subject,
+		Status:       string(d.Status),
+		ErrorMessage: d.ErrorMessage,
+		SentBy:       d.SentBy,
+		SentAt:       d.SentAt,
+		CreatedAt:    d.CreatedAt,
+	}
+	if sender, err := s.userRepo.FindByID(ctx, d.SentBy); err == nil {
+		resp.SenderName = sender.FullName
+	}
+	return resp
+}
+
+func (s *InvoiceDeliveryService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "invoice",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
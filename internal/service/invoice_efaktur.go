@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// e-Faktur transaction codes (kode jenis transaksi)
+const (
+	efakturCodeStandard  = "01" // PPN on the DPP
+	efakturCodeNilaiLain = "04" // PPN on a DPP nilai lain
+)
+
+// The three header rows of the e-Faktur output tax import file. Every
+// exported invoice is written as one FK row, one LT row with the buyer and one
+// OF row per line.
+var efakturHeader = [][]string{
+	{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR",
+		"NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN",
+		"FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"},
+	{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN",
+		"PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+	{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN",
+		"TARIF_PPNBM", "PPNBM"},
+}
+
+// EFakturExport builds the e-Faktur import file for the approved invoices
+// dated within the range, and a report of the exported invoices and of the
+// ones that were left out with the reasons why. Lines exempt from PPN are not
+// exported, and the DPP of the FK and OF rows is the PPN base, so invoices in
+// the DPP nilai lain mode report the 11/12 base. Invoices need a tax invoice
+// number (NSFP) to be exported, and the invoice number is given as the
+// reference.
+func (s *InvoiceService) EFakturExport(ctx context.Context, req *request.EFakturExportRequest) (*response.EFakturExportResponse, []byte, error) {
+	from, err := time.Parse("2006-01-02", req.DateFrom)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", req.DateTo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+	}
+	if to.Before(from) {
+		return nil, nil, fmt.Errorf("date_to must not be before date_from")
+	}
+
+	company, err := s.companyRepo.Get(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, fmt.Errorf("get company settings: %w", err)
+	}
+	if company == nil || company.NPWP == "" {
+		return nil, nil, fmt.Errorf("company NPWP is not set")
+	}
+	sellerNPWP, err := normalizeNPWP(company.NPWP)
+	if err != nil {
+		return nil, nil, fmt.Errorf("company NPWP is invalid")
+	}
+
+	invoices, err := s.invoiceRepo.FindApprovedByDate(ctx, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get approved invoices: %w", err)
+	}
+
+	report := &response.EFakturExportResponse{
+		DateFrom:   from.Format("2006-01-02"),
+		DateTo:     to.Format("2006-01-02"),
+		SellerNPWP: sellerNPWP,
+		Exported:   []response.EFakturInvoiceResponse{},
+		Skipped:    []response.EFakturSkippedResponse{},
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(efakturHeader); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range invoices {
+		inv := &invoices[i]
+		items, err := s.invoiceRepo.FindItemsByInvoiceID(ctx, inv.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get invoice items: %w", err)
+		}
+
+		rows, reasons := efakturRows(inv, items)
+		if len(reasons) > 0 {
+			report.Skipped = append(report.Skipped, response.EFakturSkippedResponse{
+				ID:            inv.ID,
+				InvoiceNumber: inv.InvoiceNumber,
+				Reasons:       reasons,
+			})
+			continue
+		}
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				return nil, nil, err
+			}
+		}
+		report.Exported = append(report.Exported, response.EFakturInvoiceResponse{
+			ID:              inv.ID,
+			InvoiceNumber:   inv.InvoiceNumber,
+			InvoiceDate:     inv.InvoiceDate.Format("2006-01-02"),
+			RecipientName:   inv.RecipientName,
+			RecipientNPWP:   rows[0][7],
+			TransactionCode: rows[0][1],
+			DPPAmount:       inv.PPNBase,
+			PPNAmount:       inv.PPNAmount,
+			LineCount:       len(rows) - 2,
+		})
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, nil, err
+	}
+	return report, buf.Bytes(), nil
+}
+
+// efakturRows returns the FK, LT and OF rows of an invoice, or the reasons it
+// cannot be exported.
+func efakturRows(inv *model.Invoice, items []model.InvoiceItem) ([][]string, []string) {
+	var reasons []string
+	npwp, err := normalizeNPWP(inv.RecipientNPWP)
+	switch {
+	case err != nil:
+		reasons = append(reasons, "recipient NPWP is invalid")
+	case npwp == "":
+		reasons = append(reasons, "recipient NPWP is missing")
+	}
+	if strings.TrimSpace(inv.RecipientName) == "" {
+		reasons = append(reasons, "recipient name is missing")
+	}
+	if strings.TrimSpace(inv.RecipientAddress) == "" {
+		reasons = append(reasons, "recipient address is missing")
+	}
+	if inv.PPNAmount <= 0 {
+		reasons = append(reasons, "no PPN is charged")
+	}
+	if inv.TaxInvoiceNumber == "" {
+		reasons = append(reasons, "tax invoice number (NSFP) is not set")
+	}
+
+	calc := *inv
+	lines, err := computeInvoiceTotals(&calc, model.NestInvoiceItems(items))
+	if err != nil {
+		reasons = append(reasons, "invoice totals cannot be computed: "+err.Error())
+	} else if calc.PPNBase != inv.PPNBase || calc.PPNAmount != inv.PPNAmount {
+		reasons = append(reasons, "invoice items do not add up to the stored PPN")
+	}
+	if len(reasons) > 0 {
+		return nil, reasons
+	}
+
+	code := efakturCodeStandard
+	if inv.PPNMode == model.PPNModeNilaiLain {
+		code = efakturCodeNilaiLain
+	}
+	// A down payment invoice is flagged as such, with its whole DPP and PPN
+	downPayment := []string{"0", "0", "0", "0"}
+	if inv.InvoiceType == model.InvoiceTypeDP {
+		downPayment = []string{"1", efakturRupiah(inv.PPNBase), efakturRupiah(inv.PPNAmount), "0"}
+	}
+	address := strings.Join(strings.Fields(inv.RecipientAddress), " ")
+
+	fk := []string{"FK", code, "0", inv.TaxInvoiceNumber,
+		strconv.Itoa(int(inv.InvoiceDate.Month())), strconv.Itoa(inv.InvoiceDate.Year()), inv.InvoiceDate.Format("02/01/2006"),
+		npwp, inv.RecipientName, address, efakturRupiah(inv.PPNBase), efakturRupiah(inv.PPNAmount), "0", ""}
+	fk = append(fk, downPayment...)
+	fk = append(fk, inv.InvoiceNumber, "")
+	rows := [][]string{
+		fk,
+		{"LT", npwp, inv.RecipientName, address, "", "", "", "", "", "", "", "", "", ""},
+	}
+
+	// Spread the PPN base over the taxable lines like the PPN itself
+	_, weights := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPNCode != model.PPNCodeExempt })
+	bases := spread(calc.PPNBase, weights)
+	for i, l := range lines {
+		if l.item.PPNCode == model.PPNCodeExempt {
+			continue
+		}
+		rows = append(rows, []string{"OF", "", l.item.Description,
+			l.item.UnitPrice.String(), strconv.FormatFloat(l.item.Quantity, 'f', -1, 64),
+			l.item.Subtotal.String(), (l.item.DiscountAmount + l.invoiceDiscount).String(),
+			bases[i].String(), l.ppn.String(), "0", "0"})
+	}
+	return rows, nil
+}
+
+// efakturRupiah formats an amount of the FK row, which takes whole rupiah:
+// the cents are dropped.
+func efakturRupiah(m money.Money) string {
+	return strconv.FormatInt(int64(m)/100, 10)
+}
+
+// SetTaxInvoiceNumber records the tax invoice number (NSFP) of an approved
+// invoice for the e-Faktur export, or clears it.
+func (s *InvoiceService) SetTaxInvoiceNumber(ctx context.Context, id uint64, req *request.SetTaxInvoiceNumberRequest, userID uint64) (*response.InvoiceResponse, error) {
+	number, err := normalizeNSFP(req.TaxInvoiceNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.SetTaxInvoiceNumber(ctx, id, number); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		switch err.Error() {
+		case "tax invoice numbers can only be set on approved invoices",
+			"tax invoice number is already used by another invoice":
+			return nil, err
+		}
+		return nil, fmt.Errorf("set tax invoice number: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, fmt.Sprintf("tax_invoice_number=%s", number))
+	return s.GetByID(ctx, id)
+}
+
+// normalizeNSFP strips the separators from a tax invoice number and checks
+// that 13 digits remain: the branch code, the year and the serial number.
+// The transaction code and replacement flag are not part of it.
+func normalizeNSFP(nsfp string) (string, error) {
+	var digits strings.Builder
+	for _, r := range nsfp {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '.' || r == '-' || r == ' ':
+		default:
+			return "", fmt.Errorf("invalid tax invoice number, must be 13 digits")
+		}
+	}
+	if n := digits.Len(); n != 0 && n != 13 {
+		return "", fmt.Errorf("invalid tax invoice number, must be 13 digits")
+	}
+	return digits.String(), nil
+}
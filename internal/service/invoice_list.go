@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+)
+
+const (
+	defaultInvoicePageSize = 20
+	maxInvoicePageSize     = 100
+)
+
+// invoiceCursor is the decoded form of the opaque next_cursor token. It
+// carries the sort it was issued for, so a cursor cannot be replayed against
+// a different ordering.
+type invoiceCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    uint64 `json:"id"`
+}
+
+// List returns one page of invoices matching the request filters, newest
+// first unless another sort is given. SPV/QC users only see invoices of
+// projects they are a member of.
+func (s *InvoiceService) List(ctx context.Context, req *request.ListInvoicesRequest, userID uint64, role string) (*response.InvoiceListResponse, error) {
+	filter, err := buildInvoiceFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if model.IsFieldRole(role) {
+		projects, err := s.projectRepo.FindByMemberUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		filter.ProjectIDs = make([]uint64, len(projects))
+		for i, p := range projects {
+			filter.ProjectIDs[i] = p.ID
+		}
+	}
+
+	invoices, next, err := s.invoiceRepo.FindPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list invoices: %w", err)
+	}
+	total, err := s.invoiceRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("count invoices: %w", err)
+	}
+
+	result := &response.InvoiceListResponse{
+		Items: make([]response.InvoiceResponse, 0, len(invoices)),
+		Total: total,
+		Limit: filter.Limit,
+	}
+	for i := range invoices {
+		result.Items = append(result.Items, toInvoiceResponse(&invoices[i]))
+	}
+	if next != nil {
+		result.NextCursor = encodeInvoiceCursor(&invoiceCursor{
+			Sort: filter.Sort, Order: orderName(filter.Desc), Value: next.Value, ID: next.ID,
+		})
+	}
+	return result, nil
+}
+
+func buildInvoiceFilter(req *request.ListInvoicesRequest) (*repository.InvoiceFilter, error) {
+	f := &repository.InvoiceFilter{
+		ProjectID:     req.ProjectID,
+		Status:        model.InvoiceStatus(req.Status),
+		PaymentStatus: model.PaymentStatus(req.PaymentStatus),
+		InvoiceType:   model.InvoiceType(req.InvoiceType),
+		Recipient:     strings.TrimSpace(req.Recipient),
+		Search:        strings.TrimSpace(req.Search),
+		Sort:          req.Sort,
+		Desc:          req.Order != "asc",
+		Limit:         req.Limit,
+	}
+	if f.Sort == "" {
+		f.Sort = "created_at"
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultInvoicePageSize
+	}
+	if f.Limit > maxInvoicePageSize {
+		f.Limit = maxInvoicePageSize
+	}
+
+	dates := []struct {
+		value string
+		dest  **time.Time
+	}{
+		{req.DateFrom, &f.DateFrom},
+		{req.DateTo, &f.DateTo},
+		{req.DueFrom, &f.DueFrom},
+		{req.DueTo, &f.DueTo},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format, use YYYY-MM-DD")
+		}
+		*d.dest = &t
+	}
+
+	if req.Cursor != "" {
+		c, err := decodeInvoiceCursor(req.Cursor)
+		if err != nil || c.Sort != f.Sort || c.Order != orderName(f.Desc) {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		f.After = &repository.InvoiceCursor{Value: c.Value, ID: c.ID}
+	}
+	return f, nil
+}
+
+func orderName(desc bool) string {
+	if desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+func encodeInvoiceCursor(c *invoiceCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeInvoiceCursor(token string) (*invoiceCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var c invoiceCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
@@ -13,6 +14,7 @@ import (
 	"github.com/gilangrmdnii/invoice-backend/internal/model"
 	"github.com/gilangrmdnii/invoice-backend/internal/repository"
 	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
 )
 
 type InvoicePaymentService struct {
@@ -60,12 +62,6 @@ func (s *InvoicePaymentService) Create(ctx context.Context, req *request.CreateI
 		return nil, fmt.Errorf("invoice is already fully paid")
 	}
 
-	// Validate payment amount doesn't exceed remaining
-	remaining := inv.Amount - inv.PaidAmount
-	if req.Amount > remaining {
-		return nil, fmt.Errorf("payment amount (%.2f) exceeds remaining balance (%.2f)", req.Amount, remaining)
-	}
-
 	paymentDate, err := time.Parse("2006-01-02", req.PaymentDate)
 	if err != nil {
 		return nil, fmt.Errorf("invalid payment date format, use YYYY-MM-DD")
@@ -81,44 +77,129 @@ func (s *InvoicePaymentService) Create(ctx context.Context, req *request.CreateI
 		CreatedBy:     userID,
 	}
 
-	id, err := s.paymentRepo.Create(ctx, payment)
+	// The amount is checked against the remaining balance under the invoice lock
+	id, change, err := s.paymentRepo.Create(ctx, payment)
 	if err != nil {
+		if isPaymentRuleError(err) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("create payment: %w", err)
 	}
 
 	// Audit log
 	s.logAudit(ctx, userID, "CREATE", "invoice_payment", id,
-		fmt.Sprintf("invoice=%s, amount=%.2f, method=%s", inv.InvoiceNumber, req.Amount, req.PaymentMethod))
+		fmt.Sprintf("invoice=%s, amount=%s, method=%s, %s", inv.InvoiceNumber, req.Amount, req.PaymentMethod, balanceDetails(change)))
 
-	// Notify invoice creator
-	newPaid := inv.PaidAmount + req.Amount
+	s.notifyPaymentRecorded(ctx, inv, req.Amount, change)
+
+	payment.ID = id
+	payment.EntryType = model.PaymentEntryPayment
+	payment.CreatedAt = time.Now()
+	return s.toPaymentResponse(ctx, payment, change), nil
+}
+
+// notifyPaymentRecorded tells the invoice creator that a payment was recorded.
+func (s *InvoicePaymentService) notifyPaymentRecorded(ctx context.Context, inv *model.Invoice, amount money.Money, change *repository.BalanceChange) {
 	statusLabel := "Bayar Sebagian"
-	if newPaid >= inv.Amount {
+	if change.After.PaymentStatus == model.PaymentStatusPaid {
 		statusLabel = "Lunas"
 	}
 	s.notifyUser(ctx, inv.CreatedBy, "Pembayaran Dicatat",
-		fmt.Sprintf("Pembayaran Rp %.0f untuk invoice %s (%s)", req.Amount, inv.InvoiceNumber, statusLabel),
+		fmt.Sprintf("Pembayaran Rp %.0f untuk invoice %s (%s)", amount.Float64(), inv.InvoiceNumber, statusLabel),
 		model.NotifInvoiceApproved, inv.ID)
+}
 
-	// Get creator name
-	creator, _ := s.userRepo.FindByID(ctx, userID)
-	creatorName := ""
-	if creator != nil {
-		creatorName = creator.FullName
+// Reverse records a reversal of a payment, e.g. when a transfer is returned or
+// a giro bounces. The original payment stays on record.
+func (s *InvoicePaymentService) Reverse(ctx context.Context, invoiceID, paymentID uint64, req *request.ReverseInvoicePaymentRequest, userID uint64) (*response.InvoicePaymentResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+
+	reversalDate, err := time.Parse("2006-01-02", req.ReversalDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reversal date format, use YYYY-MM-DD")
+	}
+
+	reversal := &model.InvoicePayment{
+		InvoiceID:         invoiceID,
+		ReversedPaymentID: &paymentID,
+		PaymentDate:       reversalDate,
+		ProofURL:          req.ProofURL,
+		Reason:            req.Reason,
+		Notes:             req.Notes,
+		CreatedBy:         userID,
+	}
+
+	id, change, err := s.paymentRepo.Reverse(ctx, reversal)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("payment not found")
+		}
+		if isPaymentRuleError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("reverse payment: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "REVERSE", "invoice_payment", paymentID,
+		fmt.Sprintf("invoice=%s, reversal=%d, amount=%s, reason=%s, %s", inv.InvoiceNumber, id, reversal.Amount, req.Reason, balanceDetails(change)))
+	s.notifyUser(ctx, inv.CreatedBy, "Pembayaran Dibatalkan",
+		fmt.Sprintf("Pembayaran Rp %.0f untuk invoice %s dibatalkan: %s", reversal.Amount.Float64(), inv.InvoiceNumber, req.Reason),
+		model.NotifPaymentReversed, inv.ID)
+
+	reversal.ID = id
+	reversal.CreatedAt = time.Now()
+	return s.toPaymentResponse(ctx, reversal, change), nil
+}
+
+// Refund records money paid back to the client for an overpaid invoice.
+func (s *InvoicePaymentService) Refund(ctx context.Context, invoiceID uint64, req *request.CreateInvoiceRefundRequest, userID uint64) (*response.InvoicePaymentResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
 	}
 
-	return &response.InvoicePaymentResponse{
-		ID:            id,
-		InvoiceID:     payment.InvoiceID,
-		Amount:        payment.Amount,
-		PaymentDate:   payment.PaymentDate.Format("2006-01-02"),
-		PaymentMethod: string(payment.PaymentMethod),
-		ProofURL:      payment.ProofURL,
-		Notes:         payment.Notes,
-		CreatedBy:     payment.CreatedBy,
-		CreatorName:   creatorName,
-		CreatedAt:     time.Now(),
-	}, nil
+	refundDate, err := time.Parse("2006-01-02", req.RefundDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid refund date format, use YYYY-MM-DD")
+	}
+
+	refund := &model.InvoicePayment{
+		InvoiceID:     invoiceID,
+		Amount:        req.Amount,
+		PaymentDate:   refundDate,
+		PaymentMethod: model.PaymentMethod(req.PaymentMethod),
+		ProofURL:      req.ProofURL,
+		Reason:        req.Reason,
+		Notes:         req.Notes,
+		CreatedBy:     userID,
+	}
+
+	id, change, err := s.paymentRepo.Refund(ctx, refund)
+	if err != nil {
+		if isPaymentRuleError(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("refund payment: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "REFUND", "invoice_payment", id,
+		fmt.Sprintf("invoice=%s, amount=%s, method=%s, reason=%s, %s", inv.InvoiceNumber, req.Amount, req.PaymentMethod, req.Reason, balanceDetails(change)))
+	s.notifyUser(ctx, inv.CreatedBy, "Pengembalian Dana",
+		fmt.Sprintf("Pengembalian dana Rp %.0f untuk invoice %s telah dicatat", req.Amount.Float64(), inv.InvoiceNumber),
+		model.NotifPaymentRefunded, inv.ID)
+
+	refund.ID = id
+	refund.CreatedAt = time.Now()
+	return s.toPaymentResponse(ctx, refund, change), nil
 }
 
 func (s *InvoicePaymentService) ListByInvoice(ctx context.Context, invoiceID uint64) ([]response.InvoicePaymentResponse, error) {
@@ -128,37 +209,68 @@ func (s *InvoicePaymentService) ListByInvoice(ctx context.Context, invoiceID uin
 	}
 
 	result := make([]response.InvoicePaymentResponse, 0, len(payments))
-	for _, p := range payments {
-		// Get creator name
-		creator, _ := s.userRepo.FindByID(ctx, p.CreatedBy)
-		creatorName := ""
-		if creator != nil {
-			creatorName = creator.FullName
-		}
-
-		result = append(result, response.InvoicePaymentResponse{
-			ID:            p.ID,
-			InvoiceID:     p.InvoiceID,
-			Amount:        p.Amount,
-			PaymentDate:   p.PaymentDate.Format("2006-01-02"),
-			PaymentMethod: string(p.PaymentMethod),
-			ProofURL:      p.ProofURL,
-			Notes:         p.Notes,
-			CreatedBy:     p.CreatedBy,
-			CreatorName:   creatorName,
-			CreatedAt:     p.CreatedAt,
-		})
+	for i := range payments {
+		result = append(result, *s.toPaymentResponse(ctx, &payments[i], nil))
 	}
 	return result, nil
 }
 
-func (s *InvoicePaymentService) Delete(ctx context.Context, paymentID, invoiceID, userID uint64) error {
-	if err := s.paymentRepo.Delete(ctx, paymentID, invoiceID); err != nil {
-		return fmt.Errorf("delete payment: %w", err)
+func (s *InvoicePaymentService) toPaymentResponse(ctx context.Context, p *model.InvoicePayment, change *repository.BalanceChange) *response.InvoicePaymentResponse {
+	// Get creator name
+	creator, _ := s.userRepo.FindByID(ctx, p.CreatedBy)
+	creatorName := ""
+	if creator != nil {
+		creatorName = creator.FullName
+	}
+
+	resp := &response.InvoicePaymentResponse{
+		ID:                p.ID,
+		InvoiceID:         p.InvoiceID,
+		EntryType:         string(p.EntryType),
+		ReceiptNumber:     p.ReceiptNumber,
+		ReversedPaymentID: p.ReversedPaymentID,
+		ReversedByID:      p.ReversedByID,
+		Amount:            p.Amount,
+		PaymentDate:       p.PaymentDate.Format("2006-01-02"),
+		PaymentMethod:     string(p.PaymentMethod),
+		ProofURL:          p.ProofURL,
+		Reason:            p.Reason,
+		Notes:             p.Notes,
+		CreatedBy:         p.CreatedBy,
+		CreatorName:       creatorName,
+		CreatedAt:         p.CreatedAt,
+	}
+	if change != nil {
+		resp.Balance = &response.InvoiceBalanceResponse{
+			PaidAmount:     change.After.PaidAmount,
+			CreditedAmount: change.After.CreditedAmount,
+			Outstanding:    change.After.Outstanding(),
+			PaymentStatus:  string(change.After.PaymentStatus),
+		}
 	}
+	return resp
+}
+
+// balanceDetails formats the invoice balance before and after a payment entry
+// for the audit log.
+func balanceDetails(c *repository.BalanceChange) string {
+	return fmt.Sprintf("paid_before=%s, paid_after=%s, status_before=%s, status_after=%s",
+		c.Before.PaidAmount, c.After.PaidAmount, c.Before.PaymentStatus, c.After.PaymentStatus)
+}
 
-	s.logAudit(ctx, userID, "DELETE", "invoice_payment", paymentID, fmt.Sprintf("invoice_id=%d", invoiceID))
-	return nil
+// isPaymentRuleError reports whether a repository error is a business rule
+// violation that can be shown to the user as is.
+func isPaymentRuleError(err error) bool {
+	msg := err.Error()
+	switch msg {
+	case "invoice must be approved before recording payments",
+		"only payments can be reversed",
+		"payment is already reversed",
+		"reversal exceeds the net paid amount",
+		"invoice has no overpaid balance":
+		return true
+	}
+	return strings.HasPrefix(msg, "payment amount (") || strings.HasPrefix(msg, "refund amount (")
 }
 
 func (s *InvoicePaymentService) logAudit(ctx context.Context, userID uint64, action, entityType string, entityID uint64, details string) {
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/pdf"
+)
+
+// RenderPDF renders the invoice as a PDF. It returns the PDF bytes and a
+// download filename derived from the invoice number.
+func (s *InvoiceService) RenderPDF(ctx context.Context, id uint64, userID uint64, role string) ([]byte, string, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("invoice not found")
+		}
+		return nil, "", err
+	}
+
+	if err := s.checkInvoiceAccess(ctx, inv, userID, role); err != nil {
+		return nil, "", err
+	}
+
+	content, err := s.renderInvoicePDF(ctx, inv)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filename := strings.NewReplacer("/", "-", "\\", "-", " ", "_").Replace(inv.InvoiceNumber) + ".pdf"
+	return content, filename, nil
+}
+
+// SavePDF renders the invoice, stores it in the uploads directory and sets it
+// as the invoice's file_url. Only FINANCE, OWNER or the invoice creator may do this.
+func (s *InvoiceService) SavePDF(ctx context.Context, id uint64, userID uint64, role string) (string, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("invoice not found")
+		}
+		return "", err
+	}
+
+	if role != "FINANCE" && role != "OWNER" && inv.CreatedBy != userID {
+		return "", fmt.Errorf("not authorized to generate invoice pdf")
+	}
+
+	content, err := s.renderInvoicePDF(ctx, inv)
+	if err != nil {
+		return "", err
+	}
+
+	filename := fmt.Sprintf("%s-invoice-%d-%s.pdf", time.Now().Format("20060102"), inv.ID, uuid.New().String()[:8])
+	if err := os.WriteFile(filepath.Join(s.uploadDir, filename), content, 0644); err != nil {
+		return "", fmt.Errorf("write invoice pdf: %w", err)
+	}
+
+	fileURL := "/uploads/" + filename
+	if err := s.invoiceRepo.UpdateFileURL(ctx, inv.ID, fileURL); err != nil {
+		return "", err
+	}
+
+	s.logAudit(ctx, userID, "GENERATE_PDF", inv.ID, fmt.Sprintf("number=%s, file=%s", inv.InvoiceNumber, fileURL))
+	return fileURL, nil
+}
+
+// checkInvoiceAccess allows FINANCE and OWNER to access any invoice and the
+// creator of an invoice to access it; other users must be a member of the
+// invoice's project. PDF downloads, receipts, revisions and clones share it.
+func (s *InvoiceService) checkInvoiceAccess(ctx context.Context, inv *model.Invoice, userID uint64, role string) error {
+	if role == "FINANCE" || role == "OWNER" || inv.CreatedBy == userID {
+		return nil
+	}
+	isMember, err := s.memberRepo.Exists(ctx, inv.ProjectID, userID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return fmt.Errorf("not a member of this project")
+	}
+	return nil
+}
+
+func (s *InvoiceService) renderInvoicePDF(ctx context.Context, inv *model.Invoice) ([]byte, error) {
+	items, err := s.invoiceRepo.FindItemsByInvoiceID(ctx, inv.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get invoice items: %w", err)
+	}
+
+	company, err := s.companyRepo.Get(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get company settings: %w", err)
+	}
+
+	doc := &pdf.InvoiceDocument{
+		Invoice: inv,
+		Items:   items,
+		Company: company,
+	}
+	if project, err := s.projectRepo.FindByID(ctx, inv.ProjectID); err == nil {
+		doc.ProjectName = project.Name
+	}
+	if company != nil {
+		doc.LogoPath = s.localUploadPath(company.LogoURL)
+	}
+
+	return pdf.RenderInvoice(doc)
+}
+
+// localUploadPath maps an "/uploads/..." URL to a file in the uploads directory.
+// Remote URLs are not fetched and yield an empty path.
+func (s *InvoiceService) localUploadPath(fileURL string) string {
+	if !strings.HasPrefix(fileURL, "/uploads/") {
+		return ""
+	}
+	return filepath.Join(s.uploadDir, filepath.Base(fileURL))
+}
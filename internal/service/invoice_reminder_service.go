@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+)
+
+type InvoiceReminderService struct {
+	invoiceRepo  *repository.InvoiceRepository
+	reminderRepo *repository.InvoiceReminderRepository
+	notifRepo    *repository.NotificationRepository
+	userRepo     *repository.UserRepository
+	sseHub       *sse.Hub
+	reminderDays []int
+}
+
+func NewInvoiceReminderService(
+	invoiceRepo *repository.InvoiceRepository,
+	reminderRepo *repository.InvoiceReminderRepository,
+	notifRepo *repository.NotificationRepository,
+	userRepo *repository.UserRepository,
+	sseHub *sse.Hub,
+	reminderDays []int,
+) *InvoiceReminderService {
+	days := append([]int(nil), reminderDays...)
+	sort.Ints(days)
+	return &InvoiceReminderService{
+		invoiceRepo:  invoiceRepo,
+		reminderRepo: reminderRepo,
+		notifRepo:    notifRepo,
+		userRepo:     userRepo,
+		sseHub:       sseHub,
+		reminderDays: days,
+	}
+}
+
+// SendOverdueReminders notifies the creator and FINANCE/OWNER users about
+// approved invoices past their due date that are not fully paid. As in the
+// aging report, invoices without a due date are due on the invoice date. A
+// reminder is sent once per configured stage (days past due); if a run was
+// missed, only the latest stage reached is sent.
+func (s *InvoiceReminderService) SendOverdueReminders(ctx context.Context) error {
+	if len(s.reminderDays) == 0 {
+		return nil
+	}
+
+	invoices, err := s.invoiceRepo.FindOutstanding(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("get outstanding invoices: %w", err)
+	}
+
+	finance, err := s.userRepo.FindByRoles(ctx, []string{"FINANCE", "OWNER"})
+	if err != nil {
+		return fmt.Errorf("find finance users: %w", err)
+	}
+
+	today := truncateDate(time.Now())
+	sent := 0
+	for _, inv := range invoices {
+		if inv.PaymentStatus == model.PaymentStatusPaid {
+			continue
+		}
+		due := inv.InvoiceDate
+		if inv.DueDate != nil {
+			due = *inv.DueDate
+		}
+		daysOverdue := int(today.Sub(truncateDate(due)).Hours() / 24)
+		stage := s.reminderStage(daysOverdue)
+		if stage == 0 {
+			continue
+		}
+
+		isNew, err := s.reminderRepo.MarkSent(ctx, inv.ID, stage)
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			continue
+		}
+
+		outstanding := inv.Amount - inv.PaidAmount - inv.CreditedAmount
+		title := "Invoice Jatuh Tempo"
+		message := fmt.Sprintf("Invoice %s untuk %s telah lewat jatuh tempo %d hari. Sisa tagihan Rp %.0f",
+			inv.InvoiceNumber, inv.RecipientName, daysOverdue, outstanding.Float64())
+
+		recipients := map[uint64]bool{inv.CreatedBy: true}
+		for _, u := range finance {
+			recipients[u.ID] = true
+		}
+		for userID := range recipients {
+			s.notifyUser(ctx, userID, title, message, model.NotifInvoiceOverdue, inv.ID)
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("[reminder] sent overdue reminders for %d invoice(s)", sent)
+	}
+	return nil
+}
+
+// reminderStage returns the highest configured stage reached, or 0 if none.
+func (s *InvoiceReminderService) reminderStage(daysOverdue int) int {
+	stage := 0
+	for _, d := range s.reminderDays {
+		if daysOverdue >= d {
+			stage = d
+		}
+	}
+	return stage
+}
+
+func (s *InvoiceReminderService) notifyUser(ctx context.Context, userID uint64, title, message string, notifType model.NotificationType, refID uint64) {
+	id, err := s.notifRepo.Create(ctx, &model.Notification{
+		UserID:      userID,
+		Title:       title,
+		Message:     message,
+		Type:        notifType,
+		ReferenceID: &refID,
+	})
+	if err != nil {
+		log.Printf("notification error: %v", err)
+		return
+	}
+	s.sseHub.Publish(userID, sse.Event{
+		Type: string(notifType),
+		Data: map[string]interface{}{"id": id, "title": title, "message": message, "reference_id": refID},
+	})
+}
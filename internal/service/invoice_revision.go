@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+)
+
+// ListRevisions returns the revision history of an invoice without snapshots.
+func (s *InvoiceService) ListRevisions(ctx context.Context, invoiceID uint64, userID uint64, role string) ([]response.InvoiceRevisionResponse, error) {
+	if err := s.checkRevisionAccess(ctx, invoiceID, userID, role); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.InvoiceRevisionResponse, 0, len(revisions))
+	for i := range revisions {
+		result = append(result, toInvoiceRevisionResponse(&revisions[i]))
+	}
+	return result, nil
+}
+
+// GetRevision returns one revision including its snapshot.
+func (s *InvoiceService) GetRevision(ctx context.Context, invoiceID uint64, revisionNumber int, userID uint64, role string) (*response.InvoiceRevisionResponse, error) {
+	if err := s.checkRevisionAccess(ctx, invoiceID, userID, role); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, invoiceID, revisionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toInvoiceRevisionResponse(rev)
+	resp.Snapshot, err = json.Marshal(rev.Snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("encode invoice revision: %w", err)
+	}
+	return &resp, nil
+}
+
+// DiffRevisions lists the fields that changed between two revisions. When to
+// is 0 the latest revision is used; when from is 0 the one before to.
+func (s *InvoiceService) DiffRevisions(ctx context.Context, invoiceID uint64, from, to int, userID uint64, role string) (*response.InvoiceRevisionDiffResponse, error) {
+	if err := s.checkRevisionAccess(ctx, invoiceID, userID, role); err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		latest, err := s.revisionRepo.FindLatestNumber(ctx, invoiceID)
+		if err != nil {
+			return nil, err
+		}
+		if latest == 0 {
+			return nil, fmt.Errorf("revision not found")
+		}
+		to = latest
+	}
+	if from == 0 {
+		from = to - 1
+	}
+	if from < 1 || from == to {
+		return nil, fmt.Errorf("two different revisions are required")
+	}
+
+	fromRev, err := s.findRevision(ctx, invoiceID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.findRevision(ctx, invoiceID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.InvoiceRevisionDiffResponse{
+		InvoiceID:    invoiceID,
+		FromRevision: from,
+		ToRevision:   to,
+		Changes:      diffInvoiceSnapshots(&fromRev.Snapshot, &toRev.Snapshot),
+	}, nil
+}
+
+func (s *InvoiceService) checkRevisionAccess(ctx context.Context, invoiceID uint64, userID uint64, role string) error {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("invoice not found")
+		}
+		return err
+	}
+	return s.checkInvoiceAccess(ctx, inv, userID, role)
+}
+
+func (s *InvoiceService) findRevision(ctx context.Context, invoiceID uint64, revisionNumber int) (*model.InvoiceRevision, error) {
+	rev, err := s.revisionRepo.FindByNumber(ctx, invoiceID, revisionNumber)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("revision not found")
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+func toInvoiceRevisionResponse(rev *model.InvoiceRevision) response.InvoiceRevisionResponse {
+	return response.InvoiceRevisionResponse{
+		ID:             rev.ID,
+		InvoiceID:      rev.InvoiceID,
+		RevisionNumber: rev.RevisionNumber,
+		Action:         string(rev.Action),
+		Status:         string(rev.Snapshot.Status),
+		Amount:         rev.Snapshot.Amount,
+		CreatedBy:      rev.CreatedBy,
+		CreatorName:    rev.CreatorName,
+		CreatedAt:      rev.CreatedAt,
+	}
+}
+
+// diffInvoiceSnapshots compares two snapshots field by field in declaration
+// order. Items are matched by position, so inserting an item shows up as
+// changes to the items after it plus one added item at the end.
+func diffInvoiceSnapshots(from, to *model.InvoiceSnapshot) []response.InvoiceFieldChangeResponse {
+	changes := []response.InvoiceFieldChangeResponse{}
+	diffStruct("", reflect.ValueOf(*from), reflect.ValueOf(*to), &changes)
+	return changes
+}
+
+func diffStruct(prefix string, a, b reflect.Value, changes *[]response.InvoiceFieldChangeResponse) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fa, fb := a.Field(i), b.Field(i)
+		if fa.Kind() == reflect.Slice && fa.Type().Elem().Kind() == reflect.Struct {
+			diffSlice(path, fa, fb, changes)
+			continue
+		}
+		if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			*changes = append(*changes, response.InvoiceFieldChangeResponse{
+				Field: path, Type: "CHANGED", Old: fa.Interface(), New: fb.Interface(),
+			})
+		}
+	}
+}
+
+func diffSlice(path string, a, b reflect.Value, changes *[]response.InvoiceFieldChangeResponse) {
+	n := a.Len()
+	if b.Len() > n {
+		n = b.Len()
+	}
+	for i := 0; i < n; i++ {
+		elem := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= a.Len():
+			*changes = append(*changes, response.InvoiceFieldChangeResponse{
+				Field: elem, Type: "ADDED", New: b.Index(i).Interface(),
+			})
+		case i >= b.Len():
+			*changes = append(*changes, response.InvoiceFieldChangeResponse{
+				Field: elem, Type: "REMOVED", Old: a.Index(i).Interface(),
+			})
+		default:
+			diffStruct(elem, a.Index(i), b.Index(i), changes)
+		}
+	}
+}
@@ -16,35 +16,50 @@ import (
 )
 
 type InvoiceService struct {
-	invoiceRepo *repository.InvoiceRepository
-	paymentRepo *repository.InvoicePaymentRepository
-	projectRepo *repository.ProjectRepository
-	memberRepo  *repository.ProjectMemberRepository
-	auditRepo   *repository.AuditLogRepository
-	notifRepo   *repository.NotificationRepository
-	userRepo    *repository.UserRepository
-	sseHub      *sse.Hub
+	invoiceRepo  *repository.InvoiceRepository
+	paymentRepo  *repository.InvoicePaymentRepository
+	creditRepo   *repository.CreditNoteRepository
+	revisionRepo *repository.InvoiceRevisionRepository
+	projectRepo  *repository.ProjectRepository
+	memberRepo   *repository.ProjectMemberRepository
+	auditRepo    *repository.AuditLogRepository
+	notifRepo    *repository.NotificationRepository
+	userRepo     *repository.UserRepository
+	companyRepo  *repository.CompanySettingsRepository
+	clientRepo   *repository.ClientRepository
+	sseHub       *sse.Hub
+	uploadDir    string
 }
 
 func NewInvoiceService(
 	invoiceRepo *repository.InvoiceRepository,
 	paymentRepo *repository.InvoicePaymentRepository,
+	creditRepo *repository.CreditNoteRepository,
+	revisionRepo *repository.InvoiceRevisionRepository,
 	projectRepo *repository.ProjectRepository,
 	memberRepo *repository.ProjectMemberRepository,
 	auditRepo *repository.AuditLogRepository,
 	notifRepo *repository.NotificationRepository,
 	userRepo *repository.UserRepository,
+	companyRepo *repository.CompanySettingsRepository,
+	clientRepo *repository.ClientRepository,
 	sseHub *sse.Hub,
+	uploadDir string,
 ) *InvoiceService {
 	return &InvoiceService{
-		invoiceRepo: invoiceRepo,
-		paymentRepo: paymentRepo,
-		projectRepo: projectRepo,
-		memberRepo:  memberRepo,
-		auditRepo:   auditRepo,
-		notifRepo:   notifRepo,
-		userRepo:    userRepo,
-		sseHub:      sseHub,
+		invoiceRepo:  invoiceRepo,
+		paymentRepo:  paymentRepo,
+		creditRepo:   creditRepo,
+		revisionRepo: revisionRepo,
+		projectRepo:  projectRepo,
+		memberRepo:   memberRepo,
+		auditRepo:    auditRepo,
+		notifRepo:    notifRepo,
+		userRepo:     userRepo,
+		companyRepo:  companyRepo,
+		clientRepo:   clientRepo,
+		sseHub:       sseHub,
+		uploadDir:    uploadDir,
 	}
 }
 
@@ -90,9 +105,32 @@ func (s *InvoiceService) notifyRoles(ctx context.Context, roles []string, title,
 	}
 }
 
+// notifyFirstApprovers notifies the roles of the first open step of the
+// invoice's approval chain, falling back to the default approvers.
+func (s *InvoiceService) notifyFirstApprovers(ctx context.Context, invoiceID uint64, title, message string, notifType model.NotificationType) {
+	roles := model.DefaultApproverRoles
+	steps, err := s.invoiceRepo.FindCurrentApprovalSteps(ctx, invoiceID)
+	if err != nil {
+		log.Printf("find approval steps error: %v", err)
+	}
+	for _, st := range steps {
+		if st.Status == model.ApprovalStepPending {
+			roles = st.ApproverRoles
+			break
+		}
+	}
+	s.notifyRoles(ctx, roles, title, message, notifType, invoiceID)
+}
+
 func (s *InvoiceService) Create(ctx context.Context, req *request.CreateInvoiceRequest, userID uint64, role string) (*response.InvoiceResponse, error) {
+	return s.create(ctx, req, nil, userID, role)
+}
+
+// create builds and stores a new invoice. billingTermID links the invoice to
+// the project billing term it was generated from, if any.
+func (s *InvoiceService) create(ctx context.Context, req *request.CreateInvoiceRequest, billingTermID *uint64, userID uint64, role string) (*response.InvoiceResponse, error) {
 	// Verify project exists
-	_, err := s.projectRepo.FindByID(ctx, req.ProjectID)
+	project, err := s.projectRepo.FindByID(ctx, req.ProjectID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("project not found")
@@ -122,64 +160,61 @@ func (s *InvoiceService) Create(ctx context.Context, req *request.CreateInvoiceR
 		return nil, fmt.Errorf("items or labels are required")
 	}
 
-	// Calculate totals from items and labels
-	var subtotal float64
-	var items []model.InvoiceItem
+	// Bill the requested client, falling back to the project's client
+	var client *model.Client
+	clientID := req.ClientID
+	if clientID == nil {
+		clientID = project.ClientID
+	}
+	if clientID != nil && *clientID != 0 {
+		client, err = s.findBillableClient(ctx, *clientID)
+		if err != nil {
+			return nil, err
+		}
+	} else if req.RecipientName == "" {
+		return nil, fmt.Errorf("recipient_name is required")
+	}
 
-	// Add standalone items
-	for _, item := range req.Items {
-		itemSubtotal := item.Quantity * item.UnitPrice
-		subtotal += itemSubtotal
-		items = append(items, model.InvoiceItem{
-			Description: item.Description,
-			Quantity:    item.Quantity,
-			Unit:        item.Unit,
-			UnitPrice:   item.UnitPrice,
-			Subtotal:    itemSubtotal,
-		})
-	}
-
-	// Add labels with children
-	for _, label := range req.Labels {
-		var children []model.InvoiceItem
-		for _, child := range label.Items {
-			childSubtotal := child.Quantity * child.UnitPrice
-			subtotal += childSubtotal
-			children = append(children, model.InvoiceItem{
-				Description: child.Description,
-				Quantity:    child.Quantity,
-				Unit:        child.Unit,
-				UnitPrice:   child.UnitPrice,
-				Subtotal:    childSubtotal,
-			})
-		}
-		items = append(items, model.InvoiceItem{
-			IsLabel:     true,
-			Description: label.Description,
-			Children:    children,
-		})
-	}
-
-	// Calculate dual tax and total
-	ppnAmount := subtotal * req.PPNPercentage / 100
-	pphAmount := subtotal * req.PPHPercentage / 100
-	total := subtotal + ppnAmount - pphAmount
+	items, err := buildInvoiceItems(req.Items, req.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDiscountMode(req.DiscountPercent, req.DiscountAmount); err != nil {
+		return nil, err
+	}
+
+	// Fall back to the company's PPN mode and rate
+	company, err := s.companyRepo.Get(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get company settings: %w", err)
+	}
+	ppnMode := model.PPNMode(req.PPNMode)
+	if ppnMode == "" && company != nil {
+		ppnMode = company.DefaultPPNMode
+	}
+	var ppnPercentage float64
+	if req.PPNPercentage != nil {
+		ppnPercentage = *req.PPNPercentage
+	} else if company != nil {
+		ppnPercentage = company.DefaultPPNPercentage
+	}
 
 	inv := &model.Invoice{
 		InvoiceType:      model.InvoiceType(req.InvoiceType),
 		ProjectID:        req.ProjectID,
+		BillingTermID:    billingTermID,
 		RecipientName:    req.RecipientName,
 		RecipientAddress: req.RecipientAddress,
 		Attention:        req.Attention,
 		PONumber:         req.PONumber,
 		InvoiceDate:      invoiceDate,
 		DPPercentage:     req.DPPercentage,
-		Subtotal:         subtotal,
-		PPNPercentage:    req.PPNPercentage,
-		PPNAmount:        ppnAmount,
+		DiscountPercent:  req.DiscountPercent,
+		DiscountAmount:   req.DiscountAmount,
+		PPNMode:          ppnMode,
+		PPNPercentage:    ppnPercentage,
 		PPHPercentage:    req.PPHPercentage,
-		PPHAmount:        pphAmount,
-		Amount:           total,
+		PPH21Percentage:  req.PPH21Percentage,
 		Notes:            req.Notes,
 		Language:         req.Language,
 		FileURL:          req.FileURL,
@@ -187,6 +222,34 @@ func (s *InvoiceService) Create(ctx context.Context, req *request.CreateInvoiceR
 		PaymentStatus:    model.PaymentStatusUnpaid,
 	}
 
+	// Calculate discounts, taxes and total from items and labels
+	lineTotals, err := computeInvoiceTotals(inv, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshot the client's billing identity; explicit request values still win
+	if client != nil {
+		applyClientSnapshot(inv, client)
+		inv.DueDate = clientDueDate(inv, client)
+		if req.RecipientName != "" {
+			inv.RecipientName = req.RecipientName
+		}
+		if req.RecipientAddress != "" {
+			inv.RecipientAddress = req.RecipientAddress
+		}
+		if req.Attention != "" {
+			inv.Attention = req.Attention
+		}
+	}
+	if req.RecipientNPWP != "" {
+		npwp, err := normalizeNPWP(req.RecipientNPWP)
+		if err != nil {
+			return nil, err
+		}
+		inv.RecipientNPWP = npwp
+	}
+
 	// Parse optional due date
 	if req.DueDate != "" {
 		dueDate, err := time.Parse("2006-01-02", req.DueDate)
@@ -195,50 +258,23 @@ func (s *InvoiceService) Create(ctx context.Context, req *request.CreateInvoiceR
 		}
 	}
 
+	if err := reconcileInvoice(inv, lineTotals); err != nil {
+		return nil, err
+	}
+
 	id, err := s.invoiceRepo.Create(ctx, inv, items)
 	if err != nil {
 		return nil, fmt.Errorf("create invoice: %w", err)
 	}
 
-	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("type=%s, amount=%.2f", inv.InvoiceType, inv.Amount))
-	s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Invoice Baru",
-		fmt.Sprintf("Invoice %s (%s) senilai Rp %.0f telah dibuat", inv.InvoiceType, inv.InvoiceNumber, inv.Amount),
-		model.NotifInvoiceCreated, id)
+	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("type=%s, amount=%s", inv.InvoiceType, inv.Amount))
+	s.notifyFirstApprovers(ctx, id, "Invoice Baru",
+		fmt.Sprintf("Invoice %s (%s) senilai Rp %.0f telah dibuat", inv.InvoiceType, inv.InvoiceNumber, inv.Amount.Float64()),
+		model.NotifInvoiceCreated)
 
 	return s.GetByID(ctx, id)
 }
 
-func (s *InvoiceService) List(ctx context.Context, userID uint64, role string) ([]response.InvoiceResponse, error) {
-	var invoices []model.Invoice
-	var err error
-
-	if model.IsFieldRole(role) {
-		projects, err := s.projectRepo.FindByMemberUserID(ctx, userID)
-		if err != nil {
-			return nil, err
-		}
-		projectIDs := make([]uint64, len(projects))
-		for i, p := range projects {
-			projectIDs[i] = p.ID
-		}
-		invoices, err = s.invoiceRepo.FindByProjectIDs(ctx, projectIDs)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		invoices, err = s.invoiceRepo.FindAll(ctx)
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	result := make([]response.InvoiceResponse, 0, len(invoices))
-	for _, inv := range invoices {
-		result = append(result, toInvoiceResponse(&inv))
-	}
-	return result, nil
-}
-
 func (s *InvoiceService) GetByID(ctx context.Context, id uint64) (*response.InvoiceResponse, error) {
 	inv, err := s.invoiceRepo.FindByID(ctx, id)
 	if err != nil {
@@ -262,21 +298,7 @@ func (s *InvoiceService) GetByID(ctx context.Context, id uint64) (*response.Invo
 		resp.ProjectName = project.Name
 	}
 
-	resp.Items = make([]response.InvoiceItemResponse, len(items))
-	for i, item := range items {
-		resp.Items[i] = response.InvoiceItemResponse{
-			ID:          item.ID,
-			InvoiceID:   item.InvoiceID,
-			ParentID:    item.ParentID,
-			IsLabel:     item.IsLabel,
-			Description: item.Description,
-			Quantity:    item.Quantity,
-			Unit:        item.Unit,
-			UnitPrice:   item.UnitPrice,
-			Subtotal:    item.Subtotal,
-			SortOrder:   item.SortOrder,
-		}
-	}
+	resp.Items, resp.Breakdown = invoiceItemsBreakdown(inv, items)
 
 	// Include payments
 	payments, err := s.paymentRepo.FindByInvoiceID(ctx, id)
@@ -289,20 +311,43 @@ func (s *InvoiceService) GetByID(ctx context.Context, id uint64) (*response.Invo
 				creatorName = creator.FullName
 			}
 			resp.Payments[i] = response.InvoicePaymentResponse{
-				ID:            p.ID,
-				InvoiceID:     p.InvoiceID,
-				Amount:        p.Amount,
-				PaymentDate:   p.PaymentDate.Format("2006-01-02"),
-				PaymentMethod: string(p.PaymentMethod),
-				ProofURL:      p.ProofURL,
-				Notes:         p.Notes,
-				CreatedBy:     p.CreatedBy,
-				CreatorName:   creatorName,
-				CreatedAt:     p.CreatedAt,
+				ID:                p.ID,
+				InvoiceID:         p.InvoiceID,
+				EntryType:         string(p.EntryType),
+				ReceiptNumber:     p.ReceiptNumber,
+				ReversedPaymentID: p.ReversedPaymentID,
+				ReversedByID:      p.ReversedByID,
+				Amount:            p.Amount,
+				PaymentDate:       p.PaymentDate.Format("2006-01-02"),
+				PaymentMethod:     string(p.PaymentMethod),
+				ProofURL:          p.ProofURL,
+				Reason:            p.Reason,
+				Notes:             p.Notes,
+				CreatedBy:         p.CreatedBy,
+				CreatorName:       creatorName,
+				CreatedAt:         p.CreatedAt,
 			}
 		}
 	}
 
+	// Include credit notes
+	creditNotes, err := s.creditRepo.FindByInvoiceID(ctx, id)
+	if err == nil && len(creditNotes) > 0 {
+		resp.CreditNotes = make([]response.CreditNoteResponse, len(creditNotes))
+		for i, cn := range creditNotes {
+			resp.CreditNotes[i] = toCreditNoteResponse(&cn)
+		}
+	}
+
+	// Include the current approval round
+	steps, err := s.invoiceRepo.FindCurrentApprovalSteps(ctx, id)
+	if err == nil && len(steps) > 0 {
+		resp.ApprovalSteps = make([]response.InvoiceApprovalStepResponse, len(steps))
+		for i := range steps {
+			resp.ApprovalSteps[i] = toInvoiceApprovalStepResponse(&steps[i])
+		}
+	}
+
 	return &resp, nil
 }
 
@@ -319,8 +364,24 @@ func (s *InvoiceService) Update(ctx context.Context, id uint64, req *request.Upd
 		return nil, fmt.Errorf("not authorized to update this invoice")
 	}
 
-	if inv.Status != model.InvoiceStatusPending {
-		return nil, fmt.Errorf("only pending invoices can be updated")
+	// A rejected invoice goes back to its creator, and saving it resubmits it
+	resubmit := inv.Status == model.InvoiceStatusRejected
+	if inv.Status != model.InvoiceStatusPending && !resubmit {
+		return nil, fmt.Errorf("only pending or rejected invoices can be updated")
+	}
+
+	// Re-snapshot the recipient when the client changes; client_id 0 unlinks it
+	var client *model.Client
+	if req.ClientID != nil {
+		if *req.ClientID == 0 {
+			inv.ClientID = nil
+		} else {
+			client, err = s.findBillableClient(ctx, *req.ClientID)
+			if err != nil {
+				return nil, err
+			}
+			applyClientSnapshot(inv, client)
+		}
 	}
 
 	if req.RecipientName != "" {
@@ -329,6 +390,13 @@ func (s *InvoiceService) Update(ctx context.Context, id uint64, req *request.Upd
 	if req.RecipientAddress != "" {
 		inv.RecipientAddress = req.RecipientAddress
 	}
+	if req.RecipientNPWP != "" {
+		npwp, err := normalizeNPWP(req.RecipientNPWP)
+		if err != nil {
+			return nil, err
+		}
+		inv.RecipientNPWP = npwp
+	}
 	if req.Attention != "" {
 		inv.Attention = req.Attention
 	}
@@ -342,6 +410,15 @@ func (s *InvoiceService) Update(ctx context.Context, id uint64, req *request.Upd
 		}
 		inv.InvoiceDate = date
 	}
+	if req.DueDate != "" {
+		date, err := time.Parse("2006-01-02", req.DueDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date format")
+		}
+		inv.DueDate = &date
+	} else if client != nil {
+		inv.DueDate = clientDueDate(inv, client)
+	}
 	if req.DPPercentage != nil {
 		inv.DPPercentage = req.DPPercentage
 	}
@@ -358,71 +435,73 @@ func (s *InvoiceService) Update(ctx context.Context, id uint64, req *request.Upd
 	var items []model.InvoiceItem
 	hasItems := (req.Items != nil && len(req.Items) > 0) || (req.Labels != nil && len(req.Labels) > 0)
 	if hasItems {
-		var subtotal float64
-		// Standalone items
-		for _, item := range req.Items {
-			itemSubtotal := item.Quantity * item.UnitPrice
-			subtotal += itemSubtotal
-			items = append(items, model.InvoiceItem{
-				Description: item.Description,
-				Quantity:    item.Quantity,
-				Unit:        item.Unit,
-				UnitPrice:   item.UnitPrice,
-				Subtotal:    itemSubtotal,
-			})
-		}
-		// Labels with children
-		for _, label := range req.Labels {
-			var children []model.InvoiceItem
-			for _, child := range label.Items {
-				childSubtotal := child.Quantity * child.UnitPrice
-				subtotal += childSubtotal
-				children = append(children, model.InvoiceItem{
-					Description: child.Description,
-					Quantity:    child.Quantity,
-					Unit:        child.Unit,
-					UnitPrice:   child.UnitPrice,
-					Subtotal:    childSubtotal,
-				})
-			}
-			items = append(items, model.InvoiceItem{
-				IsLabel:     true,
-				Description: label.Description,
-				Children:    children,
-			})
+		items, err = buildInvoiceItems(req.Items, req.Labels)
+		if err != nil {
+			return nil, err
 		}
-		inv.Subtotal = subtotal
-
-		ppnPct := inv.PPNPercentage
-		if req.PPNPercentage != nil {
-			ppnPct = *req.PPNPercentage
+	}
+	if req.DiscountPercent != nil || req.DiscountAmount != nil {
+		inv.DiscountPercent, inv.DiscountAmount = 0, 0
+		if req.DiscountPercent != nil {
+			inv.DiscountPercent = *req.DiscountPercent
 		}
-		pphPct := inv.PPHPercentage
-		if req.PPHPercentage != nil {
-			pphPct = *req.PPHPercentage
+		if req.DiscountAmount != nil {
+			inv.DiscountAmount = *req.DiscountAmount
 		}
-		inv.PPNPercentage = ppnPct
-		inv.PPNAmount = subtotal * ppnPct / 100
-		inv.PPHPercentage = pphPct
-		inv.PPHAmount = subtotal * pphPct / 100
-		inv.Amount = subtotal + inv.PPNAmount - inv.PPHAmount
-	} else if req.PPNPercentage != nil || req.PPHPercentage != nil {
-		if req.PPNPercentage != nil {
-			inv.PPNPercentage = *req.PPNPercentage
+		if err := checkDiscountMode(inv.DiscountPercent, inv.DiscountAmount); err != nil {
+			return nil, err
 		}
-		if req.PPHPercentage != nil {
-			inv.PPHPercentage = *req.PPHPercentage
+	}
+	if req.PPNMode != "" {
+		inv.PPNMode = model.PPNMode(req.PPNMode)
+	}
+	if req.PPNPercentage != nil {
+		inv.PPNPercentage = *req.PPNPercentage
+	}
+	if req.PPHPercentage != nil {
+		inv.PPHPercentage = *req.PPHPercentage
+	}
+	if req.PPH21Percentage != nil {
+		inv.PPH21Percentage = *req.PPH21Percentage
+	}
+
+	// Totals are always recomputed, from the stored lines when none are given
+	lines := items
+	if !hasItems {
+		stored, err := s.invoiceRepo.FindItemsByInvoiceID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get invoice items: %w", err)
 		}
-		inv.PPNAmount = inv.Subtotal * inv.PPNPercentage / 100
-		inv.PPHAmount = inv.Subtotal * inv.PPHPercentage / 100
-		inv.Amount = inv.Subtotal + inv.PPNAmount - inv.PPHAmount
+		lines = model.NestInvoiceItems(stored)
+	}
+	lineTotals, err := computeInvoiceTotals(inv, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reconcileInvoice(inv, lineTotals); err != nil {
+		return nil, err
 	}
 
-	if err := s.invoiceRepo.Update(ctx, inv, items); err != nil {
+	if err := s.invoiceRepo.Update(ctx, inv, items, userID); err != nil {
+		if err.Error() == "only pending or rejected invoices can be updated" {
+			return nil, err
+		}
 		return nil, fmt.Errorf("update invoice: %w", err)
 	}
 
-	s.logAudit(ctx, userID, "UPDATE", id, "")
+	details := fmt.Sprintf("amount=%s", inv.Amount)
+	if rev, err := s.revisionRepo.FindLatestNumber(ctx, id); err == nil {
+		details = fmt.Sprintf("revision=%d, %s", rev, details)
+	}
+	s.logAudit(ctx, userID, "UPDATE", id, details)
+
+	// Resubmitting starts a new approval round at the first step
+	if resubmit {
+		s.notifyFirstApprovers(ctx, id, "Invoice Diajukan Ulang",
+			fmt.Sprintf("Invoice %s (%s) senilai Rp %.0f diajukan ulang setelah ditolak", inv.InvoiceType, inv.InvoiceNumber, inv.Amount.Float64()),
+			model.NotifInvoiceApproval)
+	}
 
 	return s.GetByID(ctx, id)
 }
@@ -452,7 +531,10 @@ func (s *InvoiceService) Delete(ctx context.Context, id uint64, userID uint64) e
 	return nil
 }
 
-func (s *InvoiceService) Approve(ctx context.Context, id uint64, approvedBy uint64, notes string) (*response.InvoiceResponse, error) {
+// Approve approves the current step of the invoice's approval chain. The
+// invoice only becomes APPROVED once the final step passes; until then the
+// roles of the next step are notified.
+func (s *InvoiceService) Approve(ctx context.Context, id uint64, approvedBy uint64, role, notes string) (*response.InvoiceResponse, error) {
 	inv, err := s.invoiceRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -461,25 +543,44 @@ func (s *InvoiceService) Approve(ctx context.Context, id uint64, approvedBy uint
 		return nil, err
 	}
 
-	if err := s.invoiceRepo.ApproveInvoice(ctx, id, approvedBy, notes); err != nil {
+	outcome, err := s.invoiceRepo.ApproveInvoice(ctx, id, approvedBy, role, notes)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("invoice not found")
 		}
-		if err.Error() == "invoice is not pending" {
+		switch err.Error() {
+		case "invoice is not pending", "invoice has no open approval step",
+			"not authorized to approve this step", "approver already approved an earlier step":
 			return nil, err
 		}
 		return nil, fmt.Errorf("approve invoice: %w", err)
 	}
 
-	s.logAudit(ctx, approvedBy, "APPROVE", id, notes)
+	if outcome.Next != nil {
+		s.logAudit(ctx, approvedBy, "APPROVE_STEP", id, fmt.Sprintf("step=%d/%d, notes=%s", outcome.Step.StepOrder, outcome.TotalSteps, notes))
+		s.notifyRoles(ctx, outcome.Next.ApproverRoles, "Persetujuan Invoice Diperlukan",
+			fmt.Sprintf("Invoice %s (%s) menunggu persetujuan tahap %d dari %d", inv.InvoiceType, inv.InvoiceNumber, outcome.Next.StepOrder, outcome.TotalSteps),
+			model.NotifInvoiceApproval, id)
+		return s.GetByID(ctx, id)
+	}
+
+	// Reload to pick up the invoice number assigned at approval
+	result, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, approvedBy, "APPROVE", id, fmt.Sprintf("number=%s, notes=%s", result.InvoiceNumber, notes))
 	s.notifyUser(ctx, inv.CreatedBy, "Invoice Disetujui",
-		fmt.Sprintf("Invoice %s telah disetujui", inv.InvoiceNumber),
+		fmt.Sprintf("Invoice %s telah disetujui", result.InvoiceNumber),
 		model.NotifInvoiceApproved, id)
 
-	return s.GetByID(ctx, id)
+	return result, nil
 }
 
-func (s *InvoiceService) Reject(ctx context.Context, id uint64, rejectedBy uint64, notes string) (*response.InvoiceResponse, error) {
+// Reject rejects the current approval step and returns the invoice to its
+// creator, who may edit it to resubmit.
+func (s *InvoiceService) Reject(ctx context.Context, id uint64, rejectedBy uint64, role, notes string) (*response.InvoiceResponse, error) {
 	inv, err := s.invoiceRepo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -488,17 +589,19 @@ func (s *InvoiceService) Reject(ctx context.Context, id uint64, rejectedBy uint6
 		return nil, err
 	}
 
-	if err := s.invoiceRepo.RejectInvoice(ctx, id, rejectedBy, notes); err != nil {
+	step, err := s.invoiceRepo.RejectInvoice(ctx, id, rejectedBy, role, notes)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("invoice not found")
 		}
-		if err.Error() == "invoice is not pending" {
+		switch err.Error() {
+		case "invoice is not pending", "invoice has no open approval step", "not authorized to reject this step":
 			return nil, err
 		}
 		return nil, fmt.Errorf("reject invoice: %w", err)
 	}
 
-	s.logAudit(ctx, rejectedBy, "REJECT", id, notes)
+	s.logAudit(ctx, rejectedBy, "REJECT", id, fmt.Sprintf("step=%d, notes=%s", step.StepOrder, notes))
 	s.notifyUser(ctx, inv.CreatedBy, "Invoice Ditolak",
 		fmt.Sprintf("Invoice %s ditolak. Alasan: %s", inv.InvoiceNumber, notes),
 		model.NotifInvoiceRejected, id)
@@ -506,33 +609,76 @@ func (s *InvoiceService) Reject(ctx context.Context, id uint64, rejectedBy uint6
 	return s.GetByID(ctx, id)
 }
 
+func (s *InvoiceService) Void(ctx context.Context, id uint64, voidedBy uint64, reason string) (*response.InvoiceResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.VoidInvoice(ctx, id, voidedBy, reason); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		switch err.Error() {
+		case "only approved invoices can be voided", "invoice with payments cannot be voided":
+			return nil, err
+		}
+		return nil, fmt.Errorf("void invoice: %w", err)
+	}
+
+	s.logAudit(ctx, voidedBy, "VOID", id, fmt.Sprintf("number=%s, reason=%s", inv.InvoiceNumber, reason))
+	s.notifyUser(ctx, inv.CreatedBy, "Invoice Dibatalkan",
+		fmt.Sprintf("Invoice %s dibatalkan (void). Alasan: %s", inv.InvoiceNumber, reason),
+		model.NotifInvoiceVoided, id)
+
+	return s.GetByID(ctx, id)
+}
+
 func toInvoiceResponse(inv *model.Invoice) response.InvoiceResponse {
 	resp := response.InvoiceResponse{
 		ID:               inv.ID,
 		InvoiceNumber:    inv.InvoiceNumber,
 		InvoiceType:      string(inv.InvoiceType),
 		ProjectID:        inv.ProjectID,
+		ClientID:         inv.ClientID,
+		BillingTermID:    inv.BillingTermID,
 		Amount:           inv.Amount,
 		PaidAmount:       inv.PaidAmount,
+		CreditedAmount:   inv.CreditedAmount,
 		Status:           string(inv.Status),
 		PaymentStatus:    string(inv.PaymentStatus),
 		FileURL:          inv.FileURL,
 		RecipientName:    inv.RecipientName,
 		RecipientAddress: inv.RecipientAddress,
+		RecipientNPWP:    inv.RecipientNPWP,
+		TaxInvoiceNumber: inv.TaxInvoiceNumber,
 		Attention:        inv.Attention,
 		PONumber:         inv.PONumber,
 		InvoiceDate:      inv.InvoiceDate.Format("2006-01-02"),
 		DPPercentage:     inv.DPPercentage,
 		Subtotal:      inv.Subtotal,
+		DiscountPercent: inv.DiscountPercent,
+		DiscountAmount: inv.DiscountAmount,
+		DPPAmount:     inv.DPPAmount,
+		PPNMode:       string(inv.PPNMode),
+		PPNBase:       inv.PPNBase,
 		PPNPercentage: inv.PPNPercentage,
 		PPNAmount:     inv.PPNAmount,
 		PPHPercentage: inv.PPHPercentage,
 		PPHAmount:     inv.PPHAmount,
+		PPH21Percentage: inv.PPH21Percentage,
+		PPH21Amount:   inv.PPH21Amount,
 		Notes:            inv.Notes,
 		Language:         inv.Language,
 		CreatedBy:        inv.CreatedBy,
 		ApprovedBy:       inv.ApprovedBy,
 		RejectNotes:      inv.RejectNotes,
+		VoidReason:       inv.VoidReason,
+		VoidedBy:         inv.VoidedBy,
+		VoidedAt:         inv.VoidedAt,
 		CreatedAt:        inv.CreatedAt,
 		UpdatedAt:        inv.UpdatedAt,
 	}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+type InvoiceTemplateService struct {
+	templateRepo   *repository.InvoiceTemplateRepository
+	projectRepo    *repository.ProjectRepository
+	auditRepo      *repository.AuditLogRepository
+	invoiceService *InvoiceService
+}
+
+func NewInvoiceTemplateService(
+	templateRepo *repository.InvoiceTemplateRepository,
+	projectRepo *repository.ProjectRepository,
+	auditRepo *repository.AuditLogRepository,
+	invoiceService *InvoiceService,
+) *InvoiceTemplateService {
+	return &InvoiceTemplateService{
+		templateRepo:   templateRepo,
+		projectRepo:    projectRepo,
+		auditRepo:      auditRepo,
+		invoiceService: invoiceService,
+	}
+}
+
+func (s *InvoiceTemplateService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "invoice_template",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *InvoiceTemplateService) Create(ctx context.Context, req *request.SaveInvoiceTemplateRequest, userID uint64) (*response.InvoiceTemplateResponse, error) {
+	t := &model.InvoiceTemplate{CreatedBy: userID}
+	if err := s.applyTemplateRequest(ctx, t, req); err != nil {
+		return nil, err
+	}
+
+	id, err := s.templateRepo.Create(ctx, t)
+	if err != nil {
+		return nil, fmt.Errorf("create invoice template: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("name=%s, type=%s", t.Name, t.InvoiceType))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *InvoiceTemplateService) List(ctx context.Context, invoiceType string) ([]response.InvoiceTemplateResponse, error) {
+	templates, err := s.templateRepo.FindAll(ctx, model.InvoiceType(invoiceType))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.InvoiceTemplateResponse, 0, len(templates))
+	for i := range templates {
+		result = append(result, toInvoiceTemplateResponse(&templates[i]))
+	}
+	return result, nil
+}
+
+func (s *InvoiceTemplateService) GetByID(ctx context.Context, id uint64) (*response.InvoiceTemplateResponse, error) {
+	t, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toInvoiceTemplateResponse(t)
+	return &resp, nil
+}
+
+// Update replaces a template. Only its creator, FINANCE and OWNER may change it.
+func (s *InvoiceTemplateService) Update(ctx context.Context, id uint64, req *request.SaveInvoiceTemplateRequest, userID uint64, role string) (*response.InvoiceTemplateResponse, error) {
+	t, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageTemplate(t, userID, role) {
+		return nil, fmt.Errorf("not authorized to update this template")
+	}
+
+	if err := s.applyTemplateRequest(ctx, t, req); err != nil {
+		return nil, err
+	}
+	if err := s.templateRepo.Update(ctx, t); err != nil {
+		return nil, fmt.Errorf("update invoice template: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, fmt.Sprintf("name=%s, type=%s", t.Name, t.InvoiceType))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *InvoiceTemplateService) Delete(ctx context.Context, id uint64, userID uint64, role string) error {
+	t, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !canManageTemplate(t, userID, role) {
+		return fmt.Errorf("not authorized to delete this template")
+	}
+
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("invoice template not found")
+		}
+		return err
+	}
+
+	s.logAudit(ctx, userID, "DELETE", id, t.Name)
+	return nil
+}
+
+// CreateInvoice creates a PENDING invoice from a template. It goes through the
+// same checks and item building as a manually entered invoice.
+func (s *InvoiceTemplateService) CreateInvoice(ctx context.Context, id uint64, req *request.CreateInvoiceFromTemplateRequest, userID uint64, role string) (*response.InvoiceResponse, error) {
+	t, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	projectID := req.ProjectID
+	if projectID == 0 && t.ProjectID != nil {
+		projectID = *t.ProjectID
+	}
+	if projectID == 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+
+	invReq := &request.CreateInvoiceRequest{
+		ProjectID:        projectID,
+		InvoiceType:      string(t.InvoiceType),
+		ClientID:         t.ClientID,
+		RecipientName:    t.RecipientName,
+		RecipientAddress: t.RecipientAddress,
+		RecipientNPWP:    t.RecipientNPWP,
+		Attention:        t.Attention,
+		PONumber:         req.PONumber,
+		InvoiceDate:      req.InvoiceDate,
+		DueDate:          req.DueDate,
+		DPPercentage:     t.DPPercentage,
+		DiscountPercent:  t.DiscountPercent,
+		DiscountAmount:   t.DiscountAmount,
+		PPNMode:          string(t.PPNMode),
+		PPNPercentage:    &t.PPNPercentage,
+		PPHPercentage:    t.PPHPercentage,
+		PPH21Percentage:  t.PPH21Percentage,
+		Notes:            t.Notes,
+		Language:         t.Language,
+	}
+	for _, line := range t.Items {
+		invReq.Items = append(invReq.Items, templateLineRequest(line))
+	}
+	for _, label := range t.Labels {
+		reqLabel := request.InvoiceLabelRequest{Description: label.Description}
+		for _, line := range label.Items {
+			reqLabel.Items = append(reqLabel.Items, templateLineRequest(line))
+		}
+		invReq.Labels = append(invReq.Labels, reqLabel)
+	}
+
+	result, err := s.invoiceService.create(ctx, invReq, nil, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "CREATE_INVOICE", id, fmt.Sprintf("invoice=%d, amount=%s", result.ID, result.Amount))
+
+	return result, nil
+}
+
+func (s *InvoiceTemplateService) findTemplate(ctx context.Context, id uint64) (*model.InvoiceTemplate, error) {
+	t, err := s.templateRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice template not found")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *InvoiceTemplateService) applyTemplateRequest(ctx context.Context, t *model.InvoiceTemplate, req *request.SaveInvoiceTemplateRequest) error {
+	if len(req.Items) == 0 && len(req.Labels) == 0 {
+		return fmt.Errorf("items or labels are required")
+	}
+	if err := checkDiscountMode(req.DiscountPercent, req.DiscountAmount); err != nil {
+		return err
+	}
+
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		if _, err := s.projectRepo.FindByID(ctx, *req.ProjectID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("project not found")
+			}
+			return err
+		}
+		t.ProjectID = req.ProjectID
+	} else {
+		t.ProjectID = nil
+	}
+	t.ClientID = nil
+	if req.ClientID != nil && *req.ClientID != 0 {
+		t.ClientID = req.ClientID
+	}
+
+	npwp, err := normalizeNPWP(req.RecipientNPWP)
+	if err != nil {
+		return err
+	}
+
+	t.Name = strings.TrimSpace(req.Name)
+	t.InvoiceType = model.InvoiceType(req.InvoiceType)
+	t.RecipientName = req.RecipientName
+	t.RecipientAddress = req.RecipientAddress
+	t.RecipientNPWP = npwp
+	t.Attention = req.Attention
+	t.DPPercentage = req.DPPercentage
+	t.DiscountPercent = req.DiscountPercent
+	t.DiscountAmount = req.DiscountAmount
+	t.PPNMode = model.PPNMode(req.PPNMode)
+	t.PPNPercentage = req.PPNPercentage
+	t.PPHPercentage = req.PPHPercentage
+	t.PPH21Percentage = req.PPH21Percentage
+	t.Language = req.Language
+	t.Notes = req.Notes
+
+	t.Items = nil
+	for _, item := range req.Items {
+		line, err := requestTemplateLine(item)
+		if err != nil {
+			return err
+		}
+		t.Items = append(t.Items, line)
+	}
+	t.Labels = nil
+	for _, label := range req.Labels {
+		tl := model.InvoiceTemplateLabel{Description: label.Description}
+		for _, item := range label.Items {
+			line, err := requestTemplateLine(item)
+			if err != nil {
+				return err
+			}
+			tl.Items = append(tl.Items, line)
+		}
+		t.Labels = append(t.Labels, tl)
+	}
+	return nil
+}
+
+func canManageTemplate(t *model.InvoiceTemplate, userID uint64, role string) bool {
+	return role == "FINANCE" || role == "OWNER" || t.CreatedBy == userID
+}
+
+func requestTemplateLine(item request.InvoiceItemRequest) (model.InvoiceTemplateLine, error) {
+	line, err := buildInvoiceLine(item)
+	if err != nil {
+		return model.InvoiceTemplateLine{}, err
+	}
+	if lineDiscount(&line) > line.Subtotal {
+		return model.InvoiceTemplateLine{}, fmt.Errorf("line discount exceeds line amount")
+	}
+	return model.InvoiceTemplateLine{
+		Description:     item.Description,
+		Quantity:        item.Quantity,
+		Unit:            item.Unit,
+		UnitPrice:       item.UnitPrice,
+		DiscountPercent: item.DiscountPercent,
+		DiscountAmount:  item.DiscountAmount,
+		PPNCode:         model.PPNCode(item.PPNCode),
+		PPHCode:         model.PPHCode(item.PPHCode),
+	}, nil
+}
+
+func templateLineRequest(line model.InvoiceTemplateLine) request.InvoiceItemRequest {
+	return request.InvoiceItemRequest{
+		Description:     line.Description,
+		Quantity:        line.Quantity,
+		Unit:            line.Unit,
+		UnitPrice:       line.UnitPrice,
+		DiscountPercent: line.DiscountPercent,
+		DiscountAmount:  line.DiscountAmount,
+		PPNCode:         string(line.PPNCode),
+		PPHCode:         string(line.PPHCode),
+	}
+}
+
+func toInvoiceTemplateResponse(t *model.InvoiceTemplate) response.InvoiceTemplateResponse {
+	resp := response.InvoiceTemplateResponse{
+		ID:               t.ID,
+		Name:             t.Name,
+		InvoiceType:      string(t.InvoiceType),
+		ProjectID:        t.ProjectID,
+		ClientID:         t.ClientID,
+		RecipientName:    t.RecipientName,
+		RecipientAddress: t.RecipientAddress,
+		RecipientNPWP:    t.RecipientNPWP,
+		Attention:        t.Attention,
+		DPPercentage:     t.DPPercentage,
+		DiscountPercent:  t.DiscountPercent,
+		DiscountAmount:   t.DiscountAmount,
+		PPNMode:          string(t.PPNMode),
+		PPNPercentage:    t.PPNPercentage,
+		PPHPercentage:    t.PPHPercentage,
+		PPH21Percentage:  t.PPH21Percentage,
+		Language:         t.Language,
+		Notes:            t.Notes,
+		Items:            make([]response.InvoiceTemplateLineResponse, 0, len(t.Items)),
+		Labels:           make([]response.InvoiceTemplateLabelResponse, 0, len(t.Labels)),
+		CreatedBy:        t.CreatedBy,
+		CreatorName:      t.CreatorName,
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+
+	var subtotal money.Money
+	lineResponse := func(line model.InvoiceTemplateLine) response.InvoiceTemplateLineResponse {
+		item := model.InvoiceItem{
+			Subtotal:        lineSubtotal(line.UnitPrice, line.Quantity),
+			DiscountPercent: line.DiscountPercent,
+			DiscountAmount:  line.DiscountAmount,
+		}
+		subtotal += item.Subtotal - lineDiscount(&item)
+		resp := response.InvoiceTemplateLineResponse{
+			Description:     line.Description,
+			Quantity:        line.Quantity,
+			Unit:            line.Unit,
+			UnitPrice:       line.UnitPrice,
+			DiscountPercent: line.DiscountPercent,
+			DiscountAmount:  line.DiscountAmount,
+			PPNCode:         string(line.PPNCode),
+			PPHCode:         string(line.PPHCode),
+		}
+		if resp.PPNCode == "" {
+			resp.PPNCode = string(model.PPNCodeStandard)
+		}
+		if resp.PPHCode == "" {
+			resp.PPHCode = string(model.PPHCode23)
+		}
+		return resp
+	}
+	for _, line := range t.Items {
+		resp.Items = append(resp.Items, lineResponse(line))
+	}
+	for _, label := range t.Labels {
+		lr := response.InvoiceTemplateLabelResponse{
+			Description: label.Description,
+			Items:       make([]response.InvoiceTemplateLineResponse, 0, len(label.Items)),
+		}
+		for _, line := range label.Items {
+			lr.Items = append(lr.Items, lineResponse(line))
+		}
+		resp.Labels = append(resp.Labels, lr)
+	}
+	resp.Subtotal = subtotal
+	return resp
+}
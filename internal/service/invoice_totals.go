@@ -0,0 +1,274 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// Rounding rules for invoice and credit note totals:
+//
+//   - each line is unit price × quantity, rounded half up to the cent
+//   - a percentage line discount is rounded half up to the cent, and the line
+//     amount is the line less its discount
+//   - the subtotal is the exact sum of the line amounts
+//   - a percentage invoice discount is rounded once on the subtotal, and the
+//     DPP (tax base) is the subtotal less the invoice discount
+//   - PPN, PPh 23 and PPh 21 are each computed once on the DPP of the lines
+//     they apply to (not per line) and each rounded half up to the cent
+//   - in the DPP nilai lain mode the PPN base is 11/12 of that DPP, rounded
+//     half up to the cent before the rate is applied
+//   - the total is DPP + PPN − PPh 23 − PPh 21, with no further rounding
+//
+// Applying the rates to the combined base keeps the printed tax lines
+// consistent with the tax invoice (e-Faktur), which also rounds on the total
+// base. The per-line breakdown spreads the invoice discount and the taxes over
+// the lines they apply to, so the lines always add up to the invoice totals.
+// Credit notes follow the same rules at the rates and PPN mode of the credited
+// invoice, with its discount percentage or a proportional share of its fixed
+// discount.
+
+// lineSubtotal returns the amount of an invoice line.
+func lineSubtotal(unitPrice money.Money, quantity float64) money.Money {
+	return unitPrice.MulQuantity(quantity)
+}
+
+// ppnBase returns the base PPN is charged on for a DPP.
+func ppnBase(mode model.PPNMode, dpp money.Money) money.Money {
+	if mode == model.PPNModeNilaiLain {
+		return dpp.MulRatio(11, 12)
+	}
+	return dpp
+}
+
+// lineDiscount returns the discount of an invoice line: its percentage of the
+// line, or else its fixed discount.
+func lineDiscount(item *model.InvoiceItem) money.Money {
+	if item.DiscountPercent > 0 {
+		return item.Subtotal.MulPercent(item.DiscountPercent)
+	}
+	return item.DiscountAmount
+}
+
+// checkDiscountMode rejects a discount given both as a percentage and as a
+// fixed amount.
+func checkDiscountMode(percent float64, amount money.Money) error {
+	if percent > 0 && amount != 0 {
+		return fmt.Errorf("use either discount_percent or discount_amount")
+	}
+	return nil
+}
+
+// invoiceLineTotals is the computed breakdown of one invoice line.
+type invoiceLineTotals struct {
+	item            *model.InvoiceItem
+	label           *model.InvoiceItem // nil for standalone lines
+	amount          money.Money        // after the line discount
+	invoiceDiscount money.Money        // share of the invoice discount
+	dpp             money.Money
+	ppn             money.Money
+	pph             money.Money // PPh 23 or PPh 21, by the line's code
+}
+
+// net returns what the line adds to the invoice total.
+func (l *invoiceLineTotals) net() money.Money {
+	return l.dpp + l.ppn - l.pph
+}
+
+// buildInvoiceItems converts the requested lines and labels into invoice
+// items. Discounts and totals are filled in by computeInvoiceTotals.
+func buildInvoiceItems(reqItems []request.InvoiceItemRequest, reqLabels []request.InvoiceLabelRequest) ([]model.InvoiceItem, error) {
+	var items []model.InvoiceItem
+
+	// Standalone items
+	for _, item := range reqItems {
+		line, err := buildInvoiceLine(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, line)
+	}
+
+	// Labels with children
+	for _, label := range reqLabels {
+		var children []model.InvoiceItem
+		for _, child := range label.Items {
+			line, err := buildInvoiceLine(child)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, line)
+		}
+		items = append(items, model.InvoiceItem{
+			IsLabel:     true,
+			Description: label.Description,
+			Children:    children,
+		})
+	}
+
+	return items, nil
+}
+
+func buildInvoiceLine(req request.InvoiceItemRequest) (model.InvoiceItem, error) {
+	if err := checkDiscountMode(req.DiscountPercent, req.DiscountAmount); err != nil {
+		return model.InvoiceItem{}, err
+	}
+	return model.InvoiceItem{
+		Description:     req.Description,
+		Quantity:        req.Quantity,
+		Unit:            req.Unit,
+		UnitPrice:       req.UnitPrice,
+		DiscountPercent: req.DiscountPercent,
+		DiscountAmount:  req.DiscountAmount,
+		Subtotal:        lineSubtotal(req.UnitPrice, req.Quantity),
+		PPNCode:         model.PPNCode(req.PPNCode),
+		PPHCode:         model.PPHCode(req.PPHCode),
+	}, nil
+}
+
+// computeInvoiceTotals fills in the line discounts and the subtotal, discount,
+// DPP, tax amounts and total of an invoice from its nested items, and returns
+// the breakdown of every line in item order.
+func computeInvoiceTotals(inv *model.Invoice, items []model.InvoiceItem) ([]invoiceLineTotals, error) {
+	var lines []invoiceLineTotals
+	for i := range items {
+		if !items[i].IsLabel {
+			lines = append(lines, invoiceLineTotals{item: &items[i]})
+			continue
+		}
+		for j := range items[i].Children {
+			lines = append(lines, invoiceLineTotals{item: &items[i].Children[j], label: &items[i]})
+		}
+	}
+
+	var subtotal money.Money
+	for i := range lines {
+		item := lines[i].item
+		if item.PPNCode == "" {
+			item.PPNCode = model.PPNCodeStandard
+		}
+		if item.PPHCode == "" {
+			item.PPHCode = model.PPHCode23
+		}
+		item.DiscountAmount = lineDiscount(item)
+		if item.DiscountAmount < 0 || item.DiscountAmount > item.Subtotal {
+			return nil, fmt.Errorf("line discount exceeds line amount")
+		}
+		lines[i].amount = item.Subtotal - item.DiscountAmount
+		subtotal += lines[i].amount
+	}
+	inv.Subtotal = subtotal
+
+	if inv.DiscountPercent > 0 {
+		inv.DiscountAmount = subtotal.MulPercent(inv.DiscountPercent)
+	}
+	if inv.DiscountAmount < 0 || inv.DiscountAmount > subtotal {
+		return nil, fmt.Errorf("invoice discount exceeds subtotal")
+	}
+	inv.DPPAmount = subtotal - inv.DiscountAmount
+
+	amounts := make([]money.Money, len(lines))
+	for i := range lines {
+		amounts[i] = lines[i].amount
+	}
+	for i, share := range spread(inv.DiscountAmount, amounts) {
+		lines[i].invoiceDiscount = share
+		lines[i].dpp = lines[i].amount - share
+	}
+
+	if inv.PPNMode == "" {
+		inv.PPNMode = model.PPNModeStandard
+	}
+	ppnDPP, ppnWeights := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPNCode != model.PPNCodeExempt })
+	inv.PPNBase = ppnBase(inv.PPNMode, ppnDPP)
+	inv.PPNAmount = inv.PPNBase.MulPercent(inv.PPNPercentage)
+	for i, share := range spread(inv.PPNAmount, ppnWeights) {
+		lines[i].ppn = share
+	}
+
+	pph23Base, pph23Weights := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPHCode == model.PPHCode23 })
+	inv.PPHAmount = pph23Base.MulPercent(inv.PPHPercentage)
+	for i, share := range spread(inv.PPHAmount, pph23Weights) {
+		lines[i].pph += share
+	}
+
+	pph21Base, pph21Weights := taxableDPP(lines, func(it *model.InvoiceItem) bool { return it.PPHCode == model.PPHCode21 })
+	inv.PPH21Amount = pph21Base.MulPercent(inv.PPH21Percentage)
+	for i, share := range spread(inv.PPH21Amount, pph21Weights) {
+		lines[i].pph += share
+	}
+
+	inv.Amount = inv.DPPAmount + inv.PPNAmount - inv.PPHAmount - inv.PPH21Amount
+	return lines, nil
+}
+
+// taxableDPP returns the combined DPP of the lines a tax applies to, and each
+// line's DPP as its weight (zero for the other lines).
+func taxableDPP(lines []invoiceLineTotals, applies func(*model.InvoiceItem) bool) (money.Money, []money.Money) {
+	var base money.Money
+	weights := make([]money.Money, len(lines))
+	for i := range lines {
+		if applies(lines[i].item) {
+			weights[i] = lines[i].dpp
+			base += lines[i].dpp
+		}
+	}
+	return base, weights
+}
+
+// spread divides total over the lines in proportion to their weights. Each
+// share is the rounded cumulative share less the shares before it, so the
+// shares add up to total exactly.
+func spread(total money.Money, weights []money.Money) []money.Money {
+	shares := make([]money.Money, len(weights))
+	sum := money.Sum(weights...)
+	if sum == 0 {
+		return shares
+	}
+	var cumulative, allocated money.Money
+	for i, w := range weights {
+		cumulative += w
+		next := total.MulRatio(int64(cumulative), int64(sum))
+		shares[i] = next - allocated
+		allocated = next
+	}
+	return shares
+}
+
+// reconcileInvoice verifies, before an invoice is written, that its header
+// figures agree with the line breakdown computeInvoiceTotals returned for it:
+// the lines add up to the subtotal, the DPP is the subtotal less the
+// discount, the discount and tax shares of the lines add up to the header
+// amounts and the total is DPP + PPN − PPh 23 − PPh 21.
+func reconcileInvoice(inv *model.Invoice, lines []invoiceLineTotals) error {
+	var subtotal, discount, dpp, ppn, pph, net money.Money
+	for i := range lines {
+		l := &lines[i]
+		if l.item.Subtotal != lineSubtotal(l.item.UnitPrice, l.item.Quantity) ||
+			l.amount != l.item.Subtotal-l.item.DiscountAmount ||
+			l.dpp != l.amount-l.invoiceDiscount ||
+			(l.item.PPNCode == model.PPNCodeExempt && l.ppn != 0) {
+			return fmt.Errorf("invoice totals do not reconcile")
+		}
+		subtotal += l.amount
+		discount += l.invoiceDiscount
+		dpp += l.dpp
+		ppn += l.ppn
+		pph += l.pph
+		net += l.net()
+	}
+
+	if subtotal != inv.Subtotal ||
+		inv.DPPAmount != inv.Subtotal-inv.DiscountAmount ||
+		discount != inv.DiscountAmount ||
+		dpp != inv.DPPAmount ||
+		ppn != inv.PPNAmount ||
+		pph != inv.PPHAmount+inv.PPH21Amount ||
+		inv.Amount != inv.DPPAmount+inv.PPNAmount-inv.PPHAmount-inv.PPH21Amount ||
+		net != inv.Amount {
+		return fmt.Errorf("invoice totals do not reconcile")
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// reconciledInvoice returns a discounted invoice with an exempt line and a
+// PPh 21 line, its totals computed.
+func reconciledInvoice(t *testing.T) (*model.Invoice, []invoiceLineTotals) {
+	t.Helper()
+	inv := &model.Invoice{
+		DiscountPercent: 5,
+		PPNMode:         model.PPNModeNilaiLain,
+		PPNPercentage:   12,
+		PPHPercentage:   2,
+		PPH21Percentage: 2.5,
+	}
+	items := []model.InvoiceItem{
+		{UnitPrice: money.Money(1_250_000_33), Quantity: 3, DiscountPercent: 10},
+		{UnitPrice: money.Money(800_000_00), Quantity: 1, PPNCode: model.PPNCodeExempt},
+		{IsLabel: true, Children: []model.InvoiceItem{
+			{UnitPrice: money.Money(333_333_33), Quantity: 2, PPHCode: model.PPHCode21},
+			{UnitPrice: money.Money(99_999_99), Quantity: 7, DiscountAmount: money.Money(1_000_00)},
+		}},
+	}
+	for i := range items {
+		items[i].Subtotal = lineSubtotal(items[i].UnitPrice, items[i].Quantity)
+		for j := range items[i].Children {
+			c := &items[i].Children[j]
+			c.Subtotal = lineSubtotal(c.UnitPrice, c.Quantity)
+		}
+	}
+	lines, err := computeInvoiceTotals(inv, items)
+	if err != nil {
+		t.Fatalf("computeInvoiceTotals: %v", err)
+	}
+	return inv, lines
+}
+
+func TestReconcileInvoice(t *testing.T) {
+	inv, lines := reconciledInvoice(t)
+	if err := reconcileInvoice(inv, lines); err != nil {
+		t.Fatalf("computed invoice does not reconcile: %v", err)
+	}
+}
+
+func TestReconcileInvoiceRejectsInconsistentInvoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(inv *model.Invoice, lines []invoiceLineTotals)
+	}{
+		{"subtotal", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.Subtotal++ }},
+		{"discount", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.DiscountAmount++ }},
+		{"dpp", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.DPPAmount++ }},
+		{"ppn", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.PPNAmount++ }},
+		{"pph 23", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.PPHAmount++ }},
+		{"pph 21", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.PPH21Amount-- }},
+		{"total", func(inv *model.Invoice, _ []invoiceLineTotals) { inv.Amount++ }},
+		{"line subtotal", func(_ *model.Invoice, lines []invoiceLineTotals) { lines[0].item.Subtotal++ }},
+		{"line discount", func(_ *model.Invoice, lines []invoiceLineTotals) { lines[3].item.DiscountAmount++ }},
+		{"ppn on exempt line", func(_ *model.Invoice, lines []invoiceLineTotals) { lines[1].ppn++; lines[0].ppn-- }},
+		{"line pph share", func(_ *model.Invoice, lines []invoiceLineTotals) { lines[2].pph++ }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, lines := reconciledInvoice(t)
+			tt.tamper(inv, lines)
+			if err := reconcileInvoice(inv, lines); err == nil {
+				t.Fatal("expected the inconsistent invoice to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/payment"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/internal/sse"
+)
+
+type PaymentGatewayService struct {
+	chargeRepo   *repository.PaymentChargeRepository
+	invoiceRepo  *repository.InvoiceRepository
+	auditRepo    *repository.AuditLogRepository
+	notifRepo    *repository.NotificationRepository
+	userRepo     *repository.UserRepository
+	sseHub       *sse.Hub
+	provider     payment.Provider
+	chargeExpiry time.Duration
+}
+
+// NewPaymentGatewayService creates the gateway service. provider may be nil
+// when no gateway is configured; issuing charges and receiving callbacks then
+// fail with a clear error.
+func NewPaymentGatewayService(
+	chargeRepo *repository.PaymentChargeRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	auditRepo *repository.AuditLogRepository,
+	notifRepo *repository.NotificationRepository,
+	userRepo *repository.UserRepository,
+	sseHub *sse.Hub,
+	provider payment.Provider,
+	chargeExpiry time.Duration,
+) *PaymentGatewayService {
+	return &PaymentGatewayService{
+		chargeRepo:   chargeRepo,
+		invoiceRepo:  invoiceRepo,
+		auditRepo:    auditRepo,
+		notifRepo:    notifRepo,
+		userRepo:     userRepo,
+		sseHub:       sseHub,
+		provider:     provider,
+		chargeExpiry: chargeExpiry,
+	}
+}
+
+// CreateCharge issues a virtual account number or QRIS code for an approved
+// invoice. The amount defaults to the outstanding balance.
+func (s *PaymentGatewayService) CreateCharge(ctx context.Context, invoiceID uint64, req *request.CreatePaymentChargeRequest, userID uint64) (*response.PaymentChargeResponse, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("payment gateway is not configured")
+	}
+
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+	if inv.Status != model.InvoiceStatusApproved {
+		return nil, fmt.Errorf("invoice must be approved before recording payments")
+	}
+
+	outstanding := inv.Amount - inv.PaidAmount - inv.CreditedAmount
+	if outstanding <= 0 {
+		return nil, fmt.Errorf("invoice is already fully paid")
+	}
+	amount := req.Amount
+	if amount == 0 {
+		amount = outstanding
+	}
+	if amount > outstanding {
+		return nil, fmt.Errorf("payment amount (%s) exceeds remaining balance (%s)", amount, outstanding)
+	}
+
+	method := model.PaymentMethod(req.Method)
+	bank := strings.ToUpper(req.Bank)
+	if method == model.PaymentMethodVirtualAccount && bank == "" {
+		return nil, fmt.Errorf("bank is required for virtual account payments")
+	}
+	if method == model.PaymentMethodQRIS {
+		bank = ""
+	}
+
+	active, err := s.chargeRepo.HasActiveCharge(ctx, invoiceID, method)
+	if err != nil {
+		return nil, err
+	}
+	if active {
+		return nil, fmt.Errorf("invoice already has an active charge for this method")
+	}
+
+	expiresAt := time.Now().Add(s.chargeExpiry).Truncate(time.Second)
+	charge, err := s.provider.CreateCharge(ctx, &payment.ChargeRequest{
+		OrderID:      fmt.Sprintf("INV%d-%d", inv.ID, time.Now().Unix()),
+		Method:       payment.Method(method),
+		Bank:         bank,
+		Amount:       amount,
+		CustomerName: inv.RecipientName,
+		Description:  "Invoice " + inv.InvoiceNumber,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		log.Printf("payment gateway %s: create charge for invoice %d: %v", s.provider.Name(), inv.ID, err)
+		return nil, fmt.Errorf("payment gateway rejected the charge")
+	}
+
+	c := &model.PaymentCharge{
+		InvoiceID:     inv.ID,
+		InvoiceNumber: inv.InvoiceNumber,
+		Provider:      s.provider.Name(),
+		Method:        method,
+		Bank:          bank,
+		Reference:     charge.Reference,
+		VANumber:      charge.VANumber,
+		QRString:      charge.QRString,
+		Amount:        amount,
+		Status:        model.PaymentChargePending,
+		ExpiresAt:     charge.ExpiresAt,
+		CreatedBy:     userID,
+	}
+	id, err := s.chargeRepo.Create(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("create payment charge: %w", err)
+	}
+
+	s.logAudit(ctx, userID, "CREATE", "payment_charge", id,
+		fmt.Sprintf("invoice=%s, provider=%s, method=%s, bank=%s, reference=%s, amount=%s", inv.InvoiceNumber, c.Provider, c.Method, c.Bank, c.Reference, c.Amount))
+
+	created, err := s.chargeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := toPaymentChargeResponse(created)
+	return &resp, nil
+}
+
+func (s *PaymentGatewayService) ListByInvoice(ctx context.Context, invoiceID uint64) ([]response.PaymentChargeResponse, error) {
+	charges, err := s.chargeRepo.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.PaymentChargeResponse, 0, len(charges))
+	for i := range charges {
+		result = append(result, toPaymentChargeResponse(&charges[i]))
+	}
+	return result, nil
+}
+
+// HandleWebhook verifies and applies a gateway callback. Redelivered events
+// are acknowledged without being applied again.
+func (s *PaymentGatewayService) HandleWebhook(ctx context.Context, body []byte, header func(key string) string) (*response.PaymentWebhookResponse, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("payment gateway is not configured")
+	}
+
+	n, err := s.provider.ParseWebhook(body, header)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &model.PaymentWebhookEvent{
+		Provider:  s.provider.Name(),
+		EventID:   n.EventID,
+		Reference: n.Reference,
+		Status:    model.PaymentChargeStatus(n.Status),
+		Amount:    n.Amount,
+		PaidAt:    n.PaidAt,
+		Payload:   string(body),
+	}
+	outcome, err := s.chargeRepo.ApplyWebhookEvent(ctx, ev)
+	if err != nil {
+		return nil, fmt.Errorf("apply webhook event: %w", err)
+	}
+
+	resp := &response.PaymentWebhookResponse{EventID: n.EventID}
+	if outcome.Duplicate {
+		resp.Result = "DUPLICATE"
+		return resp, nil
+	}
+	resp.Result = string(ev.Result)
+	resp.Error = ev.Error
+	if ev.Result != model.WebhookEventProcessed {
+		log.Printf("payment gateway %s: event %s for %s %s: %s", ev.Provider, ev.EventID, ev.Reference, ev.Result, ev.Error)
+	}
+	if ev.Result == model.WebhookEventFailed {
+		s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Callback Pembayaran Gagal",
+			fmt.Sprintf("Callback pembayaran %s (referensi %s) senilai Rp %.0f tidak dapat diproses: %s", ev.Provider, ev.Reference, ev.Amount.Float64(), ev.Error),
+			model.NotifPaymentWebhookFailed, ev.ID)
+	}
+
+	if outcome.PaymentID != 0 {
+		resp.PaymentID = &outcome.PaymentID
+		s.paymentReceived(ctx, outcome)
+	}
+	return resp, nil
+}
+
+// ListWebhookEvents returns the most recent gateway callbacks, optionally only
+// those with the given result, so FAILED ones can be followed up.
+func (s *PaymentGatewayService) ListWebhookEvents(ctx context.Context, req *request.ListPaymentWebhookEventsRequest) ([]response.PaymentWebhookEventResponse, error) {
+	events, err := s.chargeRepo.FindWebhookEvents(ctx, model.WebhookEventResult(req.Result), 200)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.PaymentWebhookEventResponse, 0, len(events))
+	for _, ev := range events {
+		result = append(result, response.PaymentWebhookEventResponse{
+			ID:        ev.ID,
+			Provider:  ev.Provider,
+			EventID:   ev.EventID,
+			Reference: ev.Reference,
+			Status:    string(ev.Status),
+			Amount:    ev.Amount,
+			Result:    string(ev.Result),
+			Error:     ev.Error,
+			ChargeID:  ev.ChargeID,
+			InvoiceID: ev.InvoiceID,
+			CreatedAt: ev.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// SimulatePayment makes the mock gateway send a signed callback for a charge
+// and processes it like a real one. Only available with the mock provider.
+func (s *PaymentGatewayService) SimulatePayment(ctx context.Context, chargeID uint64, req *request.SimulatePaymentChargeRequest) (*response.PaymentWebhookResponse, error) {
+	mock, ok := s.provider.(*payment.MockProvider)
+	if !ok {
+		return nil, fmt.Errorf("payment simulation requires the mock payment provider")
+	}
+
+	charge, err := s.chargeRepo.FindByID(ctx, chargeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("payment charge not found")
+		}
+		return nil, err
+	}
+
+	status := payment.StatusPaid
+	if req.Status != "" {
+		status = payment.Status(req.Status)
+	}
+	amount := charge.Amount
+	if req.Amount != 0 {
+		amount = req.Amount
+	}
+
+	body, signature, err := mock.SimulateCallback(charge.Reference, status, amount)
+	if err != nil {
+		return nil, err
+	}
+	return s.HandleWebhook(ctx, body, func(key string) string {
+		if strings.EqualFold(key, payment.MockSignatureHeader) {
+			return signature
+		}
+		return ""
+	})
+}
+
+func (s *PaymentGatewayService) paymentReceived(ctx context.Context, outcome *repository.WebhookOutcome) {
+	c := outcome.Charge
+	change := outcome.Change
+
+	s.logAudit(ctx, c.CreatedBy, "CREATE", "invoice_payment", outcome.PaymentID,
+		fmt.Sprintf("invoice=%s, amount=%s, method=%s, provider=%s, reference=%s, %s", c.InvoiceNumber, c.Amount, c.Method, c.Provider, c.Reference, balanceDetails(change)))
+
+	inv, err := s.invoiceRepo.FindByID(ctx, c.InvoiceID)
+	if err != nil {
+		log.Printf("payment gateway: load invoice %d: %v", c.InvoiceID, err)
+		return
+	}
+
+	statusLabel := "Bayar Sebagian"
+	if change.After.PaymentStatus == model.PaymentStatusPaid {
+		statusLabel = "Lunas"
+	}
+	via := "QRIS"
+	if c.Method == model.PaymentMethodVirtualAccount {
+		via = "Virtual Account " + c.Bank
+	}
+	s.notifyUser(ctx, inv.CreatedBy, "Pembayaran Diterima",
+		fmt.Sprintf("Pembayaran Rp %.0f via %s untuk invoice %s (%s)", c.Amount.Float64(), via, inv.InvoiceNumber, statusLabel),
+		model.NotifPaymentReceived, inv.ID)
+
+	if outcome.Overpaid > 0 {
+		s.notifyRoles(ctx, []string{"FINANCE", "OWNER"}, "Kelebihan Pembayaran",
+			fmt.Sprintf("Pembayaran via %s untuk invoice %s melebihi sisa tagihan sebesar Rp %.0f dan perlu dikembalikan", via, inv.InvoiceNumber, outcome.Overpaid.Float64()),
+			model.NotifPaymentOverpaid, inv.ID)
+	}
+}
+
+func toPaymentChargeResponse(c *model.PaymentCharge) response.PaymentChargeResponse {
+	status := c.Status
+	if status == model.PaymentChargePending && time.Now().After(c.ExpiresAt) {
+		status = model.PaymentChargeExpired
+	}
+	return response.PaymentChargeResponse{
+		ID:            c.ID,
+		InvoiceID:     c.InvoiceID,
+		InvoiceNumber: c.InvoiceNumber,
+		Provider:      c.Provider,
+		Method:        string(c.Method),
+		Bank:          c.Bank,
+		Reference:     c.Reference,
+		VANumber:      c.VANumber,
+		QRString:      c.QRString,
+		Amount:        c.Amount,
+		Status:        string(status),
+		ExpiresAt:     c.ExpiresAt,
+		PaidAt:        c.PaidAt,
+		PaymentID:     c.PaymentID,
+		CreatedBy:     c.CreatedBy,
+		CreatedAt:     c.CreatedAt,
+	}
+}
+
+func (s *PaymentGatewayService) logAudit(ctx context.Context, userID uint64, action, entityType string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *PaymentGatewayService) notifyRoles(ctx context.Context, roles []string, title, message string, notifType model.NotificationType, refID uint64) {
+	users, err := s.userRepo.FindByRoles(ctx, roles)
+	if err != nil {
+		log.Printf("find users by roles error: %v", err)
+		return
+	}
+	for _, u := range users {
+		s.notifyUser(ctx, u.ID, title, message, notifType, refID)
+	}
+}
+
+func (s *PaymentGatewayService) notifyUser(ctx context.Context, userID uint64, title, message string, notifType model.NotificationType, refID uint64) {
+	id, err := s.notifRepo.Create(ctx, &model.Notification{
+		UserID:      userID,
+		Title:       title,
+		Message:     message,
+		Type:        notifType,
+		ReferenceID: &refID,
+	})
+	if err != nil {
+		log.Printf("notification error: %v", err)
+		return
+	}
+	s.sseHub.Publish(userID, sse.Event{
+		Type: string(notifType),
+		Data: map[string]interface{}{"id": id, "title": title, "message": message, "reference_id": refID},
+	})
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/pdf"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// defaultStampDutyThreshold is the amount above which a receipt needs a
+// meterai (UU 10/2020): Rp5.000.000.
+const defaultStampDutyThreshold = money.Money(500000000)
+
+// RenderReceipt renders the receipt (kwitansi) of a payment as a PDF, or as an
+// HTML page when format is "html". The receipt number is assigned the first
+// time a receipt is requested and stays the same afterwards.
+func (s *InvoiceService) RenderReceipt(ctx context.Context, invoiceID, paymentID uint64, userID uint64, role, format string) ([]byte, string, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("invoice not found")
+		}
+		return nil, "", err
+	}
+
+	if err := s.checkInvoiceAccess(ctx, inv, userID, role); err != nil {
+		return nil, "", err
+	}
+
+	p, err := s.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", fmt.Errorf("payment not found")
+		}
+		return nil, "", err
+	}
+	if p.InvoiceID != inv.ID {
+		return nil, "", fmt.Errorf("payment not found")
+	}
+	if p.EntryType != model.PaymentEntryPayment {
+		return nil, "", fmt.Errorf("receipts are only issued for payments")
+	}
+
+	number, issued, err := s.paymentRepo.AssignReceiptNumber(ctx, p.ID)
+	if err != nil {
+		if err.Error() == "receipts are only issued for payments" {
+			return nil, "", err
+		}
+		return nil, "", fmt.Errorf("assign receipt number: %w", err)
+	}
+	if issued {
+		s.logAudit(ctx, userID, "ISSUE_RECEIPT", inv.ID,
+			fmt.Sprintf("number=%s, invoice=%s, payment=%d, amount=%s", number, inv.InvoiceNumber, p.ID, p.Amount))
+	}
+	p.ReceiptNumber = number
+
+	company, err := s.companyRepo.Get(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, "", fmt.Errorf("get company settings: %w", err)
+	}
+	threshold := defaultStampDutyThreshold
+	if company != nil {
+		threshold = company.StampDutyThreshold
+	}
+
+	doc := &pdf.ReceiptDocument{
+		ReceiptNumber: number,
+		Payment:       p,
+		Invoice:       inv,
+		Company:       company,
+		StampDuty:     p.Amount > threshold,
+		Cancelled:     p.ReversedByID != nil,
+	}
+	if project, err := s.projectRepo.FindByID(ctx, inv.ProjectID); err == nil {
+		doc.ProjectName = project.Name
+	}
+
+	base := strings.NewReplacer("/", "-", "\\", "-", " ", "_").Replace(number)
+	if format == "html" {
+		content, err := pdf.RenderReceiptHTML(doc)
+		if err != nil {
+			return nil, "", err
+		}
+		return content, base + ".html", nil
+	}
+
+	if company != nil {
+		doc.LogoPath = s.localUploadPath(company.LogoURL)
+	}
+	content, err := pdf.RenderReceipt(doc)
+	if err != nil {
+		return nil, "", err
+	}
+	return content, base + ".pdf", nil
+}
@@ -18,6 +18,7 @@ type ProjectService struct {
 	budgetRepo  *repository.BudgetRepository
 	userRepo    *repository.UserRepository
 	planRepo    *repository.ProjectPlanRepository
+	clientRepo  *repository.ClientRepository
 }
 
 func NewProjectService(
@@ -26,6 +27,7 @@ func NewProjectService(
 	budgetRepo *repository.BudgetRepository,
 	userRepo *repository.UserRepository,
 	planRepo *repository.ProjectPlanRepository,
+	clientRepo *repository.ClientRepository,
 ) *ProjectService {
 	return &ProjectService{
 		projectRepo: projectRepo,
@@ -33,6 +35,7 @@ func NewProjectService(
 		budgetRepo:  budgetRepo,
 		userRepo:    userRepo,
 		planRepo:    planRepo,
+		clientRepo:  clientRepo,
 	}
 }
 
@@ -44,11 +47,29 @@ func (s *ProjectService) Create(ctx context.Context, req *request.CreateProjectR
 		CreatedBy:   userID,
 	}
 
+	clientName := ""
+	if req.ClientID != nil && *req.ClientID != 0 {
+		client, err := s.clientRepo.FindByID(ctx, *req.ClientID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("client not found")
+			}
+			return nil, err
+		}
+		project.ClientID = &client.ID
+		clientName = client.Name
+	}
+
 	// Build plan items (rencana anggaran detail)
 	planItems := buildPlanItems(req)
 
+	policy := model.OverspendWarn
+	if req.OverspendPolicy != "" {
+		policy = model.OverspendPolicy(req.OverspendPolicy)
+	}
+
 	// total_budget is always from manual input
-	id, err := s.projectRepo.CreateWithBudget(ctx, project, req.TotalBudget)
+	id, err := s.projectRepo.CreateWithBudget(ctx, project, req.TotalBudget, policy)
 	if err != nil {
 		return nil, fmt.Errorf("create project: %w", err)
 	}
@@ -61,13 +82,16 @@ func (s *ProjectService) Create(ctx context.Context, req *request.CreateProjectR
 	}
 
 	return &response.ProjectResponse{
-		ID:          id,
-		Name:        project.Name,
-		Description: project.Description,
-		Status:      string(project.Status),
-		TotalBudget: req.TotalBudget,
-		SpentAmount: 0,
-		CreatedBy:   userID,
+		ID:              id,
+		Name:            project.Name,
+		Description:     project.Description,
+		ClientID:        project.ClientID,
+		ClientName:      clientName,
+		Status:          string(project.Status),
+		TotalBudget:     req.TotalBudget,
+		SpentAmount:     0,
+		CreatedBy:       userID,
+		OverspendPolicy: string(policy),
 	}, nil
 }
 
@@ -89,6 +113,8 @@ func (s *ProjectService) GetByID(ctx context.Context, id uint64) (*response.Proj
 		ID:          project.ID,
 		Name:        project.Name,
 		Description: project.Description,
+		ClientID:    project.ClientID,
+		ClientName:  s.clientName(ctx, project.ClientID),
 		Status:      string(project.Status),
 		CreatedBy:   project.CreatedBy,
 		CreatedAt:   project.CreatedAt,
@@ -98,6 +124,7 @@ func (s *ProjectService) GetByID(ctx context.Context, id uint64) (*response.Proj
 	if budget != nil {
 		resp.TotalBudget = budget.TotalBudget
 		resp.SpentAmount = budget.SpentAmount
+		resp.OverspendPolicy = string(budget.OverspendPolicy)
 	}
 
 	return resp, nil
@@ -124,6 +151,8 @@ func (s *ProjectService) List(ctx context.Context, userID uint64, role string) (
 			ID:          p.ID,
 			Name:        p.Name,
 			Description: p.Description,
+			ClientID:    p.ClientID,
+			ClientName:  s.clientName(ctx, p.ClientID),
 			Status:      string(p.Status),
 			CreatedBy:   p.CreatedBy,
 			CreatedAt:   p.CreatedAt,
@@ -133,6 +162,7 @@ func (s *ProjectService) List(ctx context.Context, userID uint64, role string) (
 		if budget != nil {
 			resp.TotalBudget = budget.TotalBudget
 			resp.SpentAmount = budget.SpentAmount
+			resp.OverspendPolicy = string(budget.OverspendPolicy)
 		}
 
 		result = append(result, resp)
@@ -159,10 +189,29 @@ func (s *ProjectService) Update(ctx context.Context, id uint64, req *request.Upd
 	if req.Status != "" {
 		project.Status = model.ProjectStatus(req.Status)
 	}
+	// client_id 0 unlinks the client
+	if req.ClientID != nil {
+		if *req.ClientID == 0 {
+			project.ClientID = nil
+		} else {
+			if _, err := s.clientRepo.FindByID(ctx, *req.ClientID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, fmt.Errorf("client not found")
+				}
+				return nil, err
+			}
+			project.ClientID = req.ClientID
+		}
+	}
 
 	if err := s.projectRepo.Update(ctx, project); err != nil {
 		return nil, fmt.Errorf("update project: %w", err)
 	}
+	if req.OverspendPolicy != "" {
+		if err := s.budgetRepo.UpdateOverspendPolicy(ctx, id, model.OverspendPolicy(req.OverspendPolicy)); err != nil {
+			return nil, fmt.Errorf("update overspend policy: %w", err)
+		}
+	}
 
 	return s.GetByID(ctx, id)
 }
@@ -334,6 +383,18 @@ func (s *ProjectService) ListMembers(ctx context.Context, projectID uint64) ([]r
 	return result, nil
 }
 
+// clientName returns the name of the linked client, or "" if none.
+func (s *ProjectService) clientName(ctx context.Context, clientID *uint64) string {
+	if clientID == nil {
+		return ""
+	}
+	client, err := s.clientRepo.FindByID(ctx, *clientID)
+	if err != nil {
+		return ""
+	}
+	return client.Name
+}
+
 // buildPlanItems converts create request plan fields into model items
 func buildPlanItems(req *request.CreateProjectRequest) []model.ProjectPlanItem {
 	var items []model.ProjectPlanItem
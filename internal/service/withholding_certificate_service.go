@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/request"
+	"github.com/gilangrmdnii/invoice-backend/internal/dto/response"
+	"github.com/gilangrmdnii/invoice-backend/internal/model"
+	"github.com/gilangrmdnii/invoice-backend/internal/repository"
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+// Status of an invoice in the withholding report whose certificate has not
+// arrived yet
+const withholdingMissing = "MISSING"
+
+type WithholdingCertificateService struct {
+	certRepo       *repository.WithholdingCertificateRepository
+	invoiceRepo    *repository.InvoiceRepository
+	creditNoteRepo *repository.CreditNoteRepository
+	auditRepo      *repository.AuditLogRepository
+}
+
+func NewWithholdingCertificateService(
+	certRepo *repository.WithholdingCertificateRepository,
+	invoiceRepo *repository.InvoiceRepository,
+	creditNoteRepo *repository.CreditNoteRepository,
+	auditRepo *repository.AuditLogRepository,
+) *WithholdingCertificateService {
+	return &WithholdingCertificateService{
+		certRepo:       certRepo,
+		invoiceRepo:    invoiceRepo,
+		creditNoteRepo: creditNoteRepo,
+		auditRepo:      auditRepo,
+	}
+}
+
+func (s *WithholdingCertificateService) logAudit(ctx context.Context, userID uint64, action string, entityID uint64, details string) {
+	_, err := s.auditRepo.Create(ctx, &model.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: "withholding_certificate",
+		EntityID:   entityID,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("audit log error: %v", err)
+	}
+}
+
+func (s *WithholdingCertificateService) Create(ctx context.Context, invoiceID uint64, req *request.CreateWithholdingCertificateRequest, userID uint64) (*response.WithholdingCertificateResponse, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("invoice not found")
+		}
+		return nil, err
+	}
+	if inv.Status != model.InvoiceStatusApproved {
+		return nil, fmt.Errorf("withholding certificates can only be recorded for approved invoices")
+	}
+
+	taxType := model.PPHCode(req.TaxType)
+	if taxType == "" {
+		taxType = model.PPHCode23
+	}
+	expected, err := s.expectedWithholding(ctx, inv, taxType)
+	if err != nil {
+		return nil, err
+	}
+	if expected <= 0 {
+		return nil, fmt.Errorf("invoice has no withholding for this tax type")
+	}
+
+	wc := &model.WithholdingCertificate{
+		InvoiceID: inv.ID,
+		TaxType:   taxType,
+		CreatedBy: userID,
+	}
+	if err := applyCertificateDetails(wc, req.CertificateNumber, req.CertificateDate, req.WithheldAmount, expected); err != nil {
+		return nil, err
+	}
+	wc.FileURL = req.FileURL
+	wc.Notes = req.Notes
+
+	id, err := s.certRepo.Create(ctx, wc)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "CREATE", id, fmt.Sprintf("invoice=%s, tax=%s, status=%s", inv.InvoiceNumber, wc.TaxType, wc.Status))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *WithholdingCertificateService) Update(ctx context.Context, id uint64, req *request.UpdateWithholdingCertificateRequest, userID uint64) (*response.WithholdingCertificateResponse, error) {
+	wc, err := s.certRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("withholding certificate not found")
+		}
+		return nil, err
+	}
+	inv, err := s.invoiceRepo.FindByID(ctx, wc.InvoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("get invoice: %w", err)
+	}
+
+	expected, err := s.expectedWithholding(ctx, inv, wc.TaxType)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyCertificateDetails(wc, req.CertificateNumber, req.CertificateDate, req.WithheldAmount, expected); err != nil {
+		return nil, err
+	}
+	wc.FileURL = req.FileURL
+	wc.Notes = req.Notes
+
+	if err := s.certRepo.Update(ctx, wc); err != nil {
+		return nil, err
+	}
+
+	s.logAudit(ctx, userID, "UPDATE", id, fmt.Sprintf("invoice=%s, tax=%s, status=%s", inv.InvoiceNumber, wc.TaxType, wc.Status))
+
+	return s.GetByID(ctx, id)
+}
+
+func (s *WithholdingCertificateService) Delete(ctx context.Context, id uint64, userID uint64) error {
+	wc, err := s.certRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("withholding certificate not found")
+		}
+		return err
+	}
+	if err := s.certRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logAudit(ctx, userID, "DELETE", id, fmt.Sprintf("invoice=%d, tax=%s", wc.InvoiceID, wc.TaxType))
+	return nil
+}
+
+func (s *WithholdingCertificateService) GetByID(ctx context.Context, id uint64) (*response.WithholdingCertificateResponse, error) {
+	wc, err := s.certRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("withholding certificate not found")
+		}
+		return nil, err
+	}
+
+	resp := toWithholdingCertificateResponse(wc)
+	if inv, err := s.invoiceRepo.FindByID(ctx, wc.InvoiceID); err == nil {
+		resp.InvoiceNumber = inv.InvoiceNumber
+	}
+	return &resp, nil
+}
+
+func (s *WithholdingCertificateService) ListByInvoice(ctx context.Context, invoiceID uint64) ([]response.WithholdingCertificateResponse, error) {
+	certs, err := s.certRepo.FindByInvoiceID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]response.WithholdingCertificateResponse, 0, len(certs))
+	for i := range certs {
+		result = append(result, toWithholdingCertificateResponse(&certs[i]))
+	}
+	return result, nil
+}
+
+// Report lists the approved invoices of a tax period (by invoice date) that
+// had PPh withheld and whose certificate is missing or does not match. The
+// comparison uses the current PPh of the invoice, so a certificate that
+// matched before a credit note was issued is reported as a mismatch.
+func (s *WithholdingCertificateService) Report(ctx context.Context, req *request.WithholdingReportRequest) (*response.WithholdingReportResponse, error) {
+	from, err := time.Parse("2006-01", req.Period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid period format, use YYYY-MM")
+	}
+	to := from.AddDate(0, 1, -1)
+
+	invoices, err := s.invoiceRepo.FindApprovedByDate(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get approved invoices: %w", err)
+	}
+	certs, err := s.certRepo.FindByInvoiceDate(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get withholding certificates: %w", err)
+	}
+	type certKey struct {
+		invoiceID uint64
+		taxType   model.PPHCode
+	}
+	byInvoice := make(map[certKey]*model.WithholdingCertificate, len(certs))
+	for i := range certs {
+		byInvoice[certKey{certs[i].InvoiceID, certs[i].TaxType}] = &certs[i]
+	}
+
+	report := &response.WithholdingReportResponse{
+		Period:   from.Format("2006-01"),
+		Invoices: []response.WithholdingReportLineResponse{},
+	}
+	for i := range invoices {
+		inv := &invoices[i]
+		for _, taxType := range []model.PPHCode{model.PPHCode23, model.PPHCode21} {
+			expected, err := s.expectedWithholding(ctx, inv, taxType)
+			if err != nil {
+				return nil, err
+			}
+			if expected <= 0 {
+				continue
+			}
+			report.ExpectedCount++
+			report.ExpectedAmount += expected
+
+			line := response.WithholdingReportLineResponse{
+				InvoiceID:      inv.ID,
+				InvoiceNumber:  inv.InvoiceNumber,
+				InvoiceDate:    inv.InvoiceDate.Format("2006-01-02"),
+				RecipientName:  inv.RecipientName,
+				RecipientNPWP:  inv.RecipientNPWP,
+				TaxType:        string(taxType),
+				ExpectedAmount: expected,
+				Status:         withholdingMissing,
+			}
+			wc := byInvoice[certKey{inv.ID, taxType}]
+			if wc != nil && wc.Status != model.WithholdingCertificateExpected {
+				if wc.WithheldAmount == expected {
+					report.ReceivedCount++
+					continue
+				}
+				line.Status = string(model.WithholdingCertificateMismatch)
+			}
+			if wc != nil {
+				id := wc.ID
+				line.CertificateID = &id
+				line.CertificateNumber = wc.CertificateNumber
+				line.WithheldAmount = wc.WithheldAmount
+			}
+
+			if line.Status == withholdingMissing {
+				report.MissingCount++
+				report.MissingAmount += expected
+			} else {
+				report.MismatchCount++
+			}
+			report.Invoices = append(report.Invoices, line)
+		}
+	}
+	return report, nil
+}
+
+// expectedWithholding returns the PPh of the tax type the client should have
+// withheld on an invoice: its PPh 23 or PPh 21 less the PPh of that type
+// reversed by credit notes.
+func (s *WithholdingCertificateService) expectedWithholding(ctx context.Context, inv *model.Invoice, taxType model.PPHCode) (money.Money, error) {
+	expected := inv.PPHAmount
+	if taxType == model.PPHCode21 {
+		expected = inv.PPH21Amount
+	}
+	if expected <= 0 || inv.CreditedAmount == 0 {
+		return expected, nil
+	}
+	notes, err := s.creditNoteRepo.FindByInvoiceID(ctx, inv.ID)
+	if err != nil {
+		return 0, fmt.Errorf("get credit notes: %w", err)
+	}
+	for _, cn := range notes {
+		if taxType == model.PPHCode21 {
+			expected -= cn.PPH21Amount
+		} else {
+			expected -= cn.PPHAmount
+		}
+	}
+	return expected, nil
+}
+
+// applyCertificateDetails sets the certificate number, date and withheld
+// amount and derives the status: EXPECTED until a certificate number is
+// given, then RECEIVED or MISMATCH by comparing the withheld amount with the
+// expected one.
+func applyCertificateDetails(wc *model.WithholdingCertificate, number, date string, withheld, expected money.Money) error {
+	wc.CertificateNumber = number
+	wc.WithheldAmount = withheld
+	wc.ExpectedAmount = expected
+	wc.CertificateDate = nil
+	if date != "" {
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return fmt.Errorf("invalid certificate date format, use YYYY-MM-DD")
+		}
+		wc.CertificateDate = &d
+	}
+
+	switch {
+	case number == "":
+		wc.Status = model.WithholdingCertificateExpected
+	case wc.CertificateDate == nil:
+		return fmt.Errorf("certificate_date is required with a certificate number")
+	case withheld == expected:
+		wc.Status = model.WithholdingCertificateReceived
+	default:
+		wc.Status = model.WithholdingCertificateMismatch
+	}
+	return nil
+}
+
+func toWithholdingCertificateResponse(wc *model.WithholdingCertificate) response.WithholdingCertificateResponse {
+	resp := response.WithholdingCertificateResponse{
+		ID:                wc.ID,
+		InvoiceID:         wc.InvoiceID,
+		TaxType:           string(wc.TaxType),
+		CertificateNumber: wc.CertificateNumber,
+		WithheldAmount:    wc.WithheldAmount,
+		ExpectedAmount:    wc.ExpectedAmount,
+		FileURL:           wc.FileURL,
+		Status:            string(wc.Status),
+		Notes:             wc.Notes,
+		CreatedBy:         wc.CreatedBy,
+		CreatedAt:         wc.CreatedAt,
+		UpdatedAt:         wc.UpdatedAt,
+	}
+	if wc.CertificateDate != nil {
+		resp.CertificateDate = wc.CertificateDate.Format("2006-01-02")
+	}
+	return resp
+}
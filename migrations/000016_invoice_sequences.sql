@@ -0,0 +1,23 @@
+-- Gap-free invoice numbering: per company code + year sequence, assigned at approval
+
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+    company_code VARCHAR(10) NOT NULL,
+    year SMALLINT UNSIGNED NOT NULL,
+    last_number INT UNSIGNED NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (company_code, year)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE company_settings
+    ADD COLUMN invoice_number_pattern VARCHAR(100) NOT NULL DEFAULT '{SEQ:3}/INV/{COMPANY}/{MM}/{YYYY}' AFTER signatory_title;
+
+-- Seed sequences above the old id-based numbers so new numbers never collide with them
+INSERT INTO invoice_sequences (company_code, year, last_number)
+SELECT cs.company_code, YEAR(i.invoice_date), MAX(i.id)
+FROM invoices i
+CROSS JOIN (SELECT company_code FROM company_settings ORDER BY id LIMIT 1) cs
+WHERE i.status <> 'PENDING'
+GROUP BY cs.company_code, YEAR(i.invoice_date);
+
+-- Pending invoices become drafts, they receive a number when approved
+UPDATE invoices SET invoice_number = CONCAT('DRAFT-', id) WHERE status = 'PENDING';
@@ -0,0 +1,54 @@
+-- Credit notes against approved invoices, and VOID status for invoices
+
+-- 1. Invoice: VOID status, credited amount and void tracking
+ALTER TABLE invoices
+    MODIFY COLUMN status ENUM('PENDING','APPROVED','REJECTED','VOID') NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN credited_amount DECIMAL(18,2) NOT NULL DEFAULT 0.00 AFTER paid_amount,
+    ADD COLUMN void_reason TEXT AFTER reject_notes,
+    ADD COLUMN voided_by BIGINT UNSIGNED DEFAULT NULL AFTER void_reason,
+    ADD COLUMN voided_at TIMESTAMP NULL DEFAULT NULL AFTER voided_by,
+    ADD CONSTRAINT fk_inv_voided_by FOREIGN KEY (voided_by) REFERENCES users(id);
+
+-- 2. Sequences are now per document type (INV, CN, ...)
+ALTER TABLE invoice_sequences
+    ADD COLUMN doc_type VARCHAR(10) NOT NULL DEFAULT 'INV' FIRST,
+    DROP PRIMARY KEY,
+    ADD PRIMARY KEY (doc_type, company_code, year);
+
+ALTER TABLE company_settings
+    ADD COLUMN credit_note_number_pattern VARCHAR(100) NOT NULL DEFAULT '{SEQ:3}/CN/{COMPANY}/{MM}/{YYYY}' AFTER invoice_number_pattern;
+
+-- 3. Credit notes
+CREATE TABLE IF NOT EXISTS credit_notes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    credit_note_number VARCHAR(50) NOT NULL UNIQUE,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    credit_date DATE NOT NULL,
+    reason TEXT NOT NULL,
+    subtotal DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    ppn_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ppn_amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    pph_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    pph_amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_cn_invoice (invoice_id),
+    CONSTRAINT fk_cn_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_cn_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS credit_note_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    credit_note_id BIGINT UNSIGNED NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    quantity DECIMAL(10,2) NOT NULL DEFAULT 1.00,
+    unit VARCHAR(50) NOT NULL DEFAULT 'unit',
+    unit_price DECIMAL(18,2) NOT NULL,
+    subtotal DECIMAL(18,2) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_cni_credit_note (credit_note_id),
+    CONSTRAINT fk_cni_credit_note FOREIGN KEY (credit_note_id) REFERENCES credit_notes(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,11 @@
+-- Overdue invoice reminders sent by the background scheduler.
+-- One row per invoice and reminder stage (days past due) so a stage is never sent twice.
+
+CREATE TABLE IF NOT EXISTS invoice_reminders (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    days_overdue INT NOT NULL,
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_invoice_reminder_stage (invoice_id, days_overdue),
+    CONSTRAINT fk_ir_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
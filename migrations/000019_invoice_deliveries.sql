@@ -0,0 +1,18 @@
+-- Invoice email delivery log: one row per send attempt
+
+CREATE TABLE IF NOT EXISTS invoice_deliveries (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    channel ENUM('EMAIL') NOT NULL DEFAULT 'EMAIL',
+    recipients TEXT NOT NULL,
+    cc TEXT,
+    subject VARCHAR(255) NOT NULL,
+    status ENUM('PENDING','SENT','FAILED') NOT NULL DEFAULT 'PENDING',
+    error_message TEXT,
+    sent_by BIGINT UNSIGNED NOT NULL,
+    sent_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_delivery_invoice (invoice_id),
+    CONSTRAINT fk_delivery_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_delivery_sent_by FOREIGN KEY (sent_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
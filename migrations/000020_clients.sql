@@ -0,0 +1,43 @@
+-- Client master data: billing identity, contact persons and default payment terms
+
+CREATE TABLE IF NOT EXISTS clients (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    npwp VARCHAR(30) DEFAULT NULL,
+    billing_address TEXT,
+    email VARCHAR(255) DEFAULT NULL,
+    phone VARCHAR(50) DEFAULT NULL,
+    payment_terms_days INT NOT NULL DEFAULT 30,
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_clients_name (name),
+    CONSTRAINT fk_clients_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS client_contacts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    client_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    position VARCHAR(100) DEFAULT NULL,
+    email VARCHAR(255) DEFAULT NULL,
+    phone VARCHAR(50) DEFAULT NULL,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_order INT NOT NULL DEFAULT 0,
+    INDEX idx_contacts_client (client_id),
+    CONSTRAINT fk_contacts_client FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Projects and invoices reference a client. Invoices keep a snapshot of the
+-- recipient fields so issued documents do not change when the client is edited.
+ALTER TABLE projects
+    ADD COLUMN client_id BIGINT UNSIGNED DEFAULT NULL AFTER description,
+    ADD CONSTRAINT fk_projects_client FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE RESTRICT;
+
+ALTER TABLE invoices
+    ADD COLUMN client_id BIGINT UNSIGNED DEFAULT NULL AFTER project_id,
+    ADD COLUMN recipient_npwp VARCHAR(30) DEFAULT NULL AFTER recipient_address,
+    ADD INDEX idx_invoices_client (client_id),
+    ADD CONSTRAINT fk_invoices_client FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE RESTRICT;
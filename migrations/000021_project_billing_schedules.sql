@@ -0,0 +1,33 @@
+-- Per-project billing schedule: contract value (before tax) and payment terms (DP, TOP, final payment)
+
+CREATE TABLE IF NOT EXISTS project_billing_schedules (
+    project_id BIGINT UNSIGNED PRIMARY KEY,
+    contract_value DECIMAL(18,2) NOT NULL,
+    ppn_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    pph_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    notes TEXT,
+    updated_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_pbs_project FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pbs_updated_by FOREIGN KEY (updated_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS project_billing_terms (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    project_id BIGINT UNSIGNED NOT NULL,
+    invoice_type ENUM('DP','TOP_1','TOP_2','TOP_3','FINAL_PAYMENT') NOT NULL,
+    description VARCHAR(500) NOT NULL,
+    percentage DECIMAL(5,2) NOT NULL,
+    amount DECIMAL(18,2) NOT NULL,
+    expected_date DATE DEFAULT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    UNIQUE KEY uk_term_project_type (project_id, invoice_type),
+    CONSTRAINT fk_pbt_schedule FOREIGN KEY (project_id) REFERENCES project_billing_schedules(project_id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Invoices generated from a term point back to it
+ALTER TABLE invoices
+    ADD COLUMN billing_term_id BIGINT UNSIGNED DEFAULT NULL AFTER client_id,
+    ADD INDEX idx_invoices_billing_term (billing_term_id),
+    ADD CONSTRAINT fk_invoices_billing_term FOREIGN KEY (billing_term_id) REFERENCES project_billing_terms(id) ON DELETE SET NULL;
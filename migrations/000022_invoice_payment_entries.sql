@@ -0,0 +1,8 @@
+-- Payment ledger entries: reversals and refunds are recorded instead of deleting payments
+
+ALTER TABLE invoice_payments
+    ADD COLUMN entry_type ENUM('PAYMENT','REVERSAL','REFUND') NOT NULL DEFAULT 'PAYMENT' AFTER invoice_id,
+    ADD COLUMN reversed_payment_id BIGINT UNSIGNED DEFAULT NULL AFTER entry_type,
+    ADD COLUMN reason TEXT DEFAULT NULL AFTER proof_url,
+    ADD UNIQUE INDEX idx_ip_reversed_payment (reversed_payment_id),
+    ADD CONSTRAINT fk_ip_reversed_payment FOREIGN KEY (reversed_payment_id) REFERENCES invoice_payments(id) ON DELETE RESTRICT;
@@ -0,0 +1,39 @@
+-- Bank statement import and reconciliation of incoming transfers against invoices
+
+CREATE TABLE IF NOT EXISTS bank_statements (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    format VARCHAR(20) NOT NULL,
+    account_number VARCHAR(50) NOT NULL DEFAULT '',
+    file_name VARCHAR(255) NOT NULL,
+    period_start DATE DEFAULT NULL,
+    period_end DATE DEFAULT NULL,
+    transaction_count INT NOT NULL DEFAULT 0,
+    duplicate_count INT NOT NULL DEFAULT 0,
+    uploaded_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_bs_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- fingerprint identifies a transaction across overlapping statement files
+CREATE TABLE IF NOT EXISTS bank_transactions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    statement_id BIGINT UNSIGNED NOT NULL,
+    transaction_date DATE NOT NULL,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    amount DECIMAL(18,2) NOT NULL,
+    direction ENUM('CREDIT','DEBIT') NOT NULL,
+    status ENUM('UNMATCHED','MATCHED','IGNORED') NOT NULL DEFAULT 'UNMATCHED',
+    invoice_id BIGINT UNSIGNED DEFAULT NULL,
+    payment_id BIGINT UNSIGNED DEFAULT NULL,
+    matched_by BIGINT UNSIGNED DEFAULT NULL,
+    matched_at TIMESTAMP NULL DEFAULT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_bt_fingerprint (fingerprint),
+    INDEX idx_bt_statement (statement_id, status),
+    CONSTRAINT fk_bt_statement FOREIGN KEY (statement_id) REFERENCES bank_statements(id) ON DELETE CASCADE,
+    CONSTRAINT fk_bt_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id),
+    CONSTRAINT fk_bt_payment FOREIGN KEY (payment_id) REFERENCES invoice_payments(id),
+    CONSTRAINT fk_bt_matched_by FOREIGN KEY (matched_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,45 @@
+-- Payment gateway charges (virtual account and QRIS) and received webhook callbacks
+
+ALTER TABLE invoice_payments
+    MODIFY COLUMN payment_method ENUM('TRANSFER','CASH','GIRO','OTHER','VIRTUAL_ACCOUNT','QRIS') NOT NULL DEFAULT 'TRANSFER';
+
+CREATE TABLE IF NOT EXISTS payment_charges (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    method ENUM('VIRTUAL_ACCOUNT','QRIS') NOT NULL,
+    bank VARCHAR(20) NOT NULL DEFAULT '',
+    reference VARCHAR(100) NOT NULL,
+    va_number VARCHAR(50) NOT NULL DEFAULT '',
+    qr_string TEXT,
+    amount DECIMAL(18,2) NOT NULL,
+    status ENUM('PENDING','PAID','EXPIRED','FAILED') NOT NULL DEFAULT 'PENDING',
+    expires_at TIMESTAMP NOT NULL,
+    paid_at TIMESTAMP NULL DEFAULT NULL,
+    payment_id BIGINT UNSIGNED DEFAULT NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_pc_reference (provider, reference),
+    INDEX idx_pc_invoice (invoice_id, status),
+    CONSTRAINT fk_pc_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pc_payment FOREIGN KEY (payment_id) REFERENCES invoice_payments(id),
+    CONSTRAINT fk_pc_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- One row per callback event. The unique event id makes redelivered callbacks no-ops.
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    reference VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    amount DECIMAL(18,2) NOT NULL DEFAULT 0,
+    payload TEXT NOT NULL,
+    result ENUM('RECEIVED','PROCESSED','IGNORED','FAILED') NOT NULL DEFAULT 'RECEIVED',
+    error VARCHAR(500) NOT NULL DEFAULT '',
+    charge_id BIGINT UNSIGNED DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_pwe_event (provider, event_id),
+    CONSTRAINT fk_pwe_charge FOREIGN KEY (charge_id) REFERENCES payment_charges(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
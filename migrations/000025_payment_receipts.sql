@@ -0,0 +1,10 @@
+-- Payment receipts (kwitansi): a receipt number per payment with its own sequence, and the stamp duty threshold
+
+ALTER TABLE invoice_payments
+    ADD COLUMN receipt_number VARCHAR(50) DEFAULT NULL AFTER entry_type,
+    ADD UNIQUE INDEX idx_ip_receipt_number (receipt_number);
+
+-- Receipts above the threshold need a Rp10.000 meterai (UU 10/2020)
+ALTER TABLE company_settings
+    ADD COLUMN receipt_number_pattern VARCHAR(100) NOT NULL DEFAULT '{SEQ:3}/KWT/{COMPANY}/{MM}/{YYYY}' AFTER credit_note_number_pattern,
+    ADD COLUMN stamp_duty_threshold DECIMAL(18,2) NOT NULL DEFAULT 5000000 AFTER receipt_number_pattern;
@@ -0,0 +1,14 @@
+-- Invoice revisions: an immutable snapshot of the invoice header and items taken on every save
+
+CREATE TABLE IF NOT EXISTS invoice_revisions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    revision_number INT UNSIGNED NOT NULL,
+    action ENUM('CREATE', 'UPDATE', 'BASELINE') NOT NULL,
+    snapshot JSON NOT NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_ir_invoice_revision (invoice_id, revision_number),
+    CONSTRAINT fk_ir_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ir_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,44 @@
+-- Multi-level invoice approval: policies by amount threshold and invoice type, and the approval steps of each invoice
+
+CREATE TABLE IF NOT EXISTS approval_policies (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    invoice_type ENUM('DP','FINAL_PAYMENT','TOP_1','TOP_2','TOP_3','MEALS','ADDITIONAL') DEFAULT NULL,
+    min_amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_ap_match (is_active, min_amount),
+    CONSTRAINT fk_ap_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- approver_roles is a comma-separated list of roles allowed to act on the step
+CREATE TABLE IF NOT EXISTS approval_policy_steps (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    policy_id BIGINT UNSIGNED NOT NULL,
+    step_order INT NOT NULL,
+    approver_roles VARCHAR(100) NOT NULL,
+    UNIQUE INDEX idx_aps_policy_step (policy_id, step_order),
+    CONSTRAINT fk_aps_policy FOREIGN KEY (policy_id) REFERENCES approval_policies(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Steps are copied from the policy when an invoice is submitted, so later policy
+-- changes do not affect invoices in review. Each resubmission starts a new round.
+CREATE TABLE IF NOT EXISTS invoice_approval_steps (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    round INT NOT NULL,
+    step_order INT NOT NULL,
+    policy_id BIGINT UNSIGNED DEFAULT NULL,
+    approver_roles VARCHAR(100) NOT NULL,
+    status ENUM('PENDING','APPROVED','REJECTED','SKIPPED') NOT NULL DEFAULT 'PENDING',
+    acted_by BIGINT UNSIGNED DEFAULT NULL,
+    acted_at TIMESTAMP NULL DEFAULT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_ias_invoice_step (invoice_id, round, step_order),
+    CONSTRAINT fk_ias_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ias_policy FOREIGN KEY (policy_id) REFERENCES approval_policies(id) ON DELETE SET NULL,
+    CONSTRAINT fk_ias_acted_by FOREIGN KEY (acted_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,9 @@
+-- Indexes backing the invoice listing filters and keyset pagination
+
+ALTER TABLE invoices
+    ADD INDEX idx_invoices_created (created_at, id),
+    ADD INDEX idx_invoices_date (invoice_date, id),
+    ADD INDEX idx_invoices_due (due_date),
+    ADD INDEX idx_invoices_amount (amount, id),
+    ADD INDEX idx_invoices_payment_status (payment_status),
+    ADD INDEX idx_invoices_project_created (project_id, created_at, id);
@@ -0,0 +1,27 @@
+-- Saved invoice templates: recipient defaults, taxes, language, notes and the lines to bill
+
+CREATE TABLE IF NOT EXISTS invoice_templates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    invoice_type ENUM('DP', 'FINAL_PAYMENT', 'TOP_1', 'TOP_2', 'TOP_3', 'MEALS', 'ADDITIONAL') NOT NULL,
+    project_id BIGINT UNSIGNED DEFAULT NULL,
+    client_id BIGINT UNSIGNED DEFAULT NULL,
+    recipient_name VARCHAR(255) NOT NULL DEFAULT '',
+    recipient_address TEXT,
+    recipient_npwp VARCHAR(30) NOT NULL DEFAULT '',
+    attention VARCHAR(255) NOT NULL DEFAULT '',
+    dp_percentage DECIMAL(5,2) DEFAULT NULL,
+    ppn_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    pph_percentage DECIMAL(5,2) NOT NULL DEFAULT 0,
+    language ENUM('ID', 'EN') NOT NULL DEFAULT 'ID',
+    notes TEXT,
+    items JSON NOT NULL,
+    labels JSON NOT NULL,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_it_name (name),
+    CONSTRAINT fk_it_project FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL,
+    CONSTRAINT fk_it_client FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE SET NULL,
+    CONSTRAINT fk_it_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
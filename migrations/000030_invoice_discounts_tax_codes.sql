@@ -0,0 +1,22 @@
+-- Per-line discounts and tax codes, invoice-level discounts and PPh 21.
+-- Existing lines keep the previous behaviour: PPN and PPh 23 apply to every line.
+
+ALTER TABLE invoice_items
+    ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER unit_price,
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_percent,
+    ADD COLUMN ppn_code ENUM('STANDARD', 'EXEMPT') NOT NULL DEFAULT 'STANDARD' AFTER subtotal,
+    ADD COLUMN pph_code ENUM('PPH23', 'PPH21', 'NONE') NOT NULL DEFAULT 'PPH23' AFTER ppn_code;
+
+ALTER TABLE invoices
+    ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER subtotal,
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_percent,
+    ADD COLUMN dpp_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_amount,
+    ADD COLUMN pph21_percentage DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER pph_amount,
+    ADD COLUMN pph21_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER pph21_percentage;
+
+UPDATE invoices SET dpp_amount = subtotal;
+
+ALTER TABLE invoice_templates
+    ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER dp_percentage,
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_percent,
+    ADD COLUMN pph21_percentage DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER pph_percentage;
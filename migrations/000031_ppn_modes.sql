@@ -0,0 +1,27 @@
+-- PPN calculation modes: STANDARD charges PPN on the DPP, DPP_NILAI_LAIN on a DPP nilai lain of 11/12 of it (PMK 131/2024).
+-- The PPN base is stored on every invoice. Existing invoices keep the standard mode and their amounts.
+
+ALTER TABLE invoices
+    ADD COLUMN ppn_mode ENUM('STANDARD', 'DPP_NILAI_LAIN') NOT NULL DEFAULT 'STANDARD' AFTER dpp_amount,
+    ADD COLUMN ppn_base DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER ppn_mode;
+
+UPDATE invoices SET ppn_base = dpp_amount;
+
+-- Lines exempt from PPN are left out of the base, with their share of the invoice discount
+UPDATE invoices i
+JOIN (
+    SELECT invoice_id, SUM(subtotal - discount_amount) AS exempt
+    FROM invoice_items
+    WHERE is_label = FALSE AND ppn_code = 'EXEMPT'
+    GROUP BY invoice_id
+) x ON x.invoice_id = i.id
+SET i.ppn_base = i.dpp_amount - ROUND(x.exempt * i.dpp_amount / i.subtotal, 2)
+WHERE i.subtotal > 0;
+
+-- Defaults for new invoices. A rate of 0 keeps invoices without a ppn_percentage free of PPN as before.
+ALTER TABLE company_settings
+    ADD COLUMN default_ppn_mode ENUM('STANDARD', 'DPP_NILAI_LAIN') NOT NULL DEFAULT 'STANDARD' AFTER stamp_duty_threshold,
+    ADD COLUMN default_ppn_percentage DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER default_ppn_mode;
+
+ALTER TABLE invoice_templates
+    ADD COLUMN ppn_mode ENUM('STANDARD', 'DPP_NILAI_LAIN') DEFAULT NULL AFTER discount_amount;
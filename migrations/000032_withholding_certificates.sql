@@ -0,0 +1,21 @@
+-- Withholding tax certificates (bukti potong) received from clients for the PPh they withheld on invoices
+
+CREATE TABLE IF NOT EXISTS withholding_certificates (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    invoice_id BIGINT UNSIGNED NOT NULL,
+    tax_type ENUM('PPH23', 'PPH21') NOT NULL DEFAULT 'PPH23',
+    certificate_number VARCHAR(100) NOT NULL DEFAULT '',
+    certificate_date DATE DEFAULT NULL,
+    withheld_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    expected_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    file_url VARCHAR(500) NOT NULL DEFAULT '',
+    status ENUM('EXPECTED', 'RECEIVED', 'MISMATCH') NOT NULL DEFAULT 'EXPECTED',
+    notes TEXT,
+    created_by BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_wc_invoice_tax (invoice_id, tax_type),
+    INDEX idx_wc_status (status),
+    CONSTRAINT fk_wc_invoice FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_wc_created_by FOREIGN KEY (created_by) REFERENCES users(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
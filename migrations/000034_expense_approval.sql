@@ -0,0 +1,4 @@
+-- Expenses now go through approval and only approved ones count in the budget and reports.
+-- Expenses recorded before the workflow were accepted as they were created, so they are marked approved.
+
+UPDATE expenses SET status = 'APPROVED' WHERE status = 'PENDING';
@@ -0,0 +1,10 @@
+-- Per-project overspend policy. WARN lets expenses take spending past total_budget with a warning, BLOCK refuses them.
+ALTER TABLE project_budgets
+    ADD COLUMN overspend_policy ENUM('WARN', 'BLOCK') NOT NULL DEFAULT 'WARN' AFTER spent_amount;
+
+-- Edits to expense amounts were not reflected in spent_amount, so it is rebuilt from the approved expenses
+UPDATE project_budgets pb
+SET pb.spent_amount = (
+    SELECT COALESCE(SUM(e.amount), 0) FROM expenses e
+    WHERE e.project_id = pb.project_id AND e.status = 'APPROVED'
+);
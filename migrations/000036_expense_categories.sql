@@ -0,0 +1,66 @@
+-- Expense category master. Each category maps to a finance report breakdown bucket
+-- and expenses.category holds the category code.
+
+CREATE TABLE IF NOT EXISTS expense_categories (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(100) NOT NULL,
+    label_id VARCHAR(255) NOT NULL,
+    label_en VARCHAR(255) NOT NULL,
+    report_category ENUM('SPV', 'UANG_MAKAN', 'PULSA', 'RECORDING', 'INPUT_PERPI', 'BENSIN', 'BRIEFING', 'TRANSPORT', 'LAIN_LAIN') NOT NULL DEFAULT 'LAIN_LAIN',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_expense_categories_code (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO expense_categories (code, label_id, label_en, report_category) VALUES
+    ('UANG_MAKAN', 'Uang Makan', 'Meal Allowance', 'UANG_MAKAN'),
+    ('PULSA', 'Pulsa', 'Phone Credit', 'PULSA'),
+    ('RECORDING', 'Recording', 'Recording', 'RECORDING'),
+    ('INPUT_PERPI', 'Input Perpi', 'Perpi Input', 'INPUT_PERPI'),
+    ('BENSIN', 'Bensin', 'Fuel', 'BENSIN'),
+    ('BRIEFING', 'Briefing', 'Briefing', 'BRIEFING'),
+    ('TRANSPORT', 'Transport', 'Transport', 'TRANSPORT'),
+    ('SPV', 'SPV', 'Supervisor', 'SPV'),
+    ('LAIN_LAIN', 'Lain-lain', 'Other', 'LAIN_LAIN'),
+    ('BUDGET_REQUEST', 'Pengajuan Budget', 'Budget Request', 'LAIN_LAIN');
+
+-- Codes are normalized like the API does: runs of whitespace become one underscore,
+-- with leading and trailing whitespace dropped, in upper case.
+-- Existing free-text categories: the spellings the finance report already recognised map to their category
+UPDATE expenses SET category = CASE UPPER(REPLACE(TRIM(REGEXP_REPLACE(category, '[[:space:]]+', ' ')), ' ', '_'))
+    WHEN '' THEN 'LAIN_LAIN'
+    WHEN 'UANG_MAKAN' THEN 'UANG_MAKAN'
+    WHEN 'UANG-MAKAN' THEN 'UANG_MAKAN'
+    WHEN 'MAKAN' THEN 'UANG_MAKAN'
+    WHEN 'PULSA' THEN 'PULSA'
+    WHEN 'RECORDING' THEN 'RECORDING'
+    WHEN 'INPUT_PERPI' THEN 'INPUT_PERPI'
+    WHEN 'INPUT-PERPI' THEN 'INPUT_PERPI'
+    WHEN 'PERPI' THEN 'INPUT_PERPI'
+    WHEN 'BENSIN' THEN 'BENSIN'
+    WHEN 'BRIEFING' THEN 'BRIEFING'
+    WHEN 'TRANSPORT' THEN 'TRANSPORT'
+    WHEN 'TRAVEL' THEN 'TRANSPORT'
+    WHEN 'SPV' THEN 'SPV'
+    WHEN 'LAIN_LAIN' THEN 'LAIN_LAIN'
+    WHEN 'LAIN-LAIN' THEN 'LAIN_LAIN'
+    WHEN 'LAINNYA' THEN 'LAIN_LAIN'
+    WHEN 'OTHER' THEN 'LAIN_LAIN'
+    WHEN 'OTHERS' THEN 'LAIN_LAIN'
+    WHEN 'BUDGET_REQUEST' THEN 'BUDGET_REQUEST'
+    ELSE category
+END;
+
+-- Any other category becomes an inactive one under LAIN_LAIN, labelled with its original text,
+-- so past expenses keep it while finance decides whether to remap or activate it
+INSERT INTO expense_categories (code, label_id, label_en, report_category, is_active)
+SELECT UPPER(REPLACE(TRIM(REGEXP_REPLACE(e.category, '[[:space:]]+', ' ')), ' ', '_')), MIN(TRIM(e.category)), MIN(TRIM(e.category)), 'LAIN_LAIN', FALSE
+FROM expenses e
+WHERE e.category NOT IN (SELECT code FROM expense_categories)
+GROUP BY UPPER(REPLACE(TRIM(REGEXP_REPLACE(e.category, '[[:space:]]+', ' ')), ' ', '_'));
+
+UPDATE expenses SET category = UPPER(REPLACE(TRIM(REGEXP_REPLACE(category, '[[:space:]]+', ' ')), ' ', '_'));
+
+ALTER TABLE expenses
+    ADD CONSTRAINT fk_exp_category FOREIGN KEY (category) REFERENCES expense_categories(code);
@@ -0,0 +1,16 @@
+-- Credit notes follow the invoice rules: line discounts and tax codes, a share
+-- of the invoice discount and PPh 21. Existing credit notes had neither.
+
+ALTER TABLE credit_notes
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER subtotal,
+    ADD COLUMN dpp_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_amount,
+    ADD COLUMN pph21_percentage DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER pph_amount,
+    ADD COLUMN pph21_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER pph21_percentage;
+
+UPDATE credit_notes SET dpp_amount = subtotal;
+
+ALTER TABLE credit_note_items
+    ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0 AFTER unit_price,
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER discount_percent,
+    ADD COLUMN ppn_code ENUM('STANDARD', 'EXEMPT') NOT NULL DEFAULT 'STANDARD' AFTER subtotal,
+    ADD COLUMN pph_code ENUM('PPH23', 'PPH21', 'NONE') NOT NULL DEFAULT 'PPH23' AFTER ppn_code;
@@ -0,0 +1,6 @@
+-- Tax invoice number (NSFP) of an approved invoice, taken from the serial
+-- number range allocated by the tax office and required for the e-Faktur import
+
+ALTER TABLE invoices
+    ADD COLUMN tax_invoice_number VARCHAR(13) DEFAULT NULL AFTER recipient_npwp,
+    ADD UNIQUE INDEX idx_invoices_tax_invoice_number (tax_invoice_number);
@@ -0,0 +1,6 @@
+-- Invoices without a due date sort by their invoice date, as in the aging
+-- report. A stored column keeps that sort key indexed for keyset pagination.
+
+ALTER TABLE invoices
+    ADD COLUMN due_sort_date DATE AS (COALESCE(due_date, invoice_date)) STORED AFTER due_date,
+    ADD INDEX idx_invoices_due_sort (due_sort_date, id);
@@ -0,0 +1,26 @@
+-- Credit notes keep the PPN mode of the credited invoice and the base their PPN was charged on,
+-- as invoices do, so the rate and the amount can be shown together.
+
+ALTER TABLE credit_notes
+    ADD COLUMN ppn_mode ENUM('STANDARD', 'DPP_NILAI_LAIN') NOT NULL DEFAULT 'STANDARD' AFTER dpp_amount,
+    ADD COLUMN ppn_base DECIMAL(15,2) NOT NULL DEFAULT 0 AFTER ppn_mode;
+
+-- Existing credit notes take the mode of their invoice
+UPDATE credit_notes cn
+JOIN invoices i ON i.id = cn.invoice_id
+SET cn.ppn_mode = i.ppn_mode;
+
+UPDATE credit_notes SET ppn_base = dpp_amount;
+
+-- Lines exempt from PPN are left out of the base, with their share of the discount
+UPDATE credit_notes cn
+JOIN (
+    SELECT credit_note_id, SUM(subtotal - discount_amount) AS exempt
+    FROM credit_note_items
+    WHERE ppn_code = 'EXEMPT'
+    GROUP BY credit_note_id
+) x ON x.credit_note_id = cn.id
+SET cn.ppn_base = cn.dpp_amount - ROUND(x.exempt * cn.dpp_amount / cn.subtotal, 2)
+WHERE cn.subtotal > 0;
+
+UPDATE credit_notes SET ppn_base = ROUND(ppn_base * 11 / 12, 2) WHERE ppn_mode = 'DPP_NILAI_LAIN';
@@ -0,0 +1,6 @@
+-- A schedule save clears the type of the terms it retypes before setting the
+-- new ones, so that swapping two types does not trip uk_term_project_type.
+-- Saved terms always have a type.
+
+ALTER TABLE project_billing_terms
+    MODIFY COLUMN invoice_type ENUM('DP','TOP_1','TOP_2','TOP_3','FINAL_PAYMENT') NULL;
@@ -0,0 +1,261 @@
+// Package money implements an exact decimal amount with two fractional digits,
+// matching the DECIMAL(18,2) columns used for monetary values.
+//
+// Amounts are stored as an integer number of cents, so additions, subtractions
+// and comparisons are exact. Multiplication by a quantity or a percentage
+// rounds half away from zero to the cent.
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in cents (1/100 of the currency unit).
+type Money int64
+
+const Zero Money = 0
+
+// FromCents returns the amount for a number of cents.
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// FromInt returns the amount for a whole number of currency units.
+func FromInt(units int64) Money {
+	return Money(units * 100)
+}
+
+// Parse parses a decimal string such as "1234", "1234.5" or "-0.25". More than
+// two fractional digits is an error rather than being silently rounded.
+func Parse(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("money: empty amount")
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	if intPart == "" && (!hasFrac || fracPart == "") {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if len(fracPart) > 2 {
+		// Accept trailing zeros from DECIMAL columns with a larger scale
+		trimmed := strings.TrimRight(fracPart[2:], "0")
+		if trimmed != "" {
+			return 0, fmt.Errorf("money: amount %q has more than 2 decimal places", s)
+		}
+		fracPart = fracPart[:2]
+	}
+	for len(fracPart) < 2 {
+		fracPart += "0"
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units < 0 {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	cents, err := strconv.ParseInt(fracPart, 10, 64)
+	if err != nil || cents < 0 {
+		return 0, fmt.Errorf("money: invalid amount %q", s)
+	}
+	if units > (math.MaxInt64-cents)/100 {
+		return 0, fmt.Errorf("money: amount %q out of range", s)
+	}
+
+	v := units*100 + cents
+	if neg {
+		v = -v
+	}
+	return Money(v), nil
+}
+
+// MustParse is like Parse but panics on error. Intended for constants.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Cents returns the amount as a number of cents.
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount as a float. Use it for display only, never for
+// further calculations.
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount with exactly two decimals, e.g. "1234.50".
+func (m Money) String() string {
+	v := int64(m)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// Abs returns the absolute amount.
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// MulQuantity multiplies the amount by a quantity with up to two decimals
+// (DECIMAL(10,2)), rounding half away from zero to the cent.
+func (m Money) MulQuantity(qty float64) Money {
+	return Money(mulDivRound(int64(m), scaled(qty, 100), 100))
+}
+
+// MulPercent returns pct percent of the amount, where pct has up to two
+// decimals (DECIMAL(5,2)), rounding half away from zero to the cent.
+func (m Money) MulPercent(pct float64) Money {
+	return Money(mulDivRound(int64(m), scaled(pct, 100), 100*100))
+}
+
+// MulRatio returns num/den of the amount, rounding half away from zero to the
+// cent. den must not be zero.
+func (m Money) MulRatio(num, den int64) Money {
+	return Money(mulDivRound(int64(m), num, den))
+}
+
+// Sum adds up amounts.
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// Min returns the smaller of two amounts.
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Max returns the larger of two amounts.
+func Max(a, b Money) Money {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. The literal text is
+// parsed directly, so no binary floating point rounding is involved.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*m = 0
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("money: invalid amount %q", s)
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		p, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*m = p
+		return nil
+	case string:
+		p, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*m = p
+		return nil
+	case int64:
+		*m = FromInt(v)
+		return nil
+	case float64:
+		*m = Money(math.Round(v * 100))
+		return nil
+	}
+	return fmt.Errorf("money: cannot scan %T", src)
+}
+
+// Value implements driver.Valuer, sending the amount as a decimal string.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// scaled converts a decimal quantity to an integer in 1/factor units.
+func scaled(v float64, factor float64) int64 {
+	return int64(math.Round(v * factor))
+}
+
+// mulDivRound computes a*b/d rounded half away from zero, using big integers
+// so the intermediate product cannot overflow.
+func mulDivRound(a, b, d int64) int64 {
+	num := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	den := big.NewInt(d)
+	neg := num.Sign()*den.Sign() < 0
+	num.Abs(num)
+	den.Abs(den)
+
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(r, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
@@ -0,0 +1,257 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "1234", want: 1234_00},
+		{in: "1234.5", want: 1234_50},
+		{in: "1234.56", want: 1234_56},
+		{in: "-0.25", want: -25},
+		{in: "+7.05", want: 7_05},
+		{in: ".5", want: 50},
+		{in: "12.", want: 12_00},
+		{in: " 42 ", want: 42_00},
+		{in: "1.2300", want: 1_23},
+		{in: "0", want: 0},
+		{in: "92233720368547758.07", want: math.MaxInt64},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.234", wantErr: true},
+		{in: "1.-5", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "1,5", wantErr: true},
+		{in: "92233720368547758.08", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %d, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{1234_50, "1234.50"},
+		{-1234_56, "-1234.56"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: `1234.56`, want: 1234_56},
+		{in: `"1234.56"`, want: 1234_56},
+		{in: `-10`, want: -10_00},
+		{in: `1e3`, want: 1000_00},
+		{in: `1.5E2`, want: 150_00},
+		{in: `"2.5e1"`, want: 25_00},
+		{in: `null`, want: 0},
+		{in: `12.345`, wantErr: true},
+		{in: `1e-3`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+		{in: `"1eX"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		m := Money(99)
+		err := m.UnmarshalJSON([]byte(tt.in))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("UnmarshalJSON(%s) = %d, want error", tt.in, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("UnmarshalJSON(%s): %v", tt.in, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %d, want %d", tt.in, m, tt.want)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	type doc struct {
+		Amount Money `json:"amount"`
+	}
+	data, err := json.Marshal(doc{Amount: -1234_05})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(data) != `{"amount":-1234.05}` {
+		t.Fatalf("Marshal = %s", data)
+	}
+	var got doc
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got.Amount != -1234_05 {
+		t.Errorf("round trip = %d, want %d", got.Amount, -1234_05)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "decimal bytes", src: []byte("1234.50"), want: 1234_50},
+		{name: "negative decimal bytes", src: []byte("-0.01"), want: -1},
+		{name: "zero decimal bytes", src: []byte("0.00"), want: 0},
+		{name: "string", src: "12.3", want: 12_30},
+		{name: "nil", src: nil, want: 0},
+		{name: "int64", src: int64(5), want: 5_00},
+		{name: "float64", src: float64(12.34), want: 12_34},
+		{name: "invalid bytes", src: []byte("1.234"), wantErr: true},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		m := Money(99)
+		err := m.Scan(tt.src)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: Scan = %d, want error", tt.name, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Scan: %v", tt.name, err)
+			continue
+		}
+		if m != tt.want {
+			t.Errorf("%s: Scan = %d, want %d", tt.name, m, tt.want)
+		}
+	}
+}
+
+func TestValue(t *testing.T) {
+	v, err := Money(-1234_50).Value()
+	if err != nil {
+		t.Fatalf("Value: %v", err)
+	}
+	if v != "-1234.50" {
+		t.Errorf("Value = %v, want -1234.50", v)
+	}
+}
+
+func TestMulPercent(t *testing.T) {
+	tests := []struct {
+		m    Money
+		pct  float64
+		want Money
+	}{
+		{1000, 11, 110},
+		{50, 1, 1},    // 0.5 cent rounds up
+		{-50, 1, -1},  // and away from zero when negative
+		{49, 1, 0},    // 0.49 cent rounds down
+		{100, 2.5, 3}, // 2.5 cents
+		{-100, 2.5, -3},
+		{12345, 12.5, 1543}, // 1543.125 cents
+		{1000_000_00, 0, 0},
+		{1000_000_00, 100, 1000_000_00},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulPercent(tt.pct); got != tt.want {
+			t.Errorf("Money(%d).MulPercent(%v) = %d, want %d", int64(tt.m), tt.pct, got, tt.want)
+		}
+	}
+}
+
+func TestMulQuantity(t *testing.T) {
+	tests := []struct {
+		m    Money
+		qty  float64
+		want Money
+	}{
+		{1_250_000_33, 3, 3_750_000_99},
+		{333, 1.5, 500}, // 499.5 cents
+		{-333, 1.5, -500},
+		{1, 0.49, 0},
+		{1, 0.5, 1},
+		{10, 1.15, 12}, // 1.15 is not exact in binary but is read as 115/100
+		{-10, 1.15, -12},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulQuantity(tt.qty); got != tt.want {
+			t.Errorf("Money(%d).MulQuantity(%v) = %d, want %d", int64(tt.m), tt.qty, got, tt.want)
+		}
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tests := []struct {
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{1200, 11, 12, 1100},
+		{100, 1, 3, 33}, // 33.33 cents
+		{200, 1, 3, 67}, // 66.67 cents
+		{-200, 1, 3, -67},
+		{5, 1, 2, 3}, // 2.5 cents
+		{-5, 1, 2, -3},
+		{5, 1, -2, -3},
+		{math.MaxInt64 / 2, 2, 2, math.MaxInt64 / 2}, // intermediate product overflows int64
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulRatio(tt.num, tt.den); got != tt.want {
+			t.Errorf("Money(%d).MulRatio(%d, %d) = %d, want %d", int64(tt.m), tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestSumMinMaxAbs(t *testing.T) {
+	if got := Sum(1_00, -25, 2_50); got != 3_25 {
+		t.Errorf("Sum = %d, want 325", got)
+	}
+	if got := Sum(); got != 0 {
+		t.Errorf("Sum() = %d, want 0", got)
+	}
+	if got := Min(-1, 1); got != -1 {
+		t.Errorf("Min = %d, want -1", got)
+	}
+	if got := Max(-1, 1); got != 1 {
+		t.Errorf("Max = %d, want 1", got)
+	}
+	if got := Money(-7).Abs(); got != 7 {
+		t.Errorf("Abs = %d, want 7", got)
+	}
+}
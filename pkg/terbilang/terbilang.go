@@ -0,0 +1,153 @@
+// Package terbilang spells out amounts in Indonesian and English words, as
+// written on receipts next to the figures.
+package terbilang
+
+import (
+	"strings"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+var (
+	onesID   = []string{"", "satu", "dua", "tiga", "empat", "lima", "enam", "tujuh", "delapan", "sembilan"}
+	scalesID = []string{"", "ribu", "juta", "miliar", "triliun", "kuadriliun", "kuintiliun"}
+
+	onesEN = []string{"", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen"}
+	tensEN   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	scalesEN = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+)
+
+// Rupiah spells out an amount in Indonesian, e.g. "sebelas juta seratus ribu
+// rupiah". Cents are added as "sen".
+func Rupiah(m money.Money) string {
+	whole, cents := split(m)
+	s := Indonesian(whole) + " rupiah"
+	if cents > 0 {
+		s += " " + Indonesian(cents) + " sen"
+	}
+	return minus(m) + s
+}
+
+// RupiahEnglish spells out an amount in English, e.g. "eleven million one
+// hundred thousand rupiah". Cents are added as "cents".
+func RupiahEnglish(m money.Money) string {
+	whole, cents := split(m)
+	s := English(whole) + " rupiah"
+	if cents > 0 {
+		s += " and " + English(cents) + " cents"
+	}
+	return minus(m) + s
+}
+
+// Indonesian spells out a non-negative whole number in Indonesian.
+func Indonesian(n int64) string {
+	if n == 0 {
+		return "nol"
+	}
+	var parts []string
+	for i, group := range groups(n) {
+		if group == 0 {
+			continue
+		}
+		scale := scalesID[i]
+		// 1000 is "seribu", not "satu ribu"
+		if i == 1 && group == 1 {
+			parts = append([]string{"seribu"}, parts...)
+			continue
+		}
+		words := hundredsID(group)
+		if scale != "" {
+			words += " " + scale
+		}
+		parts = append([]string{words}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// English spells out a non-negative whole number in English.
+func English(n int64) string {
+	if n == 0 {
+		return "zero"
+	}
+	var parts []string
+	for i, group := range groups(n) {
+		if group == 0 {
+			continue
+		}
+		words := hundredsEN(group)
+		if scalesEN[i] != "" {
+			words += " " + scalesEN[i]
+		}
+		parts = append([]string{words}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// groups splits n into groups of three digits, least significant first.
+func groups(n int64) []int {
+	var out []int
+	for n > 0 {
+		out = append(out, int(n%1000))
+		n /= 1000
+	}
+	return out
+}
+
+func hundredsID(n int) string {
+	var parts []string
+	h, rest := n/100, n%100
+	switch {
+	case h == 1:
+		parts = append(parts, "seratus")
+	case h > 1:
+		parts = append(parts, onesID[h]+" ratus")
+	}
+	switch {
+	case rest == 0:
+	case rest < 10:
+		parts = append(parts, onesID[rest])
+	case rest == 10:
+		parts = append(parts, "sepuluh")
+	case rest == 11:
+		parts = append(parts, "sebelas")
+	case rest < 20:
+		parts = append(parts, onesID[rest-10]+" belas")
+	default:
+		parts = append(parts, onesID[rest/10]+" puluh")
+		if rest%10 != 0 {
+			parts = append(parts, onesID[rest%10])
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func hundredsEN(n int) string {
+	var parts []string
+	h, rest := n/100, n%100
+	if h > 0 {
+		parts = append(parts, onesEN[h]+" hundred")
+	}
+	switch {
+	case rest == 0:
+	case rest < 20:
+		parts = append(parts, onesEN[rest])
+	case rest%10 == 0:
+		parts = append(parts, tensEN[rest/10])
+	default:
+		parts = append(parts, tensEN[rest/10]+"-"+onesEN[rest%10])
+	}
+	return strings.Join(parts, " ")
+}
+
+func split(m money.Money) (whole, cents int64) {
+	c := m.Abs().Cents()
+	return c / 100, c % 100
+}
+
+func minus(m money.Money) string {
+	if m < 0 {
+		return "minus "
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package terbilang
+
+import (
+	"testing"
+
+	"github.com/gilangrmdnii/invoice-backend/pkg/money"
+)
+
+func TestIndonesian(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "nol"},
+		{1, "satu"},
+		{10, "sepuluh"},
+		{11, "sebelas"},
+		{12, "dua belas"},
+		{19, "sembilan belas"},
+		{20, "dua puluh"},
+		{21, "dua puluh satu"},
+		{100, "seratus"},
+		{101, "seratus satu"},
+		{111, "seratus sebelas"},
+		{250, "dua ratus lima puluh"},
+		{1000, "seribu"},
+		{1001, "seribu satu"},
+		{1100, "seribu seratus"},
+		{2000, "dua ribu"},
+		{11_000, "sebelas ribu"},
+		{100_000, "seratus ribu"},
+		{101_000, "seratus satu ribu"},
+		{1_000_000, "satu juta"},
+		{1_001_000, "satu juta seribu"},
+		{11_100_000, "sebelas juta seratus ribu"},
+		{1_000_000_000, "satu miliar"},
+		{2_000_000_000_000, "dua triliun"},
+		{1_000_000_000_001, "satu triliun satu"},
+	}
+	for _, tt := range tests {
+		if got := Indonesian(tt.n); got != tt.want {
+			t.Errorf("Indonesian(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestEnglish(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{11, "eleven"},
+		{19, "nineteen"},
+		{20, "twenty"},
+		{42, "forty-two"},
+		{100, "one hundred"},
+		{115, "one hundred fifteen"},
+		{1000, "one thousand"},
+		{100_000, "one hundred thousand"},
+		{1_000_000, "one million"},
+		{11_100_000, "eleven million one hundred thousand"},
+		{1_000_000_001, "one billion one"},
+	}
+	for _, tt := range tests {
+		if got := English(tt.n); got != tt.want {
+			t.Errorf("English(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestRupiah(t *testing.T) {
+	tests := []struct {
+		m    money.Money
+		want string
+	}{
+		{0, "nol rupiah"},
+		{1000_00, "seribu rupiah"},
+		{11_100_000_00, "sebelas juta seratus ribu rupiah"},
+		{1_500_50, "seribu lima ratus rupiah lima puluh sen"},
+		{1_01, "satu rupiah satu sen"},
+		{-25_000_00, "minus dua puluh lima ribu rupiah"},
+	}
+	for _, tt := range tests {
+		if got := Rupiah(tt.m); got != tt.want {
+			t.Errorf("Rupiah(%s) = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestRupiahEnglish(t *testing.T) {
+	tests := []struct {
+		m    money.Money
+		want string
+	}{
+		{0, "zero rupiah"},
+		{11_100_000_00, "eleven million one hundred thousand rupiah"},
+		{1_500_50, "one thousand five hundred rupiah and fifty cents"},
+		{-21_05, "minus twenty-one rupiah and five cents"},
+	}
+	for _, tt := range tests {
+		if got := RupiahEnglish(tt.m); got != tt.want {
+			t.Errorf("RupiahEnglish(%s) = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
@@ -22,6 +22,19 @@ func ParseAndValidate(c *fiber.Ctx, out interface{}) error {
 	return nil
 }
 
+// ParseQueryAndValidate is ParseAndValidate for query string parameters.
+func ParseQueryAndValidate(c *fiber.Ctx, out interface{}) error {
+	if err := c.QueryParser(out); err != nil {
+		return fmt.Errorf("invalid query parameters")
+	}
+
+	if err := validate.Struct(out); err != nil {
+		return formatValidationErrors(err)
+	}
+
+	return nil
+}
+
 func formatValidationErrors(err error) error {
 	var messages []string
 